@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/config"
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/database"
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/migration"
+)
+
+const usage = `Penggunaan: migrate <perintah>
+
+Perintah:
+  up           menjalankan semua migrasi yang belum diterapkan
+  down N       membatalkan N migrasi terakhir (default 1)
+  status       menampilkan status setiap migrasi
+  force V      menandai database berada di versi V tanpa menjalankan SQL`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	// 1. MEMUAT KONFIGURASI & KONEKSI DATABASE
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal("FATAL: Gagal memuat konfigurasi: ", err)
+	}
+	if cfg.Storage != config.StoragePostgres {
+		log.Fatal("FATAL: Migrasi hanya berlaku untuk STORAGE=postgres")
+	}
+
+	db, err := database.NewDB(cfg.DBURL)
+	if err != nil {
+		log.Fatal("FATAL: Gagal terhubung ke database: ", err)
+	}
+	defer db.Close()
+
+	migrator, err := migration.New(db)
+	if err != nil {
+		log.Fatal("FATAL: Gagal memuat file migrasi: ", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	// 2. MENJALANKAN PERINTAH
+	switch os.Args[1] {
+	case "up":
+		n, err := migrator.Up(ctx)
+		if err != nil {
+			log.Fatal("FATAL: ", err)
+		}
+		log.Printf("%d migrasi diterapkan", n)
+
+	case "down":
+		steps := 1
+		if len(os.Args) > 2 {
+			steps, err = strconv.Atoi(os.Args[2])
+			if err != nil || steps < 1 {
+				log.Fatal("FATAL: jumlah langkah down harus bilangan bulat positif")
+			}
+		}
+		n, err := migrator.Down(ctx, steps)
+		if err != nil {
+			log.Fatal("FATAL: ", err)
+		}
+		log.Printf("%d migrasi dibatalkan", n)
+
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			log.Fatal("FATAL: ", err)
+		}
+		for _, s := range statuses {
+			state := "pending"
+			switch {
+			case s.Dirty:
+				state = "DIRTY"
+			case s.Applied:
+				state = "applied " + s.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%04d  %-40s %s\n", s.Version, s.Name, state)
+		}
+
+	case "force":
+		if len(os.Args) < 3 {
+			log.Fatal("FATAL: force membutuhkan nomor versi")
+		}
+		version, err := strconv.ParseInt(os.Args[2], 10, 64)
+		if err != nil || version < 0 {
+			log.Fatal("FATAL: versi harus bilangan bulat non-negatif")
+		}
+		if err := migrator.Force(ctx, version); err != nil {
+			log.Fatal("FATAL: ", err)
+		}
+		log.Printf("Database ditandai berada di versi %d", version)
+
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+}
@@ -2,14 +2,18 @@ package main
 
 import (
 	"context" // Tambahkan context
+	"database/sql"
 	"log"
 	"net/http"
 	"time"
 
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/autentikasi"
 	"github.com/Farewellez/REST-API_VarietasPadi/internal/config"
 	"github.com/Farewellez/REST-API_VarietasPadi/internal/database"
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
 	httpHandler "github.com/Farewellez/REST-API_VarietasPadi/internal/http"
 	"github.com/Farewellez/REST-API_VarietasPadi/internal/http/handler"
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/migration"
 	"github.com/Farewellez/REST-API_VarietasPadi/internal/repository"
 	"github.com/Farewellez/REST-API_VarietasPadi/internal/service"
 )
@@ -22,34 +26,65 @@ func main() {
 		log.Fatal("FATAL: Gagal memuat konfigurasi: ", err)
 	}
 
-	// 2. KONEKSI KE DATABASE (PostgreSQL/NeonDB)
-	// Menggunakan pola error handling yang benar dari database.NewDB()
-	db, err := database.NewDB(cfg.DBURL)
-	if err != nil {
-		log.Fatal("FATAL: Gagal terhubung ke database: ", err)
-	}
-	defer db.Close()
-	log.Println("Berhasil terhubung ke database!")
+	// Kategori panjang dan bentuk biji dihitung dari tabel ambang ini di semua lapisan
+	domain.AturKategoriBiji(cfg.AmbangPanjang, cfg.AmbangBentuk)
 
-	// Opsional: Cek lagi status DB sebelum start
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	if err := db.PingContext(ctx); err != nil {
-		log.Fatal("FATAL: Database ping gagal setelah koneksi: ", err)
+	// 2. MEMILIH PENYIMPANAN & INISIALISASI REPOSITORY
+	// STORAGE=memory: tanpa database (test/demo offline), selain itu PostgreSQL/NeonDB
+	var varietasRepo domain.VarietasRepository
+	var riwayatRepo domain.RiwayatRepository
+	var kosakataRepo domain.KosakataRepository
+	var txManager domain.TxManager
+	var kualitasRepo domain.KualitasRepository
+	var gabungRepo domain.PenggabunganRepository
+	if cfg.Storage == config.StorageMemory {
+		memRepo := repository.NewMemoryVarietasRepository()
+		memRiwayat := repository.NewMemoryRiwayatRepository()
+		varietasRepo = memRepo
+		riwayatRepo = memRiwayat
+		kosakataRepo = repository.NewMemoryKosakataRepository(memRepo)
+		txManager = repository.NewMemoryTxManager()
+		kualitasRepo = repository.NewMemoryKualitasRepository()
+		gabungRepo = repository.NewMemoryPenggabunganRepository()
+		log.Println("Menggunakan penyimpanan in-memory (data hilang saat server berhenti)")
+	} else {
+		db := connectPostgres(cfg)
+		defer db.Close()
+
+		// A. Inisialisasi Repository
+		varietasRepo = repository.NewVarietasRepository(db)
+		riwayatRepo = repository.NewRiwayatRepository(db)
+		kosakataRepo = repository.NewKosakataRepository(db)
+		txManager = repository.NewTxManager(db)
+		kualitasRepo = repository.NewKualitasRepository(db)
+		gabungRepo = repository.NewPenggabunganRepository(db)
 	}
 
 	// 3. WIRING UP (Inisialisasi Lapisan)
-	// A. Inisialisasi Repository
-	varietasRepo := repository.NewVarietasRepository(db)
-
 	// B. Inisialisasi Service (DI: Membutuhkan Repository Interface)
-	varietasService := service.NewVarietasService(varietasRepo)
+	varietasService := service.NewVarietasService(varietasRepo, riwayatRepo, kosakataRepo, txManager, kualitasRepo,
+		gabungRepo, cfg.ModeDuplikat)
+	kosakataService := service.NewKosakataService(kosakataRepo)
+
+	// Pembersih tempat sampah berjalan di background selama server hidup
+	if cfg.TrashRetention > 0 {
+		go purgeTrashPeriodically(varietasService, cfg.TrashRetention, time.Hour)
+	}
+
+	// Izin per peran (service.Kebijakan) hanya berlaku jika autentikasi aktif; pembersih
+	// tempat sampah di atas tetap memakai service tanpa kebijakan
+	auth := opsiAuth(cfg)
+	if auth.Verifier != nil {
+		varietasService = service.NewKebijakanVarietasService(varietasService)
+		kosakataService = service.NewKebijakanKosakataService(kosakataService)
+	}
 
 	// C. Inisialisasi Handler (DI: Membutuhkan Service Interface)
 	varietasHandler := handler.NewVarietasHandler(varietasService)
+	kosakataHandler := handler.NewKosakataHandler(kosakataService)
 
-	// D. Inisialisasi Router (Membutuhkan Handler)
-	router := httpHandler.NewRouter(varietasHandler)
+	// D. Inisialisasi Router (Membutuhkan Handler dan verifier token JWT)
+	router := httpHandler.NewRouter(varietasHandler, kosakataHandler, auth)
 
 	// 4. MENJALANKAN SERVER
 	srv := &http.Server{
@@ -65,3 +100,77 @@ func main() {
 	// Blok dan tunggu traffic masuk
 	log.Fatal(srv.ListenAndServe())
 }
+
+// opsiAuth memuat kunci verifikasi JWT. Kunci yang tidak bisa dibaca bersifat fatal agar
+// server tidak berjalan tanpa autentikasi karena salah konfigurasi.
+func opsiAuth(cfg config.Config) httpHandler.OpsiAuth {
+	if cfg.AuthDisabled {
+		log.Println("PERINGATAN: Autentikasi dimatikan (AUTH_DISABLED=true); semua client bisa mengubah data")
+		return httpHandler.OpsiAuth{}
+	}
+	verifier, err := autentikasi.NewVerifier(autentikasi.Opsi{
+		SecretHS256:     []byte(cfg.JWTSecret),
+		FileKunciPublik: cfg.JWTPublicKeyFile,
+		FileJWKS:        cfg.JWKSFile,
+		Issuer:          cfg.JWTIssuer,
+		Audience:        cfg.JWTAudience,
+	})
+	if err != nil {
+		log.Fatal("FATAL: Gagal memuat kunci JWT: ", err)
+	}
+	return httpHandler.OpsiAuth{Verifier: verifier, AnonimBaca: cfg.AnonymousRead}
+}
+
+// connectPostgres membuka koneksi PostgreSQL/NeonDB dan (opsional) menjalankan migrasi.
+// Gagal di sini bersifat fatal karena server tidak bisa berjalan tanpa database.
+func connectPostgres(cfg config.Config) *sql.DB {
+	// Menggunakan pola error handling yang benar dari database.NewDB()
+	db, err := database.NewDB(cfg.DBURL)
+	if err != nil {
+		log.Fatal("FATAL: Gagal terhubung ke database: ", err)
+	}
+	log.Println("Berhasil terhubung ke database!")
+
+	// Opsional: Cek lagi status DB sebelum start
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := db.PingContext(ctx); err != nil {
+		log.Fatal("FATAL: Database ping gagal setelah koneksi: ", err)
+	}
+
+	// Opsional: Jalankan migrasi skema sebelum melayani request (MIGRATE_ON_START=true)
+	if cfg.MigrateOnStart {
+		migrator, err := migration.New(db)
+		if err != nil {
+			log.Fatal("FATAL: Gagal memuat file migrasi: ", err)
+		}
+		migrateCtx, migrateCancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		n, err := migrator.Up(migrateCtx)
+		migrateCancel()
+		if err != nil {
+			log.Fatal("FATAL: Migrasi database gagal: ", err)
+		}
+		log.Printf("Migrasi database selesai (%d migrasi baru diterapkan)", n)
+	}
+
+	return db
+}
+
+// purgeTrashPeriodically menghapus permanen data di tempat sampah yang melewati masa retensi.
+// Dijalankan sekali saat start lalu setiap interval.
+func purgeTrashPeriodically(svc domain.VarietasService, retention, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		n, err := svc.BersihkanSampah(ctx, retention)
+		cancel()
+		if err != nil {
+			log.Printf("WARN: Gagal membersihkan tempat sampah: %v", err)
+		} else if n > 0 {
+			log.Printf("Tempat sampah dibersihkan: %d data dihapus permanen", n)
+		}
+		<-ticker.C
+	}
+}
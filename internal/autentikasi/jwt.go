@@ -0,0 +1,269 @@
+// Package autentikasi memverifikasi token JWT (HS256 dan RS256) yang dikirim client
+// sebagai Bearer token dan mengubahnya menjadi domain.Principal.
+package autentikasi
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
+)
+
+const (
+	// Kelonggaran selisih jam antara penerbit token dan server saat memeriksa exp/nbf
+	Kelonggaran = time.Minute
+	// MaksPanjangSubjek sama dengan batas kolom aktor di tabel riwayat
+	MaksPanjangSubjek = 100
+	// MaksPanjangToken menolak token yang jelas bukan JWT biasa sebelum di-decode
+	MaksPanjangToken = 8 << 10
+)
+
+// Opsi adalah sumber kunci dan klaim yang diwajibkan. Minimal satu sumber kunci harus diisi.
+type Opsi struct {
+	// SecretHS256 adalah shared secret untuk token HS256
+	SecretHS256 []byte
+	// FileKunciPublik adalah file PEM kunci publik RSA untuk token RS256
+	FileKunciPublik string
+	// FileJWKS adalah file JWKS lokal; kunci dipilih menurut kid token
+	FileJWKS string
+	// Issuer dan Audience, jika diisi, wajib sama dengan klaim iss dan aud token
+	Issuer   string
+	Audience string
+}
+
+// Verifier memeriksa tanda tangan dan klaim token JWT
+type Verifier struct {
+	kunci    []kunci
+	issuer   string
+	audience string
+	now      func() time.Time
+}
+
+// NewVerifier memuat semua kunci dari opsi. File kunci dibaca sekali saat startup.
+func NewVerifier(opsi Opsi) (*Verifier, error) {
+	v := &Verifier{issuer: opsi.Issuer, audience: opsi.Audience, now: time.Now}
+	if len(opsi.SecretHS256) > 0 {
+		k, err := kunciSecret("", opsi.SecretHS256)
+		if err != nil {
+			return nil, err
+		}
+		v.kunci = append(v.kunci, k)
+	}
+	if opsi.FileKunciPublik != "" {
+		publik, err := bacaPEM(opsi.FileKunciPublik)
+		if err != nil {
+			return nil, err
+		}
+		k, err := kunciRSA("", publik)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", opsi.FileKunciPublik, err)
+		}
+		v.kunci = append(v.kunci, k)
+	}
+	if opsi.FileJWKS != "" {
+		daftar, err := bacaJWKS(opsi.FileJWKS)
+		if err != nil {
+			return nil, err
+		}
+		v.kunci = append(v.kunci, daftar...)
+	}
+	if len(v.kunci) == 0 {
+		return nil, errors.New("tidak ada kunci verifikasi JWT")
+	}
+	return v, nil
+}
+
+// TokenError menjelaskan kenapa token ditolak.
+// errors.Is(err, domain.ErrUnauthorized) bernilai true untuk error ini.
+type TokenError struct {
+	Alasan string
+}
+
+func (e *TokenError) Error() string {
+	return "token tidak valid: " + e.Alasan
+}
+
+// Is membuat TokenError dikenali sebagai domain.ErrUnauthorized
+func (e *TokenError) Is(target error) bool {
+	return target == domain.ErrUnauthorized
+}
+
+func tolak(format string, args ...any) error {
+	return &TokenError{Alasan: fmt.Sprintf(format, args...)}
+}
+
+// header adalah bagian JOSE header token yang dipakai
+type header struct {
+	Alg  string   `json:"alg"`
+	Kid  string   `json:"kid"`
+	Crit []string `json:"crit"`
+}
+
+// klaim adalah klaim terdaftar (RFC 7519 §4.1) yang diperiksa
+type klaim struct {
+	Sub               string   `json:"sub"`
+	Iss               string   `json:"iss"`
+	Aud               daftar   `json:"aud"`
+	Exp               *float64 `json:"exp"`
+	Nbf               *float64 `json:"nbf"`
+	Name              string   `json:"name"`
+	PreferredUsername string   `json:"preferred_username"`
+	Roles             daftar   `json:"roles"`
+}
+
+// daftar menerima klaim berupa teks atau array teks (misalnya aud dan roles)
+type daftar []string
+
+func (a *daftar) UnmarshalJSON(b []byte) error {
+	if bytes.HasPrefix(bytes.TrimSpace(b), []byte("[")) {
+		return json.Unmarshal(b, (*[]string)(a))
+	}
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	*a = daftar{s}
+	return nil
+}
+
+// Verifikasi memeriksa tanda tangan token, masa berlaku (exp wajib, nbf), iss dan aud
+// (jika dikonfigurasi), lalu mengembalikan principal-nya. Error selalu *TokenError.
+func (v *Verifier) Verifikasi(token string) (domain.Principal, error) {
+	if len(token) > MaksPanjangToken {
+		return domain.Principal{}, tolak("token terlalu panjang")
+	}
+	bagian := strings.Split(token, ".")
+	if len(bagian) != 3 {
+		return domain.Principal{}, tolak("format token bukan JWS compact (header.payload.signature)")
+	}
+
+	var h header
+	if err := decodeBagian(bagian[0], &h); err != nil {
+		return domain.Principal{}, tolak("header tidak bisa dibaca")
+	}
+	// Hanya alg yang dikonfigurasi yang diterima; "none" dan alg lain selalu ditolak
+	if h.Alg != AlgHS256 && h.Alg != AlgRS256 {
+		return domain.Principal{}, tolak("algoritma '%s' tidak diterima", h.Alg)
+	}
+	if len(h.Crit) > 0 {
+		return domain.Principal{}, tolak("ekstensi header crit tidak didukung")
+	}
+	tandaTangan, err := base64.RawURLEncoding.DecodeString(bagian[2])
+	if err != nil {
+		return domain.Principal{}, tolak("tanda tangan tidak bisa dibaca")
+	}
+	if !v.cocok(h, []byte(bagian[0]+"."+bagian[1]), tandaTangan) {
+		return domain.Principal{}, tolak("tanda tangan tidak cocok")
+	}
+
+	// Payload baru dibaca setelah tanda tangan terbukti sah
+	var k klaim
+	if err := decodeBagian(bagian[1], &k); err != nil {
+		return domain.Principal{}, tolak("klaim tidak bisa dibaca")
+	}
+	var semua map[string]any
+	if err := decodeBagian(bagian[1], &semua); err != nil {
+		return domain.Principal{}, tolak("klaim tidak bisa dibaca")
+	}
+	if err := v.periksaKlaim(k); err != nil {
+		return domain.Principal{}, err
+	}
+
+	nama := k.Name
+	if nama == "" {
+		nama = k.PreferredUsername
+	}
+	return domain.Principal{Subjek: k.Sub, Nama: nama, Penerbit: k.Iss, Peran: peranDikenal(k.Roles), Klaim: semua}, nil
+}
+
+// peranDikenal mengambil peran yang dikenal dari klaim roles (tidak peka huruf besar/kecil)
+func peranDikenal(roles []string) []domain.Peran {
+	peran := []domain.Peran{}
+	for _, r := range roles {
+		p := domain.Peran(strings.ToLower(strings.TrimSpace(r)))
+		if slices.Contains(domain.SemuaPeran, p) && !slices.Contains(peran, p) {
+			peran = append(peran, p)
+		}
+	}
+	return peran
+}
+
+// cocok mencoba semua kunci dengan alg yang sama dan kid yang cocok (kunci tanpa kid
+// cocok dengan kid apa pun)
+func (v *Verifier) cocok(h header, isi, tandaTangan []byte) bool {
+	digest := sha256.Sum256(isi)
+	for _, k := range v.kunci {
+		if k.alg != h.Alg || (k.kid != "" && h.Kid != "" && k.kid != h.Kid) {
+			continue
+		}
+		switch k.alg {
+		case AlgHS256:
+			mac := hmac.New(sha256.New, k.secret)
+			mac.Write(isi)
+			if hmac.Equal(mac.Sum(nil), tandaTangan) {
+				return true
+			}
+		case AlgRS256:
+			if rsa.VerifyPKCS1v15(k.publik, crypto.SHA256, digest[:], tandaTangan) == nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// periksaKlaim memeriksa sub, exp, nbf, iss, dan aud
+func (v *Verifier) periksaKlaim(k klaim) error {
+	if strings.TrimSpace(k.Sub) == "" {
+		return tolak("klaim sub wajib diisi")
+	}
+	if utf8.RuneCountInString(k.Sub) > MaksPanjangSubjek {
+		return tolak("klaim sub maksimal %d karakter", MaksPanjangSubjek)
+	}
+
+	now := v.now()
+	if k.Exp == nil {
+		return tolak("klaim exp wajib diisi")
+	}
+	if now.After(waktuKlaim(*k.Exp).Add(Kelonggaran)) {
+		return tolak("token sudah kedaluwarsa")
+	}
+	if k.Nbf != nil && now.Add(Kelonggaran).Before(waktuKlaim(*k.Nbf)) {
+		return tolak("token belum berlaku")
+	}
+
+	if v.issuer != "" && k.Iss != v.issuer {
+		return tolak("penerbit (iss) tidak dikenal")
+	}
+	if v.audience != "" && !slices.Contains(k.Aud, v.audience) {
+		return tolak("token bukan untuk layanan ini (aud)")
+	}
+	return nil
+}
+
+// waktuKlaim mengubah NumericDate (detik sejak epoch, boleh pecahan) menjadi time.Time
+func waktuKlaim(detik float64) time.Time {
+	utuh, pecahan := math.Modf(detik)
+	return time.Unix(int64(utuh), int64(pecahan*1e9))
+}
+
+// decodeBagian men-decode satu bagian base64url token lalu JSON-nya
+func decodeBagian(s string, v any) error {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
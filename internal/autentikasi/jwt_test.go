@@ -0,0 +1,353 @@
+package autentikasi
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
+)
+
+var (
+	secretUji   = []byte("rahasia-yang-panjangnya-minimal-32-byte!")
+	sekarangUji = time.Date(2025, 10, 17, 8, 0, 0, 0, time.UTC)
+)
+
+// kunciUji membuat kunci RSA sekali saja karena pembuatannya lambat
+var kunciUji = sync.OnceValue(func() [2]*rsa.PrivateKey {
+	var k [2]*rsa.PrivateKey
+	for i := range k {
+		var err error
+		if k[i], err = rsa.GenerateKey(rand.Reader, 2048); err != nil {
+			panic(err)
+		}
+	}
+	return k
+})
+
+func b64(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+
+// tokenUji menyusun JWS compact dari header dan klaim; tanda tangan dibuat oleh sign
+func tokenUji(t *testing.T, h, k map[string]any, sign func(isi []byte) []byte) string {
+	t.Helper()
+	hj, err := json.Marshal(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kj, err := json.Marshal(k)
+	if err != nil {
+		t.Fatal(err)
+	}
+	isi := b64(hj) + "." + b64(kj)
+	return isi + "." + b64(sign([]byte(isi)))
+}
+
+func signHS(secret []byte) func([]byte) []byte {
+	return func(isi []byte) []byte {
+		mac := hmac.New(sha256.New, secret)
+		mac.Write(isi)
+		return mac.Sum(nil)
+	}
+}
+
+func signRS(k *rsa.PrivateKey) func([]byte) []byte {
+	return func(isi []byte) []byte {
+		digest := sha256.Sum256(isi)
+		sig, err := rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, digest[:])
+		if err != nil {
+			panic(err)
+		}
+		return sig
+	}
+}
+
+// klaimUji adalah klaim sah yang bisa diubah per kasus
+func klaimUji(ubah map[string]any) map[string]any {
+	k := map[string]any{
+		"sub":   "tek1",
+		"iss":   "https://sso.lab.example",
+		"aud":   []string{"varietas-api"},
+		"exp":   sekarangUji.Add(time.Hour).Unix(),
+		"roles": []string{"Technician", "unknown"},
+	}
+	for key, v := range ubah {
+		if v == nil {
+			delete(k, key)
+			continue
+		}
+		k[key] = v
+	}
+	return k
+}
+
+func tulisFile(t *testing.T, nama string, isi []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), nama)
+	if err := os.WriteFile(path, isi, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func pemPublik(t *testing.T, publik *rsa.PublicKey) []byte {
+	t.Helper()
+	der, err := x509.MarshalPKIXPublicKey(publik)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
+
+func jwkRSA(kid string, publik *rsa.PublicKey) map[string]any {
+	return map[string]any{
+		"kty": "RSA", "kid": kid, "alg": AlgRS256, "use": "sig",
+		"n": b64(publik.N.Bytes()), "e": b64(big.NewInt(int64(publik.E)).Bytes()),
+	}
+}
+
+func jwks(t *testing.T, keys ...map[string]any) string {
+	t.Helper()
+	raw, err := json.Marshal(map[string]any{"keys": keys})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tulisFile(t, "jwks.json", raw)
+}
+
+func verifierUji(t *testing.T, opsi Opsi) *Verifier {
+	t.Helper()
+	v, err := NewVerifier(opsi)
+	if err != nil {
+		t.Fatalf("NewVerifier: %v", err)
+	}
+	v.now = func() time.Time { return sekarangUji }
+	return v
+}
+
+func TestVerifikasi(t *testing.T) {
+	rsaA, rsaB := kunciUji()[0], kunciUji()[1]
+	pemA := pemPublik(t, &rsaA.PublicKey)
+
+	hs := verifierUji(t, Opsi{SecretHS256: secretUji, Issuer: "https://sso.lab.example", Audience: "varietas-api"})
+	rs := verifierUji(t, Opsi{FileKunciPublik: tulisFile(t, "publik.pem", pemA)})
+	set := verifierUji(t, Opsi{FileJWKS: jwks(t, jwkRSA("kunci-a", &rsaA.PublicKey), jwkRSA("kunci-b", &rsaB.PublicKey))})
+
+	hdr := func(alg, kid string) map[string]any {
+		h := map[string]any{"alg": alg, "typ": "JWT"}
+		if kid != "" {
+			h["kid"] = kid
+		}
+		return h
+	}
+	batas := sekarangUji.Add(-Kelonggaran)
+
+	tests := []struct {
+		nama  string
+		v     *Verifier
+		token func(t *testing.T) string
+		galat string // potongan alasan penolakan; kosong berarti token diterima
+	}{
+		{"HS256 sah", hs, func(t *testing.T) string {
+			return tokenUji(t, hdr(AlgHS256, ""), klaimUji(nil), signHS(secretUji))
+		}, ""},
+		{"RS256 sah dengan kunci PEM", rs, func(t *testing.T) string {
+			return tokenUji(t, hdr(AlgRS256, ""), klaimUji(nil), signRS(rsaA))
+		}, ""},
+
+		// Kebingungan algoritma
+		{"alg none", hs, func(t *testing.T) string {
+			return tokenUji(t, hdr("none", ""), klaimUji(nil), func([]byte) []byte { return nil })
+		}, "algoritma 'none'"},
+		{"alg None huruf besar", hs, func(t *testing.T) string {
+			return tokenUji(t, hdr("None", ""), klaimUji(nil), func([]byte) []byte { return nil })
+		}, "algoritma 'None'"},
+		{"alg HS512", hs, func(t *testing.T) string {
+			return tokenUji(t, hdr("HS512", ""), klaimUji(nil), signHS(secretUji))
+		}, "algoritma 'HS512'"},
+		{"HS256 ditandatangani dengan kunci publik RSA", rs, func(t *testing.T) string {
+			return tokenUji(t, hdr(AlgHS256, ""), klaimUji(nil), signHS(pemA))
+		}, "tanda tangan tidak cocok"},
+		{"RS256 untuk verifier yang hanya punya secret HS", hs, func(t *testing.T) string {
+			return tokenUji(t, hdr(AlgRS256, ""), klaimUji(nil), signRS(rsaA))
+		}, "tanda tangan tidak cocok"},
+
+		// Tanda tangan dan format
+		{"payload diubah", hs, func(t *testing.T) string {
+			asli := tokenUji(t, hdr(AlgHS256, ""), klaimUji(nil), signHS(secretUji))
+			lain := tokenUji(t, hdr(AlgHS256, ""), klaimUji(map[string]any{"roles": []string{"admin"}}), signHS([]byte("secret-lain-yang-juga-panjangnya-32-byte")))
+			a, l := strings.Split(asli, "."), strings.Split(lain, ".")
+			return a[0] + "." + l[1] + "." + a[2]
+		}, "tanda tangan tidak cocok"},
+		{"tanda tangan diubah satu bit", hs, func(t *testing.T) string {
+			return tokenUji(t, hdr(AlgHS256, ""), klaimUji(nil), func(isi []byte) []byte {
+				sig := signHS(secretUji)(isi)
+				sig[0] ^= 1
+				return sig
+			})
+		}, "tanda tangan tidak cocok"},
+		{"secret salah", hs, func(t *testing.T) string {
+			return tokenUji(t, hdr(AlgHS256, ""), klaimUji(nil), signHS([]byte("secret-lain-yang-juga-panjangnya-32-byte")))
+		}, "tanda tangan tidak cocok"},
+		{"tanpa tanda tangan", hs, func(t *testing.T) string {
+			tok := tokenUji(t, hdr(AlgHS256, ""), klaimUji(nil), signHS(secretUji))
+			return tok[:strings.LastIndex(tok, ".")+1]
+		}, "tanda tangan tidak cocok"},
+		{"bukan JWS compact", hs, func(t *testing.T) string { return "a.b" }, "format token"},
+		{"token terlalu panjang", hs, func(t *testing.T) string { return strings.Repeat("a", MaksPanjangToken+1) }, "terlalu panjang"},
+		{"header crit", hs, func(t *testing.T) string {
+			h := hdr(AlgHS256, "")
+			h["crit"] = []string{"b64"}
+			return tokenUji(t, h, klaimUji(nil), signHS(secretUji))
+		}, "crit"},
+
+		// Masa berlaku dan kelonggaran jam
+		{"tanpa exp", hs, func(t *testing.T) string {
+			return tokenUji(t, hdr(AlgHS256, ""), klaimUji(map[string]any{"exp": nil}), signHS(secretUji))
+		}, "exp wajib"},
+		{"exp tepat di batas kelonggaran", hs, func(t *testing.T) string {
+			return tokenUji(t, hdr(AlgHS256, ""), klaimUji(map[string]any{"exp": batas.Unix()}), signHS(secretUji))
+		}, ""},
+		{"exp satu detik melewati kelonggaran", hs, func(t *testing.T) string {
+			return tokenUji(t, hdr(AlgHS256, ""), klaimUji(map[string]any{"exp": batas.Add(-time.Second).Unix()}), signHS(secretUji))
+		}, "kedaluwarsa"},
+		{"exp pecahan detik melewati kelonggaran", hs, func(t *testing.T) string {
+			return tokenUji(t, hdr(AlgHS256, ""), klaimUji(map[string]any{"exp": float64(batas.Unix()) - 0.5}), signHS(secretUji))
+		}, "kedaluwarsa"},
+		{"nbf tepat di batas kelonggaran", hs, func(t *testing.T) string {
+			return tokenUji(t, hdr(AlgHS256, ""), klaimUji(map[string]any{"nbf": sekarangUji.Add(Kelonggaran).Unix()}), signHS(secretUji))
+		}, ""},
+		{"nbf di masa depan", hs, func(t *testing.T) string {
+			return tokenUji(t, hdr(AlgHS256, ""), klaimUji(map[string]any{"nbf": sekarangUji.Add(Kelonggaran + time.Second).Unix()}), signHS(secretUji))
+		}, "belum berlaku"},
+
+		// Klaim lain
+		{"iss salah", hs, func(t *testing.T) string {
+			return tokenUji(t, hdr(AlgHS256, ""), klaimUji(map[string]any{"iss": "https://sso.lain.example"}), signHS(secretUji))
+		}, "iss"},
+		{"tanpa iss", hs, func(t *testing.T) string {
+			return tokenUji(t, hdr(AlgHS256, ""), klaimUji(map[string]any{"iss": nil}), signHS(secretUji))
+		}, "iss"},
+		{"aud salah", hs, func(t *testing.T) string {
+			return tokenUji(t, hdr(AlgHS256, ""), klaimUji(map[string]any{"aud": []string{"layanan-lain"}}), signHS(secretUji))
+		}, "aud"},
+		{"aud berupa teks", hs, func(t *testing.T) string {
+			return tokenUji(t, hdr(AlgHS256, ""), klaimUji(map[string]any{"aud": "varietas-api"}), signHS(secretUji))
+		}, ""},
+		{"tanpa sub", hs, func(t *testing.T) string {
+			return tokenUji(t, hdr(AlgHS256, ""), klaimUji(map[string]any{"sub": nil}), signHS(secretUji))
+		}, "sub wajib"},
+		{"sub terlalu panjang", hs, func(t *testing.T) string {
+			return tokenUji(t, hdr(AlgHS256, ""), klaimUji(map[string]any{"sub": strings.Repeat("x", MaksPanjangSubjek+1)}), signHS(secretUji))
+		}, "sub maksimal"},
+
+		// Pemilihan kunci JWKS menurut kid
+		{"JWKS kid kunci-a", set, func(t *testing.T) string {
+			return tokenUji(t, hdr(AlgRS256, "kunci-a"), klaimUji(nil), signRS(rsaA))
+		}, ""},
+		{"JWKS kid kunci-b", set, func(t *testing.T) string {
+			return tokenUji(t, hdr(AlgRS256, "kunci-b"), klaimUji(nil), signRS(rsaB))
+		}, ""},
+		{"JWKS kid menunjuk kunci lain", set, func(t *testing.T) string {
+			return tokenUji(t, hdr(AlgRS256, "kunci-a"), klaimUji(nil), signRS(rsaB))
+		}, "tanda tangan tidak cocok"},
+		{"JWKS kid tidak dikenal", set, func(t *testing.T) string {
+			return tokenUji(t, hdr(AlgRS256, "kunci-c"), klaimUji(nil), signRS(rsaA))
+		}, "tanda tangan tidak cocok"},
+		{"JWKS tanpa kid mencoba semua kunci", set, func(t *testing.T) string {
+			return tokenUji(t, hdr(AlgRS256, ""), klaimUji(nil), signRS(rsaB))
+		}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.nama, func(t *testing.T) {
+			p, err := tt.v.Verifikasi(tt.token(t))
+			if tt.galat == "" {
+				if err != nil {
+					t.Fatalf("token ditolak: %v", err)
+				}
+				if p.Subjek != "tek1" || !reflect.DeepEqual(p.Peran, []domain.Peran{domain.PeranTechnician}) {
+					t.Errorf("principal = %+v", p)
+				}
+				return
+			}
+			var tokenErr *TokenError
+			if !errors.As(err, &tokenErr) || !errors.Is(err, domain.ErrUnauthorized) {
+				t.Fatalf("error = %v, ingin *TokenError (ErrUnauthorized)", err)
+			}
+			if !strings.Contains(tokenErr.Alasan, tt.galat) {
+				t.Errorf("alasan = %q, ingin memuat %q", tokenErr.Alasan, tt.galat)
+			}
+		})
+	}
+}
+
+func TestNewVerifierKunciLemah(t *testing.T) {
+	lemah, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kuat := kunciUji()[0]
+
+	tests := []struct {
+		nama  string
+		opsi  func(t *testing.T) Opsi
+		galat string
+	}{
+		{"tanpa kunci", func(t *testing.T) Opsi { return Opsi{} }, "tidak ada kunci"},
+		{"secret 31 byte", func(t *testing.T) Opsi {
+			return Opsi{SecretHS256: []byte(strings.Repeat("s", MinPanjangSecret-1))}
+		}, "minimal 32 byte"},
+		{"secret 32 byte", func(t *testing.T) Opsi {
+			return Opsi{SecretHS256: []byte(strings.Repeat("s", MinPanjangSecret))}
+		}, ""},
+		{"PEM RSA 1024 bit", func(t *testing.T) Opsi {
+			return Opsi{FileKunciPublik: tulisFile(t, "lemah.pem", pemPublik(t, &lemah.PublicKey))}
+		}, "minimal 2048 bit"},
+		{"PEM RSA 2048 bit", func(t *testing.T) Opsi {
+			return Opsi{FileKunciPublik: tulisFile(t, "kuat.pem", pemPublik(t, &kuat.PublicKey))}
+		}, ""},
+		{"PEM bukan RSA", func(t *testing.T) Opsi {
+			return Opsi{FileKunciPublik: tulisFile(t, "bukan.pem", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte{1}}))}
+		}, "tidak didukung"},
+		{"JWKS RSA 1024 bit", func(t *testing.T) Opsi {
+			return Opsi{FileJWKS: jwks(t, jwkRSA("lemah", &lemah.PublicKey))}
+		}, "minimal 2048 bit"},
+		{"JWKS oct 31 byte", func(t *testing.T) Opsi {
+			return Opsi{FileJWKS: jwks(t, map[string]any{"kty": "oct", "kid": "s", "k": b64([]byte(strings.Repeat("s", MinPanjangSecret-1)))})}
+		}, "minimal 32 byte"},
+		{"JWKS tanpa kunci tanda tangan", func(t *testing.T) Opsi {
+			k := jwkRSA("enc", &kuat.PublicKey)
+			k["use"] = "enc"
+			return Opsi{FileJWKS: jwks(t, k)}
+		}, "tidak berisi kunci"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nama, func(t *testing.T) {
+			_, err := NewVerifier(tt.opsi(t))
+			if tt.galat == "" {
+				if err != nil {
+					t.Fatalf("NewVerifier: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.galat) {
+				t.Fatalf("error = %v, ingin memuat %q", err, tt.galat)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package autentikasi
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+)
+
+// Algoritma tanda tangan yang diterima
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+)
+
+// MinPanjangSecret adalah panjang minimal secret HS256 (RFC 7518 §3.2: minimal sebesar
+// keluaran hash, 256 bit)
+const MinPanjangSecret = 32
+
+// kunci adalah satu kunci verifikasi. Kid kosong berarti kunci dicoba untuk token
+// tanpa kid maupun dengan kid apa pun.
+type kunci struct {
+	kid    string
+	alg    string
+	secret []byte
+	publik *rsa.PublicKey
+}
+
+// kunciSecret membuat kunci HS256 dari shared secret
+func kunciSecret(kid string, secret []byte) (kunci, error) {
+	if len(secret) < MinPanjangSecret {
+		return kunci{}, fmt.Errorf("secret HS256 minimal %d byte", MinPanjangSecret)
+	}
+	return kunci{kid: kid, alg: AlgHS256, secret: secret}, nil
+}
+
+// kunciRSA membuat kunci RS256 dari kunci publik RSA
+func kunciRSA(kid string, publik *rsa.PublicKey) (kunci, error) {
+	if publik.N.BitLen() < 2048 {
+		return kunci{}, errors.New("kunci RSA minimal 2048 bit")
+	}
+	return kunci{kid: kid, alg: AlgRS256, publik: publik}, nil
+}
+
+// bacaPEM membaca kunci publik RSA dari file PEM ("PUBLIC KEY", "RSA PUBLIC KEY", atau
+// sertifikat X.509)
+func bacaPEM(path string) (*rsa.PublicKey, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	blok, _ := pem.Decode(raw)
+	if blok == nil {
+		return nil, fmt.Errorf("%s bukan file PEM", path)
+	}
+
+	var publik any
+	switch blok.Type {
+	case "PUBLIC KEY":
+		publik, err = x509.ParsePKIXPublicKey(blok.Bytes)
+	case "RSA PUBLIC KEY":
+		publik, err = x509.ParsePKCS1PublicKey(blok.Bytes)
+	case "CERTIFICATE":
+		var cert *x509.Certificate
+		if cert, err = x509.ParseCertificate(blok.Bytes); err == nil {
+			publik = cert.PublicKey
+		}
+	default:
+		return nil, fmt.Errorf("%s: blok PEM '%s' tidak didukung", path, blok.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	rsaPublik, ok := publik.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("%s bukan kunci publik RSA", path)
+	}
+	return rsaPublik, nil
+}
+
+// jwk adalah satu kunci di file JWKS (RFC 7517). Hanya kty RSA dan oct yang dipakai.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	K   string `json:"k"`
+}
+
+// bacaJWKS membaca semua kunci tanda tangan dari file JWKS lokal. Kunci dengan use selain
+// "sig" atau alg selain HS256/RS256 dilewati.
+func bacaJWKS(path string) ([]kunci, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(raw, &set); err != nil {
+		return nil, fmt.Errorf("%s bukan JWKS yang valid: %w", path, err)
+	}
+
+	var daftar []kunci
+	for i, j := range set.Keys {
+		if j.Use != "" && j.Use != "sig" {
+			continue
+		}
+		var k kunci
+		switch {
+		case j.Kty == "RSA" && (j.Alg == "" || j.Alg == AlgRS256):
+			publik, err := rsaDariJWK(j)
+			if err == nil {
+				k, err = kunciRSA(j.Kid, publik)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("%s: kunci ke-%d: %w", path, i, err)
+			}
+		case j.Kty == "oct" && (j.Alg == "" || j.Alg == AlgHS256):
+			secret, err := base64.RawURLEncoding.DecodeString(j.K)
+			if err == nil {
+				k, err = kunciSecret(j.Kid, secret)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("%s: kunci ke-%d: %w", path, i, err)
+			}
+		default:
+			continue
+		}
+		daftar = append(daftar, k)
+	}
+	if len(daftar) == 0 {
+		return nil, fmt.Errorf("%s tidak berisi kunci HS256 atau RS256", path)
+	}
+	return daftar, nil
+}
+
+// rsaDariJWK menyusun kunci publik RSA dari modulus (n) dan eksponen (e) base64url
+func rsaDariJWK(j jwk) (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(j.N)
+	if err != nil || len(n) == 0 {
+		return nil, errors.New("modulus 'n' tidak valid")
+	}
+	e, err := base64.RawURLEncoding.DecodeString(j.E)
+	if err != nil || len(e) == 0 || len(e) > 4 {
+		return nil, errors.New("eksponen 'e' tidak valid")
+	}
+	eksponen := new(big.Int).SetBytes(e)
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(eksponen.Int64())}, nil
+}
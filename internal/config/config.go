@@ -2,15 +2,59 @@ package config
 
 import (
 	"errors" // Import untuk mengembalikan error
+	"fmt"
 	"os"
+	"strconv"
+	"time"
 
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
 	"github.com/joho/godotenv"
 )
 
+// Jenis penyimpanan yang didukung
+const (
+	StoragePostgres = "postgres"
+	StorageMemory   = "memory"
+)
+
 // Config adalah struct konfigurasi aplikasi
 type Config struct {
-	DBURL string // connection string ke Neon PostgreSQL
-	Port  string // port server (default 8080)
+	DBURL   string // connection string ke Neon PostgreSQL
+	Port    string // port server (default 8080)
+	Storage string // jenis penyimpanan: "postgres" (default) atau "memory"
+
+	// MigrateOnStart menjalankan migrasi 'up' sebelum server mulai (env MIGRATE_ON_START=true)
+	MigrateOnStart bool
+
+	// TrashRetention adalah lama data soft delete disimpan di tempat sampah sebelum
+	// dihapus permanen oleh pembersih otomatis (env TRASH_RETENTION, default 720h = 30 hari).
+	// Nilai 0 mematikan pembersih otomatis.
+	TrashRetention time.Duration
+
+	// AmbangPanjang dan AmbangBentuk adalah tabel ambang kategori panjang biji dan bentuk
+	// biji (rasio panjang/lebar), env KATEGORI_PANJANG dan KATEGORI_BENTUK_BIJI dengan format
+	// "sangat_panjang>7.5,panjang>6.6,sedang>5.5,pendek". Default: standar IRRI.
+	AmbangPanjang domain.TabelAmbang
+	AmbangBentuk  domain.TabelAmbang
+
+	// ModeDuplikat adalah tindakan saat data baru kemungkinan duplikat data yang sudah ada
+	// (env DUPLIKAT_SAAT_CREATE): "warn" (default), "reject", atau "off"
+	ModeDuplikat domain.ModeDuplikat
+
+	// Autentikasi JWT. Minimal satu sumber kunci wajib diisi kecuali AuthDisabled:
+	// JWTSecret (env JWT_HS256_SECRET, minimal 32 byte) untuk HS256, JWTPublicKeyFile
+	// (env JWT_RS256_PUBLIC_KEY_FILE, PEM) untuk RS256, dan/atau JWKSFile (env JWT_JWKS_FILE).
+	JWTSecret        string
+	JWTPublicKeyFile string
+	JWKSFile         string
+	// JWTIssuer dan JWTAudience (env JWT_ISSUER, JWT_AUDIENCE) opsional; jika diisi wajib cocok
+	JWTIssuer   string
+	JWTAudience string
+	// AuthDisabled mematikan autentikasi untuk pengembangan lokal (env AUTH_DISABLED=true);
+	// pelaku perubahan kembali diambil dari header X-Actor
+	AuthDisabled bool
+	// AnonymousRead mengizinkan GET tanpa token (env AUTH_ANONYMOUS_READ, default true)
+	AnonymousRead bool
 }
 
 // Load membaca konfigurasi dari environment variable atau .env
@@ -20,8 +64,18 @@ func Load() (Config, error) { // Mengubah Load agar mengembalikan (Config, error
 	// Kita abaikan error godotenv.Load() karena tidak wajib ada
 	_ = godotenv.Load()
 
+	// STORAGE=memory menjalankan service tanpa database (untuk test dan demo offline)
+	storage := os.Getenv("STORAGE")
+	if storage == "" {
+		storage = StoragePostgres
+	}
+	if storage != StoragePostgres && storage != StorageMemory {
+		return Config{}, errors.New("STORAGE harus bernilai 'postgres' atau 'memory'")
+	}
+
+	// DB_URL hanya wajib jika penyimpanan memakai PostgreSQL
 	dbURL := os.Getenv("DB_URL")
-	if dbURL == "" {
+	if dbURL == "" && storage == StoragePostgres {
 		// Mengembalikan error, membiarkan main.go yang handle log.Fatal
 		return Config{}, errors.New("DB_URL tidak ditemukan! Harap set environment variable atau file .env")
 	}
@@ -32,8 +86,96 @@ func Load() (Config, error) { // Mengubah Load agar mengembalikan (Config, error
 		port = "8080" // default
 	}
 
+	// Migrasi otomatis saat startup bersifat opsional (default: false)
+	migrateOnStart, err := boolEnv("MIGRATE_ON_START", false)
+	if err != nil {
+		return Config{}, err
+	}
+
+	// Masa retensi tempat sampah dalam format durasi Go, misalnya "720h" atau "0" (nonaktif)
+	trashRetention := 30 * 24 * time.Hour
+	if raw := os.Getenv("TRASH_RETENTION"); raw != "" {
+		v, err := time.ParseDuration(raw)
+		if err != nil || v < 0 {
+			return Config{}, errors.New("TRASH_RETENTION harus durasi positif, misalnya 720h, atau 0 untuk menonaktifkan")
+		}
+		trashRetention = v
+	}
+
+	ambangPanjang, err := tabelAmbangEnv("KATEGORI_PANJANG", domain.AmbangPanjangIRRI)
+	if err != nil {
+		return Config{}, err
+	}
+	ambangBentuk, err := tabelAmbangEnv("KATEGORI_BENTUK_BIJI", domain.AmbangBentukIRRI)
+	if err != nil {
+		return Config{}, err
+	}
+
+	modeDuplikat := domain.ModeDuplikatPeringatan
+	if raw := os.Getenv("DUPLIKAT_SAAT_CREATE"); raw != "" {
+		if modeDuplikat, err = domain.ParseModeDuplikat(raw); err != nil {
+			return Config{}, fmt.Errorf("DUPLIKAT_SAAT_CREATE tidak valid: %w", err)
+		}
+	}
+
+	authDisabled, err := boolEnv("AUTH_DISABLED", false)
+	if err != nil {
+		return Config{}, err
+	}
+	anonymousRead, err := boolEnv("AUTH_ANONYMOUS_READ", true)
+	if err != nil {
+		return Config{}, err
+	}
+	jwtSecret := os.Getenv("JWT_HS256_SECRET")
+	jwtPublicKeyFile := os.Getenv("JWT_RS256_PUBLIC_KEY_FILE")
+	jwksFile := os.Getenv("JWT_JWKS_FILE")
+	if !authDisabled && jwtSecret == "" && jwtPublicKeyFile == "" && jwksFile == "" {
+		return Config{}, errors.New("kunci JWT belum diatur: isi JWT_HS256_SECRET, JWT_RS256_PUBLIC_KEY_FILE, " +
+			"atau JWT_JWKS_FILE (atau AUTH_DISABLED=true untuk pengembangan lokal)")
+	}
+
 	return Config{
-		DBURL: dbURL,
-		Port:  port,
+		DBURL:          dbURL,
+		Port:           port,
+		Storage:        storage,
+		MigrateOnStart: migrateOnStart,
+		TrashRetention: trashRetention,
+		AmbangPanjang:  ambangPanjang,
+		AmbangBentuk:   ambangBentuk,
+		ModeDuplikat:   modeDuplikat,
+
+		JWTSecret:        jwtSecret,
+		JWTPublicKeyFile: jwtPublicKeyFile,
+		JWKSFile:         jwksFile,
+		JWTIssuer:        os.Getenv("JWT_ISSUER"),
+		JWTAudience:      os.Getenv("JWT_AUDIENCE"),
+		AuthDisabled:     authDisabled,
+		AnonymousRead:    anonymousRead,
 	}, nil // Mengembalikan nil (tidak ada error)
 }
+
+// tabelAmbangEnv membaca tabel ambang dari env, atau bawaan jika env kosong
+func tabelAmbangEnv(name string, bawaan domain.TabelAmbang) (domain.TabelAmbang, error) {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return bawaan, nil
+	}
+	t, err := domain.ParseTabelAmbang(raw)
+	if err != nil {
+		return domain.TabelAmbang{}, fmt.Errorf("%s tidak valid: %w", name, err)
+	}
+	return t, nil
+}
+
+// boolEnv membaca env bernilai true/false, atau bawaan jika env kosong
+func boolEnv(name string, bawaan bool) (bool, error) {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return bawaan, nil
+	}
+	v, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, fmt.Errorf("%s harus bernilai true atau false", name)
+	}
+	return v, nil
+}
@@ -0,0 +1,41 @@
+// internal/domain/batch.go
+package domain
+
+// Status hasil satu operasi batch
+const (
+	StatusBerhasil   = "berhasil"
+	StatusGagal      = "gagal"
+	StatusDibatalkan = "dibatalkan" // tidak dijalankan atau di-rollback karena operasi lain gagal (atomic)
+)
+
+// OperasiBatch adalah satu operasi di dalam batch. Op berisi OperasiCreate, OperasiUpdate,
+// atau OperasiDelete. Ref adalah referensi bebas dari client (misalnya ID lokal di aplikasi
+// mobile) yang dikembalikan apa adanya di hasil.
+type OperasiBatch struct {
+	Op    string        `json:"op"`
+	Ref   string        `json:"ref,omitempty"`
+	ID    int           `json:"id,omitempty"`    // wajib untuk update dan delete
+	Versi int           `json:"versi,omitempty"` // versi yang diharapkan (0 = tanpa syarat)
+	Data  *VarietasPadi `json:"data,omitempty"`  // wajib untuk create dan update
+}
+
+// HasilOperasi adalah hasil satu OperasiBatch, dengan Index sesuai urutan di request.
+// Err berisi penyebab kegagalan untuk diterjemahkan oleh lapisan HTTP.
+type HasilOperasi struct {
+	Index  int           `json:"index"`
+	Ref    string        `json:"ref,omitempty"`
+	Op     string        `json:"op"`
+	Status string        `json:"status"`
+	Data   *VarietasPadi `json:"data,omitempty"`
+	Err    error         `json:"-"`
+}
+
+// HasilBatch adalah laporan lengkap satu batch. Committed false berarti tidak ada
+// perubahan yang tersimpan (batch atomic dengan operasi yang gagal).
+type HasilBatch struct {
+	Atomic    bool           `json:"atomic"`
+	Committed bool           `json:"committed"`
+	Berhasil  int            `json:"berhasil"`
+	Gagal     int            `json:"gagal"`
+	Hasil     []HasilOperasi `json:"hasil"`
+}
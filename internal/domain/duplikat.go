@@ -0,0 +1,139 @@
+// internal/domain/duplikat.go
+package domain
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// Kriteria kandidat duplikat: sampel fisik yang sama dicatat dua kali, mungkin dengan
+// huruf besar/kecil atau pembulatan yang sedikit berbeda
+const (
+	// JendelaDuplikat: pengamatan yang dicatat lebih jauh dari ini dianggap sampel berbeda,
+	// karena ukuran biji hanya sampai 0.1 mm sehingga nilai yang sama wajar berulang
+	JendelaDuplikat = 24 * time.Hour
+	// ToleransiUkuranDuplikat: selisih panjang/lebar biji (mm) yang masih dianggap pembulatan
+	ToleransiUkuranDuplikat = 0.05
+)
+
+// ModeDuplikat menentukan tindakan saat data baru mirip data yang sudah ada
+type ModeDuplikat string
+
+const (
+	ModeDuplikatPeringatan ModeDuplikat = "warn"   // tetap disimpan, client diberi daftar kandidat
+	ModeDuplikatTolak      ModeDuplikat = "reject" // ditolak dengan ErrConflict
+	ModeDuplikatNonaktif   ModeDuplikat = "off"    // tidak diperiksa
+)
+
+// ParseModeDuplikat membaca mode dari teks konfigurasi
+func ParseModeDuplikat(raw string) (ModeDuplikat, error) {
+	switch m := ModeDuplikat(strings.ToLower(strings.TrimSpace(raw))); m {
+	case ModeDuplikatPeringatan, ModeDuplikatTolak, ModeDuplikatNonaktif:
+		return m, nil
+	}
+	return "", fmt.Errorf("mode duplikat '%s' tidak dikenal (warn, reject, atau off)", raw)
+}
+
+// normalisasiNama menyeragamkan teks bebas untuk perbandingan: huruf kecil tanpa spasi,
+// tanda hubung, atau garis bawah ("IR 64", "ir-64", dan "IR64" dianggap sama)
+func normalisasiNama(s string) string {
+	return strings.NewReplacer(" ", "", "-", "", "_", "").Replace(strings.ToLower(s))
+}
+
+// ukuranSama membandingkan dua ukuran dengan toleransi pembulatan
+func ukuranSama(a, b float64) bool {
+	return math.Abs(a-b) <= ToleransiUkuranDuplikat+1e-9
+}
+
+// KandidatDuplikat bernilai true jika a dan b kemungkinan sampel fisik yang sama: nilai
+// kategorikal sama (tidak peka huruf besar/kecil dan spasi), ukuran sama dalam toleransi
+// pembulatan, lebar biji sama atau salah satunya belum diukur, dan dicatat berdekatan.
+func KandidatDuplikat(a, b VarietasPadi) bool {
+	if normalisasiNama(a.VarietasKelas) != normalisasiNama(b.VarietasKelas) ||
+		!strings.EqualFold(a.Warna, b.Warna) ||
+		!strings.EqualFold(a.TeksturPermukaan, b.TeksturPermukaan) ||
+		!strings.EqualFold(a.BentukUjungDaun, b.BentukUjungDaun) ||
+		!ukuranSama(a.PanjangBijiMM, b.PanjangBijiMM) {
+		return false
+	}
+	if a.LebarBijiMM != nil && b.LebarBijiMM != nil && !ukuranSama(*a.LebarBijiMM, *b.LebarBijiMM) {
+		return false
+	}
+	selisih := a.WaktuPembuatan.Sub(b.WaktuPembuatan)
+	return selisih.Abs() <= JendelaDuplikat
+}
+
+// KunciDuplikat mengelompokkan data menurut nilai kategorikalnya. Semua kandidat duplikat
+// data v memiliki kunci yang sama dengan v; ukuran tidak ikut karena dibandingkan dengan
+// toleransi.
+func KunciDuplikat(v VarietasPadi) string {
+	return strings.Join([]string{normalisasiNama(v.VarietasKelas), strings.ToLower(v.Warna),
+		strings.ToLower(v.TeksturPermukaan), strings.ToLower(v.BentukUjungDaun)}, "\x00")
+}
+
+// DuplikatError dikembalikan saat data baru ditolak karena mirip data yang sudah ada
+// (ModeDuplikatTolak). errors.Is(err, ErrConflict) bernilai true untuk error ini.
+type DuplikatError struct {
+	Kandidat []VarietasPadi
+}
+
+func (e *DuplikatError) Error() string {
+	ids := make([]string, len(e.Kandidat))
+	for i, v := range e.Kandidat {
+		ids[i] = fmt.Sprint(v.ID)
+	}
+	return fmt.Sprintf("%v: data kemungkinan duplikat dari id %s", ErrConflict, strings.Join(ids, ", "))
+}
+
+// Is membuat DuplikatError dikenali sebagai ErrConflict
+func (e *DuplikatError) Is(target error) bool {
+	return target == ErrConflict
+}
+
+// PermintaanGabung adalah permintaan menggabungkan beberapa data ke satu data utama
+type PermintaanGabung struct {
+	IDUtama    int   `json:"id_utama"`
+	IDDigabung []int `json:"id_digabung"`
+}
+
+// Penggabungan mencatat bahwa data IDLama sudah digabung ke IDBaru. Catatan ini tetap ada
+// walaupun data lama dihapus permanen, sehingga ID lama selalu bisa diarahkan ke ID baru.
+type Penggabungan struct {
+	IDLama int       `json:"id_padi"`
+	IDBaru int       `json:"digabung_ke"`
+	Aktor  string    `json:"aktor"`
+	Waktu  time.Time `json:"waktu"`
+}
+
+// HasilGabung adalah data utama setelah penggabungan beserta penunjuk dari ID yang digabung
+type HasilGabung struct {
+	Data     VarietasPadi   `json:"data"`
+	Digabung []Penggabungan `json:"digabung"`
+}
+
+// DigabungError dikembalikan saat data yang dicari sudah digabung ke data lain.
+// errors.Is(err, ErrNotFound) bernilai true untuk error ini.
+type DigabungError struct {
+	IDLama, IDBaru int
+}
+
+func (e *DigabungError) Error() string {
+	return fmt.Sprintf("data varietas id %d sudah digabung ke id %d", e.IDLama, e.IDBaru)
+}
+
+// Is membuat DigabungError dikenali sebagai ErrNotFound
+func (e *DigabungError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+// PenggabunganRepository menyimpan penunjuk dari ID data yang sudah digabung ke data utamanya
+type PenggabunganRepository interface {
+	// Save mencatat penggabungan. Penunjuk lama yang mengarah ke salah satu IDLama ikut
+	// diarahkan ke IDBaru agar rantai penggabungan selalu satu langkah.
+	Save(ctx context.Context, p ...Penggabungan) ([]Penggabungan, error)
+	// FindByID mengambil penggabungan data id. sql.ErrNoRows jika data tidak pernah digabung.
+	FindByID(ctx context.Context, id int) (Penggabungan, error)
+}
@@ -0,0 +1,67 @@
+// internal/domain/errors.go
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Sentinel error domain. Service membungkus error dengan %w memakai sentinel ini,
+// dan handler memetakannya ke status HTTP lewat errors.Is, bukan lewat isi pesan.
+var (
+	ErrNotFound    = errors.New("data varietas tidak ditemukan")
+	ErrValidation  = errors.New("data varietas tidak valid")
+	ErrConflict    = errors.New("konflik data varietas")
+	ErrUnavailable = errors.New("penyimpanan data sedang tidak tersedia")
+	// ErrPreconditionFailed: versi data sudah berubah sejak dibaca klien (If-Match tidak cocok)
+	ErrPreconditionFailed = errors.New("versi data varietas sudah berubah")
+	// ErrUnauthorized: request tidak membawa token yang valid padahal wajib
+	ErrUnauthorized = errors.New("autentikasi diperlukan")
+	// ErrForbidden: pemanggil sudah dikenal tetapi perannya tidak mengizinkan operasi ini
+	ErrForbidden = errors.New("akses ditolak")
+	// ErrKonflikTransaksi: transaksi dibatalkan database karena bentrok dengan transaksi lain
+	// (serialization failure atau deadlock). Termasuk ErrConflict; TxManager mengulang
+	// transaksi yang gagal karena error ini.
+	ErrKonflikTransaksi = fmt.Errorf("%w: transaksi bentrok dengan transaksi lain", ErrConflict)
+)
+
+// FieldError menjelaskan satu field yang gagal validasi
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code,omitempty"` // kode aturan yang dilanggar, misalnya "required" atau "range"
+	Message string `json:"message"`
+}
+
+// ValidationError berisi semua field yang gagal validasi.
+// errors.Is(err, ErrValidation) bernilai true untuk error ini.
+type ValidationError struct {
+	Message string
+	Fields  []FieldError
+}
+
+// NewValidationError membuat ValidationError dari daftar field yang gagal
+func NewValidationError(message string, fields ...FieldError) *ValidationError {
+	return &ValidationError{Message: message, Fields: fields}
+}
+
+func (e *ValidationError) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = ErrValidation.Error()
+	}
+	if len(e.Fields) == 0 {
+		return msg
+	}
+
+	parts := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		parts = append(parts, f.Field+": "+f.Message)
+	}
+	return msg + " (" + strings.Join(parts, "; ") + ")"
+}
+
+// Is membuat ValidationError dikenali sebagai ErrValidation
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
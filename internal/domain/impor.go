@@ -0,0 +1,39 @@
+// internal/domain/impor.go
+package domain
+
+// Status baris pada laporan impor
+const (
+	StatusDiterima = "diterima"
+	StatusDitolak  = "ditolak"
+)
+
+// BarisImpor adalah satu baris data dari file impor (misalnya CSV).
+// Errors berisi kesalahan yang sudah ditemukan saat parsing (misalnya angka tidak valid);
+// baris dengan Errors langsung ditolak tanpa divalidasi ulang.
+type BarisImpor struct {
+	Baris  int
+	Data   VarietasPadi
+	Errors []FieldError
+}
+
+// LaporanBaris adalah hasil impor satu baris
+type LaporanBaris struct {
+	Baris  int          `json:"baris"` // nomor baris di file (header = baris 1)
+	Status string       `json:"status"`
+	IDPadi int          `json:"id_padi,omitempty"` // terisi untuk baris yang disimpan
+	Errors []FieldError `json:"errors,omitempty"`
+	// KandidatDuplikat berisi ID data tersimpan dan DuplikatBaris nomor baris impor
+	// sebelumnya yang kemungkinan sampel yang sama dengan baris ini
+	KandidatDuplikat []int `json:"kandidat_duplikat,omitempty"`
+	DuplikatBaris    []int `json:"duplikat_baris,omitempty"`
+}
+
+// HasilImpor adalah laporan lengkap satu proses impor.
+// Pada dry run, baris valid berstatus diterima tetapi tidak disimpan.
+type HasilImpor struct {
+	DryRun   bool           `json:"dry_run"`
+	Total    int            `json:"total"`
+	Diterima int            `json:"diterima"`
+	Ditolak  int            `json:"ditolak"`
+	Baris    []LaporanBaris `json:"baris"`
+}
@@ -0,0 +1,157 @@
+// internal/domain/kategori_biji.go
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Ambang adalah satu baris tabel ambang: nilai yang lebih besar dari Batas masuk Kategori
+type Ambang struct {
+	Kategori string  `json:"kategori"`
+	Batas    float64 `json:"lebih_dari"`
+}
+
+// TabelAmbang mengelompokkan nilai angka ke kategori. Ambang diurutkan dari Batas
+// terbesar; nilai yang tidak melewati satu pun ambang masuk kategori Sisa.
+type TabelAmbang struct {
+	Ambang []Ambang `json:"ambang"`
+	Sisa   string   `json:"sisa"`
+}
+
+// Kategori mengembalikan kategori untuk nilai
+func (t TabelAmbang) Kategori(nilai float64) string {
+	for _, a := range t.Ambang {
+		if nilai > a.Batas {
+			return a.Kategori
+		}
+	}
+	return t.Sisa
+}
+
+// Semua mengembalikan semua nama kategori dari yang terbesar
+func (t TabelAmbang) Semua() []string {
+	out := make([]string, 0, len(t.Ambang)+1)
+	for _, a := range t.Ambang {
+		out = append(out, a.Kategori)
+	}
+	return append(out, t.Sisa)
+}
+
+// String menulis tabel dalam format yang sama dengan ParseTabelAmbang
+func (t TabelAmbang) String() string {
+	parts := make([]string, 0, len(t.Ambang)+1)
+	for _, a := range t.Ambang {
+		parts = append(parts, a.Kategori+">"+strconv.FormatFloat(a.Batas, 'f', -1, 64))
+	}
+	return strings.Join(append(parts, t.Sisa), ",")
+}
+
+// ParseTabelAmbang membaca tabel dari teks seperti "sangat_panjang>7.5,panjang>6.6,sedang>5.5,pendek".
+// Batas harus menurun dan entri terakhir (tanpa batas) menjadi kategori Sisa.
+func ParseTabelAmbang(raw string) (TabelAmbang, error) {
+	parts := strings.Split(raw, ",")
+	var t TabelAmbang
+	for i, part := range parts {
+		part = strings.TrimSpace(part)
+		kategori, batasTeks, adaBatas := strings.Cut(part, ">")
+		kategori = strings.TrimSpace(kategori)
+		if kategori == "" {
+			return TabelAmbang{}, fmt.Errorf("tabel ambang '%s': nama kategori kosong", raw)
+		}
+
+		if i == len(parts)-1 {
+			if adaBatas {
+				return TabelAmbang{}, fmt.Errorf("tabel ambang '%s': entri terakhir tidak boleh punya batas", raw)
+			}
+			t.Sisa = kategori
+			break
+		}
+		if !adaBatas {
+			return TabelAmbang{}, fmt.Errorf("tabel ambang '%s': kategori '%s' tidak punya batas", raw, kategori)
+		}
+		batas, err := strconv.ParseFloat(strings.TrimSpace(batasTeks), 64)
+		if err != nil {
+			return TabelAmbang{}, fmt.Errorf("tabel ambang '%s': batas '%s' bukan angka", raw, batasTeks)
+		}
+		if len(t.Ambang) > 0 && batas >= t.Ambang[len(t.Ambang)-1].Batas {
+			return TabelAmbang{}, fmt.Errorf("tabel ambang '%s': batas harus menurun", raw)
+		}
+		t.Ambang = append(t.Ambang, Ambang{Kategori: kategori, Batas: batas})
+	}
+	return t, nil
+}
+
+// Tabel bawaan mengikuti Standard Evaluation System for Rice (IRRI).
+var (
+	// AmbangPanjangIRRI: panjang biji (mm) > 7.5 sangat panjang, 6.61–7.5 panjang,
+	// 5.51–6.6 sedang, ≤ 5.5 pendek
+	AmbangPanjangIRRI = TabelAmbang{
+		Ambang: []Ambang{{"sangat_panjang", 7.5}, {"panjang", 6.6}, {"sedang", 5.5}},
+		Sisa:   "pendek",
+	}
+	// AmbangBentukIRRI: rasio panjang/lebar > 3 ramping, 2.1–3 sedang, 1.1–2 gemuk, ≤ 1 bulat
+	AmbangBentukIRRI = TabelAmbang{
+		Ambang: []Ambang{{"ramping", 3.0}, {"sedang", 2.0}, {"gemuk", 1.0}},
+		Sisa:   "bulat",
+	}
+)
+
+// kategoriBiji menyimpan tabel ambang yang aktif. Diatur sekali saat start (AturKategoriBiji).
+var kategoriBiji = struct {
+	sync.RWMutex
+	panjang, bentuk TabelAmbang
+}{panjang: AmbangPanjangIRRI, bentuk: AmbangBentukIRRI}
+
+// AturKategoriBiji mengganti tabel ambang panjang dan bentuk biji yang dipakai semua data
+func AturKategoriBiji(panjang, bentuk TabelAmbang) {
+	kategoriBiji.Lock()
+	defer kategoriBiji.Unlock()
+	kategoriBiji.panjang, kategoriBiji.bentuk = panjang, bentuk
+}
+
+// TabelKategoriBiji mengembalikan tabel ambang panjang dan bentuk biji yang aktif
+func TabelKategoriBiji() (panjang, bentuk TabelAmbang) {
+	kategoriBiji.RLock()
+	defer kategoriBiji.RUnlock()
+	return kategoriBiji.panjang, kategoriBiji.bentuk
+}
+
+// KategoriPanjang adalah kategori panjang biji menurut tabel ambang yang aktif
+func (v VarietasPadi) KategoriPanjang() string {
+	panjang, _ := TabelKategoriBiji()
+	return panjang.Kategori(v.PanjangBijiMM)
+}
+
+// RasioPanjangLebar adalah panjang/lebar biji; ok=false jika lebar belum diukur
+func (v VarietasPadi) RasioPanjangLebar() (rasio float64, ok bool) {
+	if v.LebarBijiMM == nil || *v.LebarBijiMM <= 0 {
+		return 0, false
+	}
+	return v.PanjangBijiMM / *v.LebarBijiMM, true
+}
+
+// BentukBiji adalah kategori bentuk biji dari rasio panjang/lebar, atau kosong jika
+// lebar biji belum diukur
+func (v VarietasPadi) BentukBiji() string {
+	rasio, ok := v.RasioPanjangLebar()
+	if !ok {
+		return ""
+	}
+	_, bentuk := TabelKategoriBiji()
+	return bentuk.Kategori(rasio)
+}
+
+// MarshalJSON menambahkan field turunan (kategori_panjang, bentuk_biji) ke setiap
+// representasi JSON VarietasPadi. Field turunan diabaikan saat data dibaca dari JSON.
+func (v VarietasPadi) MarshalJSON() ([]byte, error) {
+	type varietasAsli VarietasPadi // tanpa method, agar tidak rekursif
+	return json.Marshal(struct {
+		varietasAsli
+		KategoriPanjang string `json:"kategori_panjang"`
+		BentukBiji      string `json:"bentuk_biji,omitempty"`
+	}{varietasAsli(v), v.KategoriPanjang(), v.BentukBiji()})
+}
@@ -0,0 +1,106 @@
+// internal/domain/kemiripan.go
+package domain
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// BobotJarak adalah bobot setiap ciri pada jarak antar data. Jarak adalah rata-rata
+// berbobot dari selisih panjang biji yang dinormalisasi (dibagi rentang panjang biji
+// seluruh data) dan penalti 0/1 untuk setiap ciri kategorikal yang berbeda, sehingga
+// selalu bernilai 0 (identik) sampai 1. Bobot 0 mengabaikan ciri tersebut.
+type BobotJarak struct {
+	PanjangBijiMM    float64 `json:"panjang_biji_mm"`
+	Warna            float64 `json:"warna"`
+	TeksturPermukaan float64 `json:"tekstur_permukaan"`
+	BentukUjungDaun  float64 `json:"bentuk_ujung_daun"`
+}
+
+// BobotJarakBawaan memberi bobot yang sama untuk keempat ciri
+var BobotJarakBawaan = BobotJarak{PanjangBijiMM: 1, Warna: 1, TeksturPermukaan: 1, BentukUjungDaun: 1}
+
+// Total adalah jumlah semua bobot
+func (b BobotJarak) Total() float64 {
+	return b.PanjangBijiMM + b.Warna + b.TeksturPermukaan + b.BentukUjungDaun
+}
+
+// Validate memastikan setiap bobot angka terhingga yang tidak negatif dan minimal satu
+// bobot lebih dari 0. NaN diperiksa tersendiri karena perbandingan dengan NaN selalu false.
+func (b BobotJarak) Validate() error {
+	var fields []FieldError
+	for _, f := range b.field() {
+		switch v := *f.nilai; {
+		case math.IsNaN(v) || math.IsInf(v, 0):
+			fields = append(fields, FieldError{Field: "bobot." + f.nama, Code: "range", Message: "harus angka terhingga (bukan NaN atau Inf)"})
+		case v < 0:
+			fields = append(fields, FieldError{Field: "bobot." + f.nama, Code: "min", Message: "tidak boleh negatif"})
+		}
+	}
+	if total := b.Total(); len(fields) == 0 && (total <= 0 || math.IsInf(total, 0)) {
+		msg := "minimal satu bobot harus lebih dari 0"
+		if total > 0 {
+			msg = "jumlah bobot terlalu besar"
+		}
+		fields = append(fields, FieldError{Field: "bobot", Code: "required", Message: msg})
+	}
+	if len(fields) > 0 {
+		return NewValidationError("bobot jarak tidak valid", fields...)
+	}
+	return nil
+}
+
+type fieldBobot struct {
+	nama  string
+	nilai *float64
+}
+
+// field mengembalikan nama field beserta pointer ke bobotnya, berurutan, untuk dibaca atau diisi
+func (b *BobotJarak) field() []fieldBobot {
+	return []fieldBobot{
+		{"panjang_biji_mm", &b.PanjangBijiMM},
+		{"warna", &b.Warna},
+		{"tekstur_permukaan", &b.TeksturPermukaan},
+		{"bentuk_ujung_daun", &b.BentukUjungDaun},
+	}
+}
+
+// ParseBobotJarak membaca bobot dari teks seperti "panjang_biji_mm:2,warna:0.5". Field yang
+// tidak disebut memakai BobotJarakBawaan.
+func ParseBobotJarak(raw string) (BobotJarak, error) {
+	b := BobotJarakBawaan
+	fields := b.field()
+	for _, part := range strings.Split(raw, ",") {
+		field, teks, ok := strings.Cut(strings.TrimSpace(part), ":")
+		field = strings.TrimSpace(field)
+		i := slices.IndexFunc(fields, func(f fieldBobot) bool { return f.nama == field })
+		if !ok || i < 0 {
+			return BobotJarak{}, NewValidationError("parameter bobot tidak valid",
+				FieldError{Field: "bobot", Code: "invalid",
+					Message: fmt.Sprintf("'%s' harus berformat field:angka dengan field panjang_biji_mm, warna, tekstur_permukaan, atau bentuk_ujung_daun", part)})
+		}
+		v, err := strconv.ParseFloat(strings.TrimSpace(teks), 64)
+		if err != nil {
+			return BobotJarak{}, NewValidationError("parameter bobot tidak valid",
+				FieldError{Field: "bobot." + field, Code: "type", Message: "harus bertipe angka"})
+		}
+		*fields[i].nilai = v
+	}
+	return b, b.Validate()
+}
+
+// Tetangga adalah satu data yang mirip dengan acuan. Kemiripan = 1 - Jarak.
+type Tetangga struct {
+	Data      VarietasPadi `json:"data"`
+	Jarak     float64      `json:"jarak"`
+	Kemiripan float64      `json:"kemiripan"`
+}
+
+// HasilKemiripan adalah k data terdekat dari acuan, terurut dari yang paling mirip
+type HasilKemiripan struct {
+	Bobot    BobotJarak `json:"bobot"`
+	Tetangga []Tetangga `json:"tetangga"`
+}
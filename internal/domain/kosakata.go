@@ -0,0 +1,88 @@
+// internal/domain/kosakata.go
+package domain
+
+import "context"
+
+// Kategori kosakata terkontrol. Nilainya sama dengan nama field JSON di VarietasPadi
+// dan nama tabel lookup di database.
+const (
+	KategoriWarna            = "warna"
+	KategoriTeksturPermukaan = "tekstur_permukaan"
+	KategoriBentukUjungDaun  = "bentuk_ujung_daun"
+)
+
+// KategoriKosakata adalah daftar semua kategori kosakata terkontrol
+var KategoriKosakata = []string{KategoriWarna, KategoriTeksturPermukaan, KategoriBentukUjungDaun}
+
+// KosakataAwal adalah nilai baku awal (seed) tiap kategori.
+// Admin dapat menambah nilai baru lewat endpoint kosakata.
+var KosakataAwal = map[string][]string{
+	KategoriWarna:            WarnaValid,
+	KategoriTeksturPermukaan: TeksturPermukaanValid,
+	KategoriBentukUjungDaun:  BentukUjungDaunValid,
+}
+
+// Kosakata adalah satu nilai baku pada sebuah kategori, beserta alias ejaannya.
+// Contoh: Nilai "Kuning" dengan Alias ["kuning jerami", "yellow"].
+type Kosakata struct {
+	ID       int      `json:"id"`
+	Kategori string   `json:"kategori"`
+	Nilai    string   `json:"nilai"`
+	Alias    []string `json:"alias"`
+}
+
+// KosakataRepository Interface (Kontrak Data Access untuk tabel lookup)
+type KosakataRepository interface {
+	FindAll(ctx context.Context, kategori string) ([]Kosakata, error)
+	FindByID(ctx context.Context, kategori string, id int) (Kosakata, error)
+	Create(ctx context.Context, data Kosakata) (Kosakata, error)
+	// Update mengganti ejaan nilai baku; data pengamatan ikut berubah (ON UPDATE CASCADE)
+	Update(ctx context.Context, data Kosakata) (Kosakata, error)
+	// Delete gagal dengan ErrConflict jika nilai masih dipakai data pengamatan
+	Delete(ctx context.Context, kategori string, id int) error
+	AddAlias(ctx context.Context, kategori string, id int, alias string) (Kosakata, error)
+	RemoveAlias(ctx context.Context, kategori string, id int, alias string) error
+	// Resolve mengembalikan nilai baku untuk value (nilai atau alias, tidak peka huruf besar/kecil).
+	// Mengembalikan sql.ErrNoRows jika value tidak dikenal.
+	Resolve(ctx context.Context, kategori string, value string) (string, error)
+}
+
+// KosakataService Interface (Kontrak Logika Bisnis kosakata terkontrol)
+type KosakataService interface {
+	DaftarKosakata(ctx context.Context, kategori string) ([]Kosakata, error)
+	DapatkanKosakata(ctx context.Context, kategori string, id int) (Kosakata, error)
+	TambahKosakata(ctx context.Context, data Kosakata) (Kosakata, error)
+	UbahKosakata(ctx context.Context, data Kosakata) (Kosakata, error)
+	HapusKosakata(ctx context.Context, kategori string, id int) error
+	TambahAlias(ctx context.Context, kategori string, id int, alias string) (Kosakata, error)
+	HapusAlias(ctx context.Context, kategori string, id int, alias string) error
+}
+
+// ValidKategori mengecek apakah kategori termasuk kosakata terkontrol
+func ValidKategori(kategori string) bool {
+	for _, k := range KategoriKosakata {
+		if k == kategori {
+			return true
+		}
+	}
+	return false
+}
+
+// KategoriValue mengembalikan nilai field kategorikal VarietasPadi untuk kategori tertentu
+func (v VarietasPadi) KategoriValue(kategori string) string {
+	s, _ := v.FieldValue(kategori).(string)
+	return s
+}
+
+// WithKategoriValue mengembalikan salinan data dengan field kategori diganti value
+func (v VarietasPadi) WithKategoriValue(kategori, value string) VarietasPadi {
+	switch kategori {
+	case KategoriWarna:
+		v.Warna = value
+	case KategoriTeksturPermukaan:
+		v.TeksturPermukaan = value
+	case KategoriBentukUjungDaun:
+		v.BentukUjungDaun = value
+	}
+	return v
+}
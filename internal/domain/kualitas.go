@@ -0,0 +1,85 @@
+// internal/domain/kualitas.go
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Jenis temuan pemeriksaan kualitas data
+const (
+	// TemuanOutlierPanjang: panjang biji menyimpang jauh dari data lain di varietas_kelas yang sama
+	TemuanOutlierPanjang = "outlier_panjang"
+	// TemuanDuplikat: kemungkinan sampel yang sama dengan data lain yang lebih lama (lihat KandidatDuplikat)
+	TemuanDuplikat = "duplikat"
+	// TemuanNilaiLangka: nilai kategorikal yang hampir tidak pernah dipakai (kemungkinan salah ketik)
+	TemuanNilaiLangka = "nilai_langka"
+)
+
+// JenisTemuan adalah semua jenis temuan, urutan yang dipakai laporan
+var JenisTemuan = []string{TemuanOutlierPanjang, TemuanDuplikat, TemuanNilaiLangka}
+
+// Status temuan pada laporan
+const (
+	StatusTemuanTerbuka = "terbuka" // belum ditinjau
+	StatusTemuanDiakui  = "diakui"  // sudah ditinjau dan dinyatakan benar
+)
+
+// TemuanKualitas adalah satu masalah kualitas pada satu data. Kode stabil selama
+// masalahnya sama (misalnya "outlier_panjang:12" atau "nilai_langka:12:warna") sehingga
+// pengakuan reviewer tetap melekat saat laporan dihitung ulang.
+type TemuanKualitas struct {
+	Kode   string `json:"kode"`
+	Jenis  string `json:"jenis"`
+	IDPadi int    `json:"id_padi"`
+	// Versi adalah versi data saat temuan dihitung; perbaikan ditolak jika data sudah berubah
+	Versi int    `json:"versi"`
+	Field string `json:"field,omitempty"`
+	Nilai any    `json:"nilai,omitempty"`
+	Pesan string `json:"pesan"`
+	// Metode adalah uji yang menandai outlier: "robust_z" dan/atau "iqr"
+	Metode []string `json:"metode,omitempty"`
+	// Saran adalah nilai pengganti yang kemungkinan benar, jika bisa ditebak
+	Saran any `json:"saran,omitempty"`
+	// DuplikatDari adalah ID data lebih lama yang nilainya sama (hanya untuk duplikat)
+	DuplikatDari *int   `json:"duplikat_dari,omitempty"`
+	Status       string `json:"status"`
+	// Pengakuan terisi jika temuan sudah diakui pada versi data saat ini
+	Pengakuan *PengakuanKualitas `json:"pengakuan,omitempty"`
+}
+
+// PengakuanKualitas mencatat reviewer yang menyatakan temuan bukan kesalahan. Pengakuan
+// hanya berlaku untuk versi data yang ditinjau; jika data berubah temuan terbuka lagi.
+type PengakuanKualitas struct {
+	Kode      string    `json:"kode"`
+	IDPadi    int       `json:"id_padi"`
+	VersiData int       `json:"versi_data"`
+	Catatan   string    `json:"catatan"`
+	Aktor     string    `json:"aktor"`
+	Waktu     time.Time `json:"waktu"`
+}
+
+// LaporanKualitas adalah hasil pemeriksaan kualitas semua data aktif
+type LaporanKualitas struct {
+	JumlahData    int              `json:"jumlah_data"`
+	Terbuka       int              `json:"terbuka"`
+	Diakui        int              `json:"diakui"`
+	Temuan        []TemuanKualitas `json:"temuan"`
+	DiperiksaPada time.Time        `json:"diperiksa_pada"`
+}
+
+// FilterKualitas menyaring temuan pada laporan; nilai kosong berarti semua
+type FilterKualitas struct {
+	Jenis  string
+	Status string
+}
+
+// KualitasRepository menyimpan pengakuan reviewer atas temuan kualitas data
+type KualitasRepository interface {
+	// FindAll mengembalikan semua pengakuan
+	FindAll(ctx context.Context) ([]PengakuanKualitas, error)
+	// Save menyimpan pengakuan, menggantikan pengakuan lama dengan kode yang sama
+	Save(ctx context.Context, p PengakuanKualitas) (PengakuanKualitas, error)
+	// Delete menghapus pengakuan. sql.ErrNoRows jika tidak ada.
+	Delete(ctx context.Context, kode string) error
+}
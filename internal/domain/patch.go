@@ -0,0 +1,120 @@
+// internal/domain/patch.go
+package domain
+
+// VarietasPatch adalah perubahan sebagian pada VarietasPadi.
+// Field bernilai nil berarti tidak diubah; hanya kolom yang diisi yang ditulis ke database.
+type VarietasPatch struct {
+	VarietasKelas *string
+	Warna         *string
+	PanjangBijiMM *float64
+	// LebarBijiMM opsional sehingga bisa dikosongkan: nil berarti tidak diubah,
+	// pointer ke nil berarti diisi null
+	LebarBijiMM      **float64
+	TeksturPermukaan *string
+	BentukUjungDaun  *string
+
+	// Tests adalah operasi "test" JSON Patch sesuai urutannya di dokumen patch.
+	// Jika salah satu tidak cocok, patch ditolak dengan ErrConflict.
+	Tests []PatchTest
+
+	// Versi adalah versi data yang diharapkan (dari If-Match); 0 berarti tanpa syarat
+	Versi int
+}
+
+// PatchTest adalah satu operasi "test" JSON Patch
+type PatchTest struct {
+	Field string
+	Nilai any
+	// Sebelum berisi perubahan dari operasi yang mendahului test ini. Sesuai RFC 6902
+	// test dibandingkan dengan dokumen setelah operasi-operasi itu diterapkan.
+	Sebelum VarietasPatch
+}
+
+// Fields mengembalikan nama field (JSON) yang diubah oleh patch ini
+func (p VarietasPatch) Fields() []string {
+	var fields []string
+	if p.VarietasKelas != nil {
+		fields = append(fields, "varietas_kelas")
+	}
+	if p.Warna != nil {
+		fields = append(fields, "warna")
+	}
+	if p.PanjangBijiMM != nil {
+		fields = append(fields, "panjang_biji_mm")
+	}
+	if p.LebarBijiMM != nil {
+		fields = append(fields, "lebar_biji_mm")
+	}
+	if p.TeksturPermukaan != nil {
+		fields = append(fields, "tekstur_permukaan")
+	}
+	if p.BentukUjungDaun != nil {
+		fields = append(fields, "bentuk_ujung_daun")
+	}
+	return fields
+}
+
+// IsEmpty bernilai true jika patch tidak mengubah field apa pun
+func (p VarietasPatch) IsEmpty() bool {
+	return len(p.Fields()) == 0
+}
+
+// Apply mengembalikan salinan data dengan field patch diterapkan (hasil merge)
+func (p VarietasPatch) Apply(v VarietasPadi) VarietasPadi {
+	if p.VarietasKelas != nil {
+		v.VarietasKelas = *p.VarietasKelas
+	}
+	if p.Warna != nil {
+		v.Warna = *p.Warna
+	}
+	if p.PanjangBijiMM != nil {
+		v.PanjangBijiMM = *p.PanjangBijiMM
+	}
+	if p.LebarBijiMM != nil {
+		v.LebarBijiMM = *p.LebarBijiMM
+	}
+	if p.TeksturPermukaan != nil {
+		v.TeksturPermukaan = *p.TeksturPermukaan
+	}
+	if p.BentukUjungDaun != nil {
+		v.BentukUjungDaun = *p.BentukUjungDaun
+	}
+	return v
+}
+
+// Restrict membuat patch baru berisi nilai dari data untuk field yang sama dengan p.
+// Dipakai setelah validasi agar nilai yang sudah dinormalisasi (trim, ejaan baku) yang disimpan.
+func (p VarietasPatch) Restrict(v VarietasPadi) VarietasPatch {
+	out := VarietasPatch{Versi: p.Versi}
+	if p.VarietasKelas != nil {
+		out.VarietasKelas = &v.VarietasKelas
+	}
+	if p.Warna != nil {
+		out.Warna = &v.Warna
+	}
+	if p.PanjangBijiMM != nil {
+		out.PanjangBijiMM = &v.PanjangBijiMM
+	}
+	if p.LebarBijiMM != nil {
+		out.LebarBijiMM = &v.LebarBijiMM
+	}
+	if p.TeksturPermukaan != nil {
+		out.TeksturPermukaan = &v.TeksturPermukaan
+	}
+	if p.BentukUjungDaun != nil {
+		out.BentukUjungDaun = &v.BentukUjungDaun
+	}
+	return out
+}
+
+// FailedTests mengembalikan field yang nilainya tidak sesuai syarat Tests. Setiap test
+// diperiksa terhadap data lama yang sudah diubah oleh operasi sebelum test tersebut.
+func (p VarietasPatch) FailedTests(v VarietasPadi) []string {
+	var failed []string
+	for _, t := range p.Tests {
+		if compareValues(t.Sebelum.Apply(v).FieldValue(t.Field), t.Nilai) != 0 {
+			failed = append(failed, t.Field)
+		}
+	}
+	return failed
+}
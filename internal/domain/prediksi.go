@@ -0,0 +1,65 @@
+// internal/domain/prediksi.go
+package domain
+
+import "time"
+
+// FiturRules adalah VarietasRules tanpa aturan varietas_kelas: aturan untuk sampel yang
+// kelasnya belum diketahui, misalnya input prediksi
+var FiturRules = func() []Rule {
+	var rules []Rule
+	for _, rule := range VarietasRules {
+		if rule.Field != "varietas_kelas" {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}()
+
+// Prediksi adalah satu kandidat varietas_kelas beserta peluangnya (0..1)
+type Prediksi struct {
+	VarietasKelas string  `json:"varietas_kelas"`
+	Confidence    float64 `json:"confidence"`
+}
+
+// InfoModel menjelaskan model klasifikasi yang sedang dipakai
+type InfoModel struct {
+	Algoritma   string    `json:"algoritma"`
+	JumlahData  int       `json:"jumlah_data"`
+	JumlahKelas int       `json:"jumlah_kelas"`
+	DilatihPada time.Time `json:"dilatih_pada"`
+}
+
+// HasilPrediksi adalah k kandidat teratas, terurut dari confidence tertinggi
+type HasilPrediksi struct {
+	Prediksi []Prediksi `json:"prediksi"`
+	Model    InfoModel  `json:"model"`
+}
+
+// MetrikKelas adalah precision, recall, dan F1 satu kelas. Support adalah jumlah data
+// yang kelas aslinya kelas ini.
+type MetrikKelas struct {
+	VarietasKelas string  `json:"varietas_kelas"`
+	Support       int     `json:"support"`
+	Precision     float64 `json:"precision"`
+	Recall        float64 `json:"recall"`
+	F1            float64 `json:"f1"`
+}
+
+// MatriksKonfusi menghitung pasangan (kelas asli, kelas prediksi). Nilai[i][j] adalah
+// jumlah data berkelas Label[i] yang diprediksi sebagai Label[j].
+type MatriksKonfusi struct {
+	Label []string `json:"label"`
+	Nilai [][]int  `json:"nilai"`
+}
+
+// EvaluasiModel adalah hasil stratified k-fold cross-validation model klasifikasi
+type EvaluasiModel struct {
+	Algoritma      string         `json:"algoritma"`
+	JumlahLipatan  int            `json:"jumlah_lipatan"`
+	JumlahData     int            `json:"jumlah_data"`
+	Akurasi        float64        `json:"akurasi"`
+	MacroF1        float64        `json:"macro_f1"`
+	Kelas          []MetrikKelas  `json:"kelas"`
+	MatriksKonfusi MatriksKonfusi `json:"matriks_konfusi"`
+	DievaluasiPada time.Time      `json:"dievaluasi_pada"`
+}
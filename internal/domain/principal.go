@@ -0,0 +1,66 @@
+// internal/domain/principal.go
+package domain
+
+import (
+	"context"
+	"maps"
+)
+
+// Peran adalah peran pengguna lab yang menentukan izin akses (lihat service.Kebijakan)
+type Peran string
+
+const (
+	PeranViewer     Peran = "viewer"     // hanya membaca
+	PeranTechnician Peran = "technician" // menambah data dan mengubah data miliknya sendiri
+	PeranCurator    Peran = "curator"    // mengubah data siapa pun, menggabung, dan memulihkan
+	PeranAdmin      Peran = "admin"      // menghapus permanen dan mengelola kosakata
+)
+
+// SemuaPeran adalah peran yang dikenal; peran lain di token diabaikan
+var SemuaPeran = []Peran{PeranViewer, PeranTechnician, PeranCurator, PeranAdmin}
+
+// Principal adalah identitas pemanggil API yang sudah diautentikasi dari token JWT
+type Principal struct {
+	// Subjek adalah klaim "sub"; dipakai sebagai aktor di riwayat perubahan
+	Subjek string `json:"sub"`
+	// Nama adalah klaim "name" atau "preferred_username", jika ada
+	Nama     string `json:"nama,omitempty"`
+	Penerbit string `json:"iss,omitempty"`
+	// Peran diambil dari klaim "roles"
+	Peran []Peran `json:"peran"`
+	// Klaim berisi semua klaim token apa adanya
+	Klaim map[string]any `json:"-"`
+}
+
+// WithPrincipal menyimpan principal di context. Selama ada principal, aktor riwayat
+// diambil dari principal, bukan dari WithActor.
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, ctxKeyPrincipal, p)
+}
+
+// PrincipalFromContext mengembalikan principal request, false jika request anonim
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(ctxKeyPrincipal).(Principal)
+	return p, ok
+}
+
+// WithPemilikWajib menandai bahwa operasi riwayat (misalnya OperasiUpdate) hanya boleh
+// dilakukan pada data yang DibuatOleh-nya sama dengan subjek. Service memeriksanya setelah
+// data dikunci, di transaksi yang sama dengan perubahannya.
+func WithPemilikWajib(ctx context.Context, operasi, subjek string) context.Context {
+	syarat, _ := ctx.Value(ctxKeyPemilikWajib).(map[string]string)
+	syarat = maps.Clone(syarat)
+	if syarat == nil {
+		syarat = map[string]string{}
+	}
+	syarat[operasi] = subjek
+	return context.WithValue(ctx, ctxKeyPemilikWajib, syarat)
+}
+
+// PemilikWajib mengembalikan subjek yang wajib menjadi pemilik data untuk operasi,
+// false jika operasi boleh dilakukan pada data siapa pun
+func PemilikWajib(ctx context.Context, operasi string) (string, bool) {
+	syarat, _ := ctx.Value(ctxKeyPemilikWajib).(map[string]string)
+	subjek, ok := syarat[operasi]
+	return subjek, ok
+}
@@ -0,0 +1,310 @@
+// internal/domain/query.go
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Batas default pagination untuk daftar varietas. MaxPage menjaga agar Offset dan
+// HasNext (page * per_page) tidak pernah overflow.
+const (
+	DefaultPerPage = 20
+	MaxPerPage     = 100
+	MaxPage        = math.MaxInt32 / MaxPerPage
+)
+
+// FieldKind menentukan tipe nilai sebuah kolom yang boleh di-sort / di-filter
+type FieldKind int
+
+const (
+	FieldText FieldKind = iota
+	FieldNumber
+)
+
+// VarietasFields adalah whitelist field (nama JSON) yang boleh dipakai
+// untuk sorting dan filtering. Field di luar daftar ini akan ditolak,
+// sehingga nama kolom tidak pernah berasal langsung dari input user.
+var VarietasFields = map[string]FieldKind{
+	"id_padi":           FieldNumber,
+	"varietas_kelas":    FieldText,
+	"warna":             FieldText,
+	"panjang_biji_mm":   FieldNumber,
+	"lebar_biji_mm":     FieldNumber,
+	"tekstur_permukaan": FieldText,
+	"bentuk_ujung_daun": FieldText,
+
+	// Field turunan (lihat kategori_biji.go): bisa di-filter dan di-sort, tidak bisa diubah
+	"kategori_panjang": FieldText,
+	"bentuk_biji":      FieldText,
+}
+
+// FieldTurunan adalah field yang dihitung dari field lain, bukan disimpan
+var FieldTurunan = map[string]bool{"kategori_panjang": true, "bentuk_biji": true}
+
+// FilterOp adalah operator pembanding pada filter
+type FilterOp string
+
+const (
+	OpEq  FilterOp = "eq"
+	OpNe  FilterOp = "ne"
+	OpGt  FilterOp = "gt"
+	OpGte FilterOp = "gte"
+	OpLt  FilterOp = "lt"
+	OpLte FilterOp = "lte"
+)
+
+// textOps adalah operator yang berlaku untuk field bertipe teks
+var textOps = map[FilterOp]bool{OpEq: true, OpNe: true}
+
+// numberOps adalah operator yang berlaku untuk field bertipe angka
+var numberOps = map[FilterOp]bool{OpEq: true, OpNe: true, OpGt: true, OpGte: true, OpLt: true, OpLte: true}
+
+// Filter adalah satu kriteria filter, misalnya panjang_biji_mm[gte]=7.
+// Value sudah dikonversi sesuai FieldKind (string atau float64).
+type Filter struct {
+	Field string
+	Op    FilterOp
+	Value any
+}
+
+// SortField adalah satu kriteria urutan, misalnya sort=-panjang_biji_mm
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// VarietasQuery adalah spesifikasi query untuk daftar varietas.
+// Handler mengisinya dari query string, repository menerjemahkannya ke SQL.
+type VarietasQuery struct {
+	Page    int
+	PerPage int
+	Sort    []SortField
+	Filters []Filter
+
+	// Terhapus bernilai true untuk membaca isi tempat sampah (data soft delete)
+	// alih-alih data aktif
+	Terhapus bool
+}
+
+// VarietasPage adalah hasil query berhalaman beserta metadata total data
+type VarietasPage struct {
+	Data    []VarietasPadi
+	Total   int
+	Page    int
+	PerPage int
+}
+
+// ErrQueryTidakValid dikembalikan ketika parameter query tidak bisa dipahami
+var ErrQueryTidakValid = errors.New("parameter query tidak valid")
+
+// NewFilter membuat Filter yang sudah divalidasi terhadap whitelist field dan operator
+func NewFilter(field string, op FilterOp, raw string) (Filter, error) {
+	kind, ok := VarietasFields[field]
+	if !ok {
+		return Filter{}, fmt.Errorf("%w: field filter '%s' tidak dikenal", ErrQueryTidakValid, field)
+	}
+
+	switch kind {
+	case FieldNumber:
+		if !numberOps[op] {
+			return Filter{}, fmt.Errorf("%w: operator '%s' tidak didukung untuk '%s'", ErrQueryTidakValid, op, field)
+		}
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return Filter{}, fmt.Errorf("%w: nilai '%s' untuk '%s' harus berupa angka", ErrQueryTidakValid, raw, field)
+		}
+		return Filter{Field: field, Op: op, Value: n}, nil
+	default:
+		if !textOps[op] {
+			return Filter{}, fmt.Errorf("%w: operator '%s' tidak didukung untuk '%s'", ErrQueryTidakValid, op, field)
+		}
+		if FieldTurunan[field] && !kategoriDikenal(field, raw) {
+			return Filter{}, fmt.Errorf("%w: '%s' bukan nilai %s yang dikenal", ErrQueryTidakValid, raw, field)
+		}
+		return Filter{Field: field, Op: op, Value: raw}, nil
+	}
+}
+
+// kategoriDikenal mengecek nilai filter field turunan terhadap tabel ambang yang aktif
+func kategoriDikenal(field, nilai string) bool {
+	panjang, bentuk := TabelKategoriBiji()
+	tabel := panjang
+	if field == "bentuk_biji" {
+		tabel = bentuk
+	}
+	for _, k := range tabel.Semua() {
+		if k == nilai {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseSort mengubah string seperti "-panjang_biji_mm,varietas_kelas" menjadi daftar SortField
+func ParseSort(raw string) ([]SortField, error) {
+	var result []SortField
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		sf := SortField{Field: part}
+		if strings.HasPrefix(part, "-") {
+			sf = SortField{Field: part[1:], Desc: true}
+		}
+		if _, ok := VarietasFields[sf.Field]; !ok {
+			return nil, fmt.Errorf("%w: field sort '%s' tidak dikenal", ErrQueryTidakValid, sf.Field)
+		}
+		result = append(result, sf)
+	}
+	return result, nil
+}
+
+// Normalize mengisi nilai default pagination dan membatasi page serta per_page
+func (q VarietasQuery) Normalize() VarietasQuery {
+	if q.Page < 1 {
+		q.Page = 1
+	}
+	if q.Page > MaxPage {
+		q.Page = MaxPage
+	}
+	if q.PerPage < 1 {
+		q.PerPage = DefaultPerPage
+	}
+	if q.PerPage > MaxPerPage {
+		q.PerPage = MaxPerPage
+	}
+	return q
+}
+
+// Offset menghitung jumlah baris yang dilewati untuk halaman saat ini
+func (q VarietasQuery) Offset() int {
+	return (q.Page - 1) * q.PerPage
+}
+
+// HasNext bernilai true jika masih ada halaman setelah halaman ini
+func (p VarietasPage) HasNext() bool {
+	return p.Page*p.PerPage < p.Total
+}
+
+// HasPrev bernilai true jika halaman ini bukan halaman pertama
+func (p VarietasPage) HasPrev() bool {
+	return p.Page > 1
+}
+
+// FieldValue mengembalikan nilai field berdasarkan nama JSON-nya.
+// Dipakai oleh implementasi non-SQL (misalnya repository in-memory) untuk filter dan sort.
+func (v VarietasPadi) FieldValue(field string) any {
+	switch field {
+	case "id_padi":
+		return float64(v.ID)
+	case "varietas_kelas":
+		return v.VarietasKelas
+	case "warna":
+		return v.Warna
+	case "panjang_biji_mm":
+		return v.PanjangBijiMM
+	case "lebar_biji_mm":
+		if v.LebarBijiMM == nil {
+			return nil
+		}
+		return *v.LebarBijiMM
+	case "tekstur_permukaan":
+		return v.TeksturPermukaan
+	case "bentuk_ujung_daun":
+		return v.BentukUjungDaun
+	case "kategori_panjang":
+		return v.KategoriPanjang()
+	case "bentuk_biji":
+		return v.BentukBiji()
+	}
+	return nil
+}
+
+// compareValues membandingkan dua nilai field (string atau float64): -1, 0, atau 1.
+// nil (field opsional yang kosong) dianggap lebih besar dari nilai apa pun, sama seperti
+// NULL pada ORDER BY PostgreSQL.
+func compareValues(a, b any) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return 1
+	case b == nil:
+		return -1
+	}
+	switch av := a.(type) {
+	case float64:
+		bv, _ := b.(float64)
+		switch {
+		case av < bv:
+			return -1
+		case av > bv:
+			return 1
+		}
+		return 0
+	case string:
+		bv, _ := b.(string)
+		return strings.Compare(av, bv)
+	}
+	return 0
+}
+
+// Match mengecek apakah sebuah data memenuhi filter ini
+// Field opsional yang kosong tidak cocok dengan filter apa pun (seperti NULL di SQL).
+func (f Filter) Match(v VarietasPadi) bool {
+	nilai := v.FieldValue(f.Field)
+	if nilai == nil {
+		return false
+	}
+	c := compareValues(nilai, f.Value)
+	switch f.Op {
+	case OpEq:
+		return c == 0
+	case OpNe:
+		return c != 0
+	case OpGt:
+		return c > 0
+	case OpGte:
+		return c >= 0
+	case OpLt:
+		return c < 0
+	case OpLte:
+		return c <= 0
+	}
+	return false
+}
+
+// Match mengecek apakah sebuah data memenuhi semua filter di query
+// dan berada di lingkup yang benar (aktif atau tempat sampah)
+func (q VarietasQuery) Match(v VarietasPadi) bool {
+	if (v.DeletedAt != nil) != q.Terhapus {
+		return false
+	}
+	for _, f := range q.Filters {
+		if !f.Match(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// Less membandingkan dua data sesuai urutan sort di query,
+// dengan id_padi sebagai pembanding terakhir (sama seperti repository SQL).
+func (q VarietasQuery) Less(a, b VarietasPadi) bool {
+	for _, s := range q.Sort {
+		c := compareValues(a.FieldValue(s.Field), b.FieldValue(s.Field))
+		if c == 0 {
+			continue
+		}
+		if s.Desc {
+			return c > 0
+		}
+		return c < 0
+	}
+	return a.ID < b.ID
+}
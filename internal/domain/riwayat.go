@@ -0,0 +1,162 @@
+// internal/domain/riwayat.go
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Jenis operasi yang dicatat di riwayat perubahan
+const (
+	OperasiCreate  = "create"
+	OperasiUpdate  = "update"
+	OperasiDelete  = "delete"
+	OperasiRestore = "restore"
+	OperasiPurge   = "purge"
+	// OperasiMerge dicatat pada data utama dan setiap data yang digabung ke dalamnya
+	OperasiMerge = "merge"
+)
+
+// AktorAnonim dipakai jika request tidak menyebutkan siapa pelakunya
+const AktorAnonim = "anonim"
+
+// Revisi adalah satu baris riwayat (audit trail) perubahan VarietasPadi.
+// Sebelum kosong untuk create, Sesudah kosong untuk purge.
+type Revisi struct {
+	ID         int64                `json:"id"`
+	IDPadi     int                  `json:"id_padi"`
+	Versi      int                  `json:"versi"`
+	Operasi    string               `json:"operasi"`
+	Aktor      string               `json:"aktor"`
+	Keterangan string               `json:"keterangan,omitempty"`
+	Waktu      time.Time            `json:"waktu"`
+	Sebelum    *VarietasPadi        `json:"sebelum"`
+	Sesudah    *VarietasPadi        `json:"sesudah"`
+	Perubahan  map[string]Perubahan `json:"perubahan"`
+}
+
+// RiwayatRepository menyimpan dan membaca riwayat perubahan VarietasPadi.
+// Catat dipanggil service di dalam TxManager.WithinTx yang sama dengan perubahan datanya,
+// sehingga data dan riwayatnya selalu tersimpan (atau batal) bersama.
+type RiwayatRepository interface {
+	// Catat menyimpan satu atau banyak revisi sekaligus
+	Catat(ctx context.Context, revisi ...Revisi) error
+	// History mengembalikan riwayat satu data, urut dari yang terlama
+	History(ctx context.Context, id int) ([]Revisi, error)
+	// FindRevision mengambil satu revisi milik data id. sql.ErrNoRows jika tidak ada.
+	FindRevision(ctx context.Context, id int, revisiID int64) (Revisi, error)
+}
+
+// Perubahan adalah nilai lama dan baru satu field
+type Perubahan struct {
+	Dari any `json:"dari"`
+	Ke   any `json:"ke"`
+}
+
+// fieldRiwayat adalah field yang dibandingkan saat menyusun diff riwayat
+var fieldRiwayat = []string{"varietas_kelas", "warna", "panjang_biji_mm", "lebar_biji_mm", "tekstur_permukaan", "bentuk_ujung_daun", "deleted_at"}
+
+// DiffVarietas membandingkan dua keadaan data dan mengembalikan field yang berubah.
+// nil berarti data belum ada (create) atau sudah tidak ada (purge).
+func DiffVarietas(sebelum, sesudah *VarietasPadi) map[string]Perubahan {
+	nilai := func(v *VarietasPadi, field string) any {
+		if v == nil {
+			return nil
+		}
+		if field == "deleted_at" {
+			if v.DeletedAt == nil {
+				return nil
+			}
+			return *v.DeletedAt
+		}
+		return v.FieldValue(field)
+	}
+
+	diff := map[string]Perubahan{}
+	for _, field := range fieldRiwayat {
+		dari, ke := nilai(sebelum, field), nilai(sesudah, field)
+		if !samaNilai(dari, ke) {
+			diff[field] = Perubahan{Dari: dari, Ke: ke}
+		}
+	}
+	return diff
+}
+
+// samaNilai membandingkan nilai field riwayat, termasuk waktu dan nil
+func samaNilai(a, b any) bool {
+	ta, okA := a.(time.Time)
+	tb, okB := b.(time.Time)
+	if okA || okB {
+		return okA && okB && ta.Equal(tb)
+	}
+	return a == b
+}
+
+// NewRevisi menyusun baris riwayat untuk satu operasi. Aktor dan keterangan
+// diambil dari context (lihat WithActor dan WithKeterangan).
+func NewRevisi(ctx context.Context, operasi string, sebelum, sesudah *VarietasPadi) Revisi {
+	rev := Revisi{
+		Operasi:    operasi,
+		Aktor:      ActorFromContext(ctx),
+		Keterangan: KeteranganFromContext(ctx),
+		Sebelum:    sebelum,
+		Sesudah:    sesudah,
+		Perubahan:  DiffVarietas(sebelum, sesudah),
+	}
+	if sesudah != nil {
+		rev.IDPadi, rev.Versi = sesudah.ID, sesudah.Versi
+	} else if sebelum != nil {
+		rev.IDPadi, rev.Versi = sebelum.ID, sebelum.Versi
+	}
+	return rev
+}
+
+// Snapshot mengembalikan keadaan data yang tercatat pada revisi ini
+// (keadaan sesudah operasi, atau sebelum jika data sudah dihapus permanen)
+func (r Revisi) Snapshot() (VarietasPadi, bool) {
+	if r.Sesudah != nil {
+		return *r.Sesudah, true
+	}
+	if r.Sebelum != nil {
+		return *r.Sebelum, true
+	}
+	return VarietasPadi{}, false
+}
+
+type ctxKey int
+
+const (
+	ctxKeyAktor ctxKey = iota
+	ctxKeyKeterangan
+	ctxKeyPrincipal
+	ctxKeyPemilikWajib
+)
+
+// WithActor menyimpan identitas pelaku perubahan di context untuk dicatat di riwayat
+func WithActor(ctx context.Context, aktor string) context.Context {
+	return context.WithValue(ctx, ctxKeyAktor, aktor)
+}
+
+// ActorFromContext mengembalikan pelaku perubahan: subjek principal yang terautentikasi,
+// lalu aktor dari WithActor, atau AktorAnonim jika keduanya tidak ada
+func ActorFromContext(ctx context.Context) string {
+	if p, ok := PrincipalFromContext(ctx); ok && p.Subjek != "" {
+		return p.Subjek
+	}
+	if aktor, _ := ctx.Value(ctxKeyAktor).(string); aktor != "" {
+		return aktor
+	}
+	return AktorAnonim
+}
+
+// WithKeterangan menambahkan catatan bebas ke baris riwayat berikutnya, misalnya
+// "revert ke revisi 12"
+func WithKeterangan(ctx context.Context, keterangan string) context.Context {
+	return context.WithValue(ctx, ctxKeyKeterangan, keterangan)
+}
+
+// KeteranganFromContext mengembalikan catatan riwayat dari context (boleh kosong)
+func KeteranganFromContext(ctx context.Context) string {
+	keterangan, _ := ctx.Value(ctxKeyKeterangan).(string)
+	return keterangan
+}
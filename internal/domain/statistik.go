@@ -0,0 +1,51 @@
+// internal/domain/statistik.go
+package domain
+
+import "context"
+
+// FieldKategorikal adalah field teks yang bisa dipakai group_by dan dibuatkan tabel frekuensi
+var FieldKategorikal = []string{"varietas_kelas", "warna", "tekstur_permukaan", "bentuk_ujung_daun", "kategori_panjang", "bentuk_biji"}
+
+// StatistikNumerik adalah statistik deskriptif satu field angka. Kuartil dihitung dengan
+// interpolasi linear (sama seperti percentile_cont PostgreSQL).
+type StatistikNumerik struct {
+	Count  int     `json:"count"`
+	Min    float64 `json:"min"`
+	Q1     float64 `json:"q1"`
+	Median float64 `json:"median"`
+	Q3     float64 `json:"q3"`
+	Max    float64 `json:"max"`
+	Mean   float64 `json:"mean"`
+	StdDev float64 `json:"std_dev"` // simpangan baku sampel (n-1); 0 jika hanya satu data
+}
+
+// Frekuensi adalah jumlah kemunculan satu nilai kategorikal. Proporsi relatif terhadap
+// jumlah data di grupnya.
+type Frekuensi struct {
+	Nilai    string  `json:"nilai"`
+	Jumlah   int     `json:"jumlah"`
+	Proporsi float64 `json:"proporsi"`
+}
+
+// StatistikGrup adalah statistik satu grup. Grup kosong jika tanpa group_by.
+// Frekuensi berisi tabel per field kategorikal (kecuali field group_by), terurut dari
+// nilai yang paling sering muncul.
+type StatistikGrup struct {
+	Grup          string                 `json:"grup,omitempty"`
+	PanjangBijiMM StatistikNumerik       `json:"panjang_biji_mm"`
+	Frekuensi     map[string][]Frekuensi `json:"frekuensi"`
+}
+
+// StatistikVarietas adalah hasil statistik deskriptif, satu entri per grup terurut nama grup
+type StatistikVarietas struct {
+	GroupBy string          `json:"group_by,omitempty"`
+	Total   int             `json:"total"`
+	Grup    []StatistikGrup `json:"grup"`
+}
+
+// StatistikRepository adalah kemampuan opsional repository untuk menghitung statistik
+// sendiri (misalnya dengan agregasi SQL). Repository yang tidak memilikinya dihitung
+// oleh service dari hasil Iterate.
+type StatistikRepository interface {
+	Statistik(ctx context.Context, q VarietasQuery, groupBy string) (StatistikVarietas, error)
+}
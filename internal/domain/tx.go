@@ -0,0 +1,18 @@
+// internal/domain/tx.go
+package domain
+
+import "context"
+
+// TxManager menjalankan beberapa operasi repository sebagai satu unit kerja (transaksi).
+// Repository mengambil transaksi aktif dari context yang diteruskan ke fn, jadi semua
+// method repository yang dipanggil dengan context itu ikut dalam transaksi yang sama.
+type TxManager interface {
+	// WithinTx menjalankan fn dalam transaksi: commit jika fn mengembalikan nil, rollback
+	// jika gagal. Jika gagal karena ErrKonflikTransaksi, seluruh fn diulang beberapa kali,
+	// jadi fn tidak boleh punya efek samping di luar transaksi (atau harus aman diulang).
+	// WithinTx di dalam WithinTx memakai transaksi yang sudah berjalan.
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+	// Savepoint menjalankan fn di dalam transaksi aktif; jika fn gagal hanya perubahan fn
+	// yang dibatalkan. Tanpa transaksi aktif, Savepoint sama dengan WithinTx.
+	Savepoint(ctx context.Context, fn func(ctx context.Context) error) error
+}
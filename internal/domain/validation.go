@@ -0,0 +1,166 @@
+// internal/domain/validation.go
+package domain
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode/utf8"
+)
+
+// Rentang panjang biji (mm) yang masih masuk akal secara agronomis.
+// Nilai di luar rentang ini hampir pasti salah ketik (misalnya 75 untuk 7.5).
+const (
+	MinPanjangBijiMM = 3.0
+	MaxPanjangBijiMM = 15.0
+
+	// Lebar biji opsional, tetapi jika diisi harus di rentang ini
+	MinLebarBijiMM = 1.0
+	MaxLebarBijiMM = 6.0
+)
+
+// Nilai baku awal field kategorikal VarietasPadi. Daftar lengkapnya disimpan di
+// tabel kosakata terkontrol (lihat KosakataAwal), jadi validator tidak mengeceknya.
+var (
+	WarnaValid            = []string{"Kuning", "Kuning Emas", "Putih", "Krem", "Coklat", "Merah", "Hitam", "Ungu"}
+	TeksturPermukaanValid = []string{"Halus", "Kasar", "Berbulu"}
+	BentukUjungDaunValid  = []string{"Runcing", "Meruncing", "Tumpul", "Membulat"}
+)
+
+// Rule adalah satu aturan validasi deklaratif untuk satu field.
+// Check mengembalikan pesan kesalahan, atau string kosong jika nilai valid.
+type Rule struct {
+	Field string
+	Code  string
+	Check func(value any) string
+}
+
+// Required: field teks tidak boleh kosong
+func Required(field string) Rule {
+	return Rule{Field: field, Code: "required", Check: func(value any) string {
+		if s, _ := value.(string); s == "" {
+			return "wajib diisi"
+		}
+		return ""
+	}}
+}
+
+// MaxLength: panjang field teks (dalam karakter) tidak boleh melebihi max
+func MaxLength(field string, max int) Rule {
+	return Rule{Field: field, Code: "max_length", Check: func(value any) string {
+		if s, _ := value.(string); utf8.RuneCountInString(s) > max {
+			return fmt.Sprintf("maksimal %d karakter", max)
+		}
+		return ""
+	}}
+}
+
+// OneOf: field teks harus salah satu dari nilai yang diizinkan (tidak peka huruf besar/kecil).
+// Nilai kosong dilewati agar tidak dobel dengan aturan Required.
+func OneOf(field string, allowed []string) Rule {
+	return Rule{Field: field, Code: "one_of", Check: func(value any) string {
+		s, _ := value.(string)
+		if s == "" || canonical(s, allowed) != "" {
+			return ""
+		}
+		return "harus salah satu dari: " + strings.Join(allowed, ", ")
+	}}
+}
+
+// Between: field angka harus berada di rentang [min, max]. NaN dan Inf selalu ditolak
+// (perbandingan dengan NaN selalu false sehingga harus diperiksa tersendiri).
+func Between(field string, min, max float64) Rule {
+	return Rule{Field: field, Code: "range", Check: func(value any) string {
+		if n, _ := value.(float64); math.IsNaN(n) || math.IsInf(n, 0) || n < min || n > max {
+			return fmt.Sprintf("harus di antara %g dan %g", min, max)
+		}
+		return ""
+	}}
+}
+
+// Optional menjalankan rule hanya jika field opsional terisi (bukan nil)
+func Optional(rule Rule) Rule {
+	check := rule.Check
+	rule.Check = func(value any) string {
+		if value == nil {
+			return ""
+		}
+		return check(value)
+	}
+	return rule
+}
+
+// VarietasRules adalah aturan validasi standar VarietasPadi.
+// Aturan yang sama dipakai saat create, update, dan import. Keanggotaan nilai
+// warna, tekstur_permukaan, dan bentuk_ujung_daun dicek terhadap kosakata terkontrol.
+var VarietasRules = []Rule{
+	Required("varietas_kelas"),
+	MaxLength("varietas_kelas", 100),
+
+	Required("warna"),
+	MaxLength("warna", 50),
+
+	Between("panjang_biji_mm", MinPanjangBijiMM, MaxPanjangBijiMM),
+	Optional(Between("lebar_biji_mm", MinLebarBijiMM, MaxLebarBijiMM)),
+
+	Required("tekstur_permukaan"),
+	MaxLength("tekstur_permukaan", 50),
+
+	Required("bentuk_ujung_daun"),
+	MaxLength("bentuk_ujung_daun", 50),
+}
+
+// Validator menjalankan sekumpulan Rule terhadap VarietasPadi
+type Validator struct {
+	rules []Rule
+}
+
+// NewValidator membuat Validator. Tanpa argumen, VarietasRules yang dipakai.
+func NewValidator(rules ...Rule) *Validator {
+	if len(rules) == 0 {
+		rules = VarietasRules
+	}
+	return &Validator{rules: rules}
+}
+
+// Validate merapikan data (trim spasi) lalu menjalankan semua aturan.
+// Semua pelanggaran dikumpulkan, tidak berhenti di yang pertama.
+func (v *Validator) Validate(data VarietasPadi) (VarietasPadi, error) {
+	data = Normalize(data)
+
+	var fields []FieldError
+	for _, rule := range v.rules {
+		if msg := rule.Check(data.FieldValue(rule.Field)); msg != "" {
+			fields = append(fields, FieldError{Field: rule.Field, Code: rule.Code, Message: msg})
+		}
+	}
+
+	if len(fields) > 0 {
+		return data, NewValidationError("data varietas tidak valid", fields...)
+	}
+	return data, nil
+}
+
+// Normalize membuang spasi di awal/akhir dan spasi ganda di semua field teks
+func Normalize(data VarietasPadi) VarietasPadi {
+	data.VarietasKelas = CollapseSpaces(data.VarietasKelas)
+	data.Warna = CollapseSpaces(data.Warna)
+	data.TeksturPermukaan = CollapseSpaces(data.TeksturPermukaan)
+	data.BentukUjungDaun = CollapseSpaces(data.BentukUjungDaun)
+	return data
+}
+
+// CollapseSpaces membuang spasi di awal/akhir dan menyatukan spasi ganda
+func CollapseSpaces(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// canonical mencari ejaan baku dari value di daftar allowed (tidak peka huruf besar/kecil)
+func canonical(value string, allowed []string) string {
+	for _, a := range allowed {
+		if strings.EqualFold(a, value) {
+			return a
+		}
+	}
+	return ""
+}
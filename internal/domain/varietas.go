@@ -7,23 +7,60 @@ import (
 )
 
 type VarietasPadi struct {
-	ID               int       `json:"id_padi"`
-	VarietasKelas    string    `json:"varietas_kelas"`
-	Warna            string    `json:"warna"`
-	PanjangBijiMM    float64   `json:"panjang_biji_mm"`
+	ID            int     `json:"id_padi"`
+	VarietasKelas string  `json:"varietas_kelas"`
+	Warna         string  `json:"warna"`
+	PanjangBijiMM float64 `json:"panjang_biji_mm"`
+	// LebarBijiMM opsional (null jika belum diukur); dipakai untuk bentuk biji
+	LebarBijiMM      *float64  `json:"lebar_biji_mm"`
 	TeksturPermukaan string    `json:"tekstur_permukaan"`
 	BentukUjungDaun  string    `json:"bentuk_ujung_daun"`
 	WaktuPembuatan   time.Time `json:"waktu_pembuatan"`
+	// Versi naik setiap kali baris diubah; dipakai sebagai ETag untuk optimistic concurrency
+	Versi int `json:"versi"`
+	// DeletedAt terisi jika data sudah dipindah ke tempat sampah (soft delete)
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	// DibuatOleh adalah aktor yang membuat data (lihat ActorFromContext). Diisi service saat
+	// create dan tidak pernah berubah; dipakai hak akses "data sendiri" (WithPemilikWajib).
+	DibuatOleh string `json:"dibuat_oleh,omitempty"`
 }
 
 // VarietasRepository Interface (Kontrak Data Access)
+// Semua method memakai transaksi aktif di ctx jika ada (lihat TxManager).
 type VarietasRepository interface {
 	// SEMUA FUNGSI CRUD DITAMBAH context.Context SEBAGAI ARGUMEN PERTAMA
 	Create(ctx context.Context, data VarietasPadi) (VarietasPadi, error)
+	// CreateBulk menyimpan banyak data dalam satu transaksi (semua atau tidak sama sekali)
+	// dan mengembalikannya dengan ID terisi, dalam urutan yang sama
+	CreateBulk(ctx context.Context, data []VarietasPadi) ([]VarietasPadi, error)
 	FindByID(ctx context.Context, id int) (VarietasPadi, error)
 	FindAll(ctx context.Context) ([]VarietasPadi, error) // FIX ERROR: Menambah context.Context
+	// FindPage mengambil satu halaman data sesuai VarietasQuery (filter, sort, pagination)
+	FindPage(ctx context.Context, q VarietasQuery) (VarietasPage, error)
+	// Iterate memanggil fn untuk setiap data yang cocok dengan filter dan sort q (tanpa pagination).
+	// Data dibaca bertahap dengan cursor sehingga pemakaian memori tidak bergantung jumlah data.
+	// Jika fn mengembalikan error, iterasi berhenti dan error itu dikembalikan.
+	Iterate(ctx context.Context, q VarietasQuery, fn func(VarietasPadi) error) error
+	// Lock membaca data id dan menguncinya sampai transaksi di ctx selesai (SELECT ... FOR UPDATE),
+	// sehingga cek versi di service dan penulisan sesudahnya bersifat atomik.
+	// terhapus memilih data di tempat sampah alih-alih data aktif. sql.ErrNoRows jika tidak ada.
+	Lock(ctx context.Context, id int, terhapus bool) (VarietasPadi, error)
+	// LockDuplikat mengunci kunci duplikat (lihat KunciDuplikat) sampai transaksi di ctx
+	// selesai, sehingga pemeriksaan duplikat dan penyimpanan data dengan kunci yang sama
+	// dari transaksi lain harus menunggu
+	LockDuplikat(ctx context.Context, kunci string) error
+	// Update menulis semua field data dan mengembalikan data dengan versi baru
 	Update(ctx context.Context, data VarietasPadi) (VarietasPadi, error)
-	Delete(ctx context.Context, id int) error
+	// Patch hanya menulis kolom yang diisi di VarietasPatch, lalu mengembalikan data lengkap
+	Patch(ctx context.Context, id int, patch VarietasPatch) (VarietasPadi, error)
+	// Delete memindahkan data ke tempat sampah (soft delete); data tidak lagi terlihat
+	// oleh FindAll, FindByID, dan FindPage kecuali VarietasQuery.Terhapus diisi.
+	Delete(ctx context.Context, id int) (VarietasPadi, error)
+	// Restore mengeluarkan data dari tempat sampah. sql.ErrNoRows jika id tidak ada di sampah.
+	Restore(ctx context.Context, id int) (VarietasPadi, error)
+	// Purge menghapus permanen data yang masuk tempat sampah sebelum waktu tertentu
+	// dan mengembalikan data yang dihapus
+	Purge(ctx context.Context, before time.Time) ([]VarietasPadi, error)
 }
 
 // VarietasService Interface (Kontrak Logika Bisnis)
@@ -31,8 +68,55 @@ type VarietasService interface {
 	// SEMUA FUNGSI SERVICE DITAMBAH context.Context SEBAGAI ARGUMEN PERTAMA
 	// Ini adalah kontrak lengkap untuk CRUD (sudah benar, hanya perlu context)
 	TambahkanData(ctx context.Context, data VarietasPadi) (VarietasPadi, error)
-	DapatkanDataByID(ctx context.Context, id int) (VarietasPadi, error)    // FIX ERROR: Menambah context.Context
-	DapatkanSemuaData(ctx context.Context) ([]VarietasPadi, error)         // FIX ERROR: Menambah context.Context
+	// ImporData memvalidasi setiap baris lalu menyimpan baris yang valid sekaligus.
+	// Baris yang tidak valid dilaporkan per baris dan tidak menggagalkan baris lain.
+	ImporData(ctx context.Context, baris []BarisImpor, dryRun bool) (HasilImpor, error)
+	DapatkanDataByID(ctx context.Context, id int) (VarietasPadi, error) // FIX ERROR: Menambah context.Context
+	DapatkanSemuaData(ctx context.Context, q VarietasQuery) (VarietasPage, error)
+	// EksporData mengalirkan semua data yang cocok dengan q ke fn, satu per satu
+	EksporData(ctx context.Context, q VarietasQuery, fn func(VarietasPadi) error) error
 	UbahData(ctx context.Context, data VarietasPadi) (VarietasPadi, error) // FIX ERROR: Menambah context.Context
-	HapusData(ctx context.Context, id int) error                           // FIX ERROR: Menambah context.Context
+	HapusData(ctx context.Context, id int, versi int) error                // versi 0 berarti tanpa syarat If-Match
+	// UbahSebagian menerapkan patch (JSON Merge Patch / JSON Patch) dan memvalidasi hasil gabungannya
+	UbahSebagian(ctx context.Context, id int, patch VarietasPatch) (VarietasPadi, error)
+	// DaftarSampah, PulihkanData, dan BersihkanSampah mengelola data yang sudah dihapus
+	DaftarSampah(ctx context.Context, q VarietasQuery) (VarietasPage, error)
+	PulihkanData(ctx context.Context, id int) (VarietasPadi, error)
+	BersihkanSampah(ctx context.Context, retensi time.Duration) (int, error)
+	// RiwayatData mengembalikan timeline perubahan satu data
+	RiwayatData(ctx context.Context, id int) ([]Revisi, error)
+	// KembalikanRevisi mengubah data kembali ke keadaan pada revisi tertentu (versi 0 = tanpa If-Match)
+	KembalikanRevisi(ctx context.Context, id int, revisiID int64, versi int) (VarietasPadi, error)
+	// JalankanBatch menjalankan banyak operasi create/update/delete dalam satu transaksi.
+	// Jika atomic, satu kegagalan membatalkan semuanya; jika tidak, hanya operasi yang gagal
+	// yang dibatalkan.
+	JalankanBatch(ctx context.Context, ops []OperasiBatch, atomic bool) (HasilBatch, error)
+	// PrediksiKelas menebak k varietas_kelas paling mungkin dari ciri morfologi sampel
+	// (VarietasKelas sampel diabaikan). Model dilatih ulang otomatis jika data berubah.
+	PrediksiKelas(ctx context.Context, sampel VarietasPadi, k int) (HasilPrediksi, error)
+	// LatihUlangModel melatih ulang model klasifikasi dari data terbaru
+	LatihUlangModel(ctx context.Context) (InfoModel, error)
+	// EvaluasiKlasifikasi mengukur kualitas model dengan stratified k-fold cross-validation
+	EvaluasiKlasifikasi(ctx context.Context, lipatan int) (EvaluasiModel, error)
+	// StatistikData menghitung statistik deskriptif data yang cocok dengan filter q,
+	// per grup nilai field groupBy (kosong berarti semua data satu grup)
+	StatistikData(ctx context.Context, q VarietasQuery, groupBy string) (StatistikVarietas, error)
+	// CariMirip mengembalikan k data yang paling mirip dengan ciri morfologi sampel
+	CariMirip(ctx context.Context, sampel VarietasPadi, k int, bobot BobotJarak) (HasilKemiripan, error)
+	// CariMiripDariID mengembalikan k data lain yang paling mirip dengan data id
+	CariMiripDariID(ctx context.Context, id int, k int, bobot BobotJarak) (HasilKemiripan, error)
+	// PeriksaKualitas mendaftar temuan kualitas data (outlier, duplikat, nilai langka)
+	PeriksaKualitas(ctx context.Context, f FilterKualitas) (LaporanKualitas, error)
+	// AkuiTemuan menandai temuan sudah ditinjau dan datanya benar (sampai data berubah)
+	AkuiTemuan(ctx context.Context, kode, catatan string) (TemuanKualitas, error)
+	// BatalkanPengakuan membuka kembali temuan yang sudah diakui
+	BatalkanPengakuan(ctx context.Context, kode string) error
+	// PerbaikiTemuan memperbaiki data penyebab temuan; nilai nil berarti memakai saran.
+	// Mengembalikan nil jika data dipindahkan ke tempat sampah (duplikat).
+	PerbaikiTemuan(ctx context.Context, kode string, nilai any) (*VarietasPadi, error)
+	// DapatkanKandidatDuplikat mengembalikan data lain yang kemungkinan sampel fisik yang sama
+	DapatkanKandidatDuplikat(ctx context.Context, data VarietasPadi) ([]VarietasPadi, error)
+	// GabungkanData menggabungkan beberapa data ke satu data utama; ID yang digabung
+	// selanjutnya diarahkan ke data utama
+	GabungkanData(ctx context.Context, req PermintaanGabung) (HasilGabung, error)
 }
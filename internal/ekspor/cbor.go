@@ -0,0 +1,184 @@
+package ekspor
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"math"
+	"time"
+
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
+)
+
+func init() {
+	Register(Format{Nama: "cbor", ContentType: "application/cbor", Ekstensi: "cbor",
+		New: NewCBORWriter, Satu: tulisCBORSatu})
+}
+
+// Major type CBOR (RFC 8949 bagian 3.1)
+const (
+	cborUint   = 0
+	cborNegInt = 1
+	cborText   = 3
+	cborArray  = 4
+	cborMap    = 5
+	cborTag    = 6
+)
+
+const (
+	cborArrayTakHingga = 0x9f // array dengan panjang tak tentu (indefinite-length)
+	cborBreak          = 0xff
+	cborFloat64        = 0xfb
+	cborNull           = 0xf6
+	cborTagWaktuTeks   = 0 // tag 0: waktu RFC 3339 dalam text string
+)
+
+// cborWriter menulis daftar VarietasPadi sebagai satu array CBOR panjang tak tentu,
+// sehingga jumlah data tidak perlu diketahui di awal. Setiap data berupa map dengan
+// kunci yang sama dengan field JSON.
+type cborWriter struct {
+	enc     cborEncoder
+	started bool
+}
+
+// NewCBORWriter membuat Writer CBOR
+func NewCBORWriter(w io.Writer) Writer {
+	return &cborWriter{enc: cborEncoder{w: bufio.NewWriter(w)}}
+}
+
+func (c *cborWriter) start() {
+	if !c.started {
+		c.started = true
+		c.enc.w.WriteByte(cborArrayTakHingga)
+	}
+}
+
+func (c *cborWriter) Write(v domain.VarietasPadi) error {
+	c.start()
+	c.enc.varietas(v)
+	return c.enc.err()
+}
+
+func (c *cborWriter) Close() error {
+	c.start()
+	c.enc.w.WriteByte(cborBreak)
+	if err := c.enc.err(); err != nil {
+		return err
+	}
+	return c.enc.w.Flush()
+}
+
+// tulisCBORSatu menulis satu data sebagai satu map CBOR
+func tulisCBORSatu(w io.Writer, v domain.VarietasPadi) error {
+	enc := cborEncoder{w: bufio.NewWriter(w)}
+	enc.varietas(v)
+	if err := enc.err(); err != nil {
+		return err
+	}
+	return enc.w.Flush()
+}
+
+// cborEncoder adalah encoder CBOR minimal untuk tipe yang dipakai VarietasPadi.
+// Kesalahan tulis disimpan oleh bufio.Writer dan dicek sekali lewat err().
+type cborEncoder struct {
+	w *bufio.Writer
+}
+
+func (e cborEncoder) err() error {
+	// Write kosong tidak menulis apa pun tetapi mengembalikan error tulis pertama
+	_, err := e.w.Write(nil)
+	return err
+}
+
+// head menulis byte awal item: major type dan argumen (panjang atau nilai)
+func (e cborEncoder) head(major byte, n uint64) {
+	switch {
+	case n < 24:
+		e.w.WriteByte(major<<5 | byte(n))
+	case n <= math.MaxUint8:
+		e.w.Write([]byte{major<<5 | 24, byte(n)})
+	case n <= math.MaxUint16:
+		b := []byte{major<<5 | 25, 0, 0}
+		binary.BigEndian.PutUint16(b[1:], uint16(n))
+		e.w.Write(b)
+	case n <= math.MaxUint32:
+		b := []byte{major<<5 | 26, 0, 0, 0, 0}
+		binary.BigEndian.PutUint32(b[1:], uint32(n))
+		e.w.Write(b)
+	default:
+		b := []byte{major<<5 | 27, 0, 0, 0, 0, 0, 0, 0, 0}
+		binary.BigEndian.PutUint64(b[1:], n)
+		e.w.Write(b)
+	}
+}
+
+func (e cborEncoder) int(n int) {
+	if n >= 0 {
+		e.head(cborUint, uint64(n))
+		return
+	}
+	e.head(cborNegInt, uint64(-1-n))
+}
+
+func (e cborEncoder) text(s string) {
+	e.head(cborText, uint64(len(s)))
+	e.w.WriteString(s)
+}
+
+func (e cborEncoder) float(f float64) {
+	b := []byte{cborFloat64, 0, 0, 0, 0, 0, 0, 0, 0}
+	binary.BigEndian.PutUint64(b[1:], math.Float64bits(f))
+	e.w.Write(b)
+}
+
+func (e cborEncoder) time(t time.Time) {
+	e.head(cborTag, cborTagWaktuTeks)
+	e.text(t.UTC().Format(time.RFC3339Nano))
+}
+
+// varietas menulis satu map dengan urutan kunci sesuai Kolom; sama seperti JSON,
+// lebar_biji_mm kosong ditulis null, sedangkan bentuk_biji dan deleted_at hanya ditulis jika terisi
+func (e cborEncoder) varietas(v domain.VarietasPadi) {
+	n := len(Kolom)
+	bentuk := v.BentukBiji()
+	if bentuk == "" {
+		n--
+	}
+	if v.DeletedAt != nil {
+		n++
+	}
+	e.head(cborMap, uint64(n))
+
+	e.text("id_padi")
+	e.int(v.ID)
+	e.text("varietas_kelas")
+	e.text(v.VarietasKelas)
+	e.text("warna")
+	e.text(v.Warna)
+	e.text("panjang_biji_mm")
+	e.float(v.PanjangBijiMM)
+	e.text("tekstur_permukaan")
+	e.text(v.TeksturPermukaan)
+	e.text("bentuk_ujung_daun")
+	e.text(v.BentukUjungDaun)
+	e.text("waktu_pembuatan")
+	e.time(v.WaktuPembuatan)
+	e.text("versi")
+	e.int(v.Versi)
+	e.text("lebar_biji_mm")
+	if v.LebarBijiMM != nil {
+		e.float(*v.LebarBijiMM)
+	} else {
+		e.w.WriteByte(cborNull)
+	}
+	e.text("kategori_panjang")
+	e.text(v.KategoriPanjang())
+	if bentuk != "" {
+		e.text("bentuk_biji")
+		e.text(bentuk)
+	}
+	if v.DeletedAt != nil {
+		e.text("deleted_at")
+		e.time(*v.DeletedAt)
+	}
+}
@@ -0,0 +1,321 @@
+package ekspor
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
+)
+
+// cborTagged adalah item bertag hasil decode
+type cborTagged struct {
+	Tag   uint64
+	Nilai any
+}
+
+// cborReader adalah decoder CBOR untuk test yang hanya mendukung tipe yang dipakai encoder
+// (uint, negint, text, array, map, tag, float64, null). Map dibaca menjadi map[string]any
+// dan kunci duplikat ditolak.
+type cborReader struct {
+	b   []byte
+	pos int
+}
+
+func (r *cborReader) argumen(info byte) (uint64, error) {
+	ambil := func(n int) ([]byte, error) {
+		if r.pos+n > len(r.b) {
+			return nil, fmt.Errorf("cbor: data habis di posisi %d", r.pos)
+		}
+		b := r.b[r.pos : r.pos+n]
+		r.pos += n
+		return b, nil
+	}
+	switch {
+	case info < 24:
+		return uint64(info), nil
+	case info == 24:
+		b, err := ambil(1)
+		if err != nil {
+			return 0, err
+		}
+		return uint64(b[0]), nil
+	case info == 25:
+		b, err := ambil(2)
+		if err != nil {
+			return 0, err
+		}
+		return uint64(binary.BigEndian.Uint16(b)), nil
+	case info == 26:
+		b, err := ambil(4)
+		if err != nil {
+			return 0, err
+		}
+		return uint64(binary.BigEndian.Uint32(b)), nil
+	case info == 27:
+		b, err := ambil(8)
+		if err != nil {
+			return 0, err
+		}
+		return binary.BigEndian.Uint64(b), nil
+	}
+	return 0, fmt.Errorf("cbor: additional info %d tidak didukung", info)
+}
+
+func (r *cborReader) item() (any, error) {
+	if r.pos >= len(r.b) {
+		return nil, fmt.Errorf("cbor: data habis di posisi %d", r.pos)
+	}
+	awal := r.b[r.pos]
+	r.pos++
+	major, info := awal>>5, awal&0x1f
+
+	switch awal {
+	case cborNull:
+		return nil, nil
+	case cborArrayTakHingga:
+		list := []any{}
+		for {
+			if r.pos < len(r.b) && r.b[r.pos] == cborBreak {
+				r.pos++
+				return list, nil
+			}
+			v, err := r.item()
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+		}
+	}
+	if major == 7 {
+		if info != 27 {
+			return nil, fmt.Errorf("cbor: simple/float 0x%02x tidak didukung", awal)
+		}
+		n, err := r.argumen(info)
+		return math.Float64frombits(n), err
+	}
+
+	n, err := r.argumen(info)
+	if err != nil {
+		return nil, err
+	}
+	switch major {
+	case cborUint:
+		return int64(n), nil
+	case cborNegInt:
+		return -1 - int64(n), nil
+	case cborText:
+		if r.pos+int(n) > len(r.b) {
+			return nil, fmt.Errorf("cbor: text melewati akhir data")
+		}
+		s := string(r.b[r.pos : r.pos+int(n)])
+		r.pos += int(n)
+		return s, nil
+	case cborArray:
+		list := make([]any, n)
+		for i := range list {
+			if list[i], err = r.item(); err != nil {
+				return nil, err
+			}
+		}
+		return list, nil
+	case cborMap:
+		m := make(map[string]any, n)
+		for range n {
+			k, err := r.item()
+			if err != nil {
+				return nil, err
+			}
+			kunci, ok := k.(string)
+			if !ok {
+				return nil, fmt.Errorf("cbor: kunci map bukan text: %v", k)
+			}
+			if _, dup := m[kunci]; dup {
+				return nil, fmt.Errorf("cbor: kunci %q muncul dua kali", kunci)
+			}
+			if m[kunci], err = r.item(); err != nil {
+				return nil, err
+			}
+		}
+		return m, nil
+	case cborTag:
+		v, err := r.item()
+		return cborTagged{Tag: n, Nilai: v}, err
+	}
+	return nil, fmt.Errorf("cbor: major type %d tidak didukung", major)
+}
+
+func decodeCBOR(t *testing.T, b []byte) any {
+	t.Helper()
+	r := &cborReader{b: b}
+	v, err := r.item()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.pos != len(b) {
+		t.Fatalf("cbor: sisa %d byte setelah item", len(b)-r.pos)
+	}
+	return v
+}
+
+// encodeCBOR menjalankan fn dengan cborEncoder lalu mengembalikan hasilnya
+func encodeCBOR(fn func(e cborEncoder)) []byte {
+	var buf bytes.Buffer
+	e := cborEncoder{w: bufio.NewWriter(&buf)}
+	fn(e)
+	e.w.Flush()
+	return buf.Bytes()
+}
+
+// TestCBORVektorRFC8949 memakai contoh encoding dari RFC 8949 Appendix A
+func TestCBORVektorRFC8949(t *testing.T) {
+	tests := []struct {
+		nama string
+		fn   func(e cborEncoder)
+		hex  string
+	}{
+		{"0", func(e cborEncoder) { e.int(0) }, "00"},
+		{"1", func(e cborEncoder) { e.int(1) }, "01"},
+		{"10", func(e cborEncoder) { e.int(10) }, "0a"},
+		{"23", func(e cborEncoder) { e.int(23) }, "17"},
+		{"24", func(e cborEncoder) { e.int(24) }, "1818"},
+		{"25", func(e cborEncoder) { e.int(25) }, "1819"},
+		{"100", func(e cborEncoder) { e.int(100) }, "1864"},
+		{"1000", func(e cborEncoder) { e.int(1000) }, "1903e8"},
+		{"1000000", func(e cborEncoder) { e.int(1000000) }, "1a000f4240"},
+		{"1000000000000", func(e cborEncoder) { e.int(1000000000000) }, "1b000000e8d4a51000"},
+		{"18446744073709551615", func(e cborEncoder) { e.head(cborUint, math.MaxUint64) }, "1bffffffffffffffff"},
+		{"-1", func(e cborEncoder) { e.int(-1) }, "20"},
+		{"-10", func(e cborEncoder) { e.int(-10) }, "29"},
+		{"-100", func(e cborEncoder) { e.int(-100) }, "3863"},
+		{"-1000", func(e cborEncoder) { e.int(-1000) }, "3903e7"},
+		{"1.1", func(e cborEncoder) { e.float(1.1) }, "fb3ff199999999999a"},
+		{"1.0e+300", func(e cborEncoder) { e.float(1.0e+300) }, "fb7e37e43c8800759c"},
+		{"-4.1", func(e cborEncoder) { e.float(-4.1) }, "fbc010666666666666"},
+		{`""`, func(e cborEncoder) { e.text("") }, "60"},
+		{`"a"`, func(e cborEncoder) { e.text("a") }, "6161"},
+		{`"IETF"`, func(e cborEncoder) { e.text("IETF") }, "6449455446"},
+		{`"\"\\"`, func(e cborEncoder) { e.text("\"\\") }, "62225c"},
+		{`"ü"`, func(e cborEncoder) { e.text("ü") }, "62c3bc"},
+		{`"水"`, func(e cborEncoder) { e.text("水") }, "63e6b0b4"},
+		{`0("2013-03-21T20:04:00Z")`, func(e cborEncoder) { e.time(time.Date(2013, 3, 21, 20, 4, 0, 0, time.UTC)) },
+			"c074323031332d30332d32315432303a30343a30305a"},
+		{"{}", func(e cborEncoder) { e.head(cborMap, 0) }, "a0"},
+		{`{"a": 1, "b": [2, 3]}`, func(e cborEncoder) {
+			e.head(cborMap, 2)
+			e.text("a")
+			e.int(1)
+			e.text("b")
+			e.head(cborArray, 2)
+			e.int(2)
+			e.int(3)
+		}, "a26161016162820203"},
+		{"teks 24 byte", func(e cborEncoder) { e.text("abcdefghijklmnopqrstuvwx") }, "7818" + hex.EncodeToString([]byte("abcdefghijklmnopqrstuvwx"))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nama, func(t *testing.T) {
+			got := hex.EncodeToString(encodeCBOR(tt.fn))
+			if got != tt.hex {
+				t.Errorf("encode = %s, ingin %s", got, tt.hex)
+			}
+		})
+	}
+}
+
+func TestCBORWaktuZonaLain(t *testing.T) {
+	wib := time.FixedZone("WIB", 7*3600)
+	got := decodeCBOR(t, encodeCBOR(func(e cborEncoder) {
+		e.time(time.Date(2025, 10, 17, 15, 45, 0, 500, wib))
+	}))
+	want := cborTagged{Tag: cborTagWaktuTeks, Nilai: "2025-10-17T08:45:00.0000005Z"}
+	if got != want {
+		t.Errorf("decode = %#v, ingin %#v", got, want)
+	}
+}
+
+// petaCBOR adalah map yang diharapkan untuk v setelah di-decode
+func petaCBOR(v domain.VarietasPadi) map[string]any {
+	m := map[string]any{
+		"id_padi":           int64(v.ID),
+		"varietas_kelas":    v.VarietasKelas,
+		"warna":             v.Warna,
+		"panjang_biji_mm":   v.PanjangBijiMM,
+		"tekstur_permukaan": v.TeksturPermukaan,
+		"bentuk_ujung_daun": v.BentukUjungDaun,
+		"waktu_pembuatan":   cborTagged{Tag: cborTagWaktuTeks, Nilai: v.WaktuPembuatan.UTC().Format(time.RFC3339Nano)},
+		"versi":             int64(v.Versi),
+		"lebar_biji_mm":     nil,
+		"kategori_panjang":  v.KategoriPanjang(),
+	}
+	if v.LebarBijiMM != nil {
+		m["lebar_biji_mm"] = *v.LebarBijiMM
+	}
+	if b := v.BentukBiji(); b != "" {
+		m["bentuk_biji"] = b
+	}
+	if v.DeletedAt != nil {
+		m["deleted_at"] = cborTagged{Tag: cborTagWaktuTeks, Nilai: v.DeletedAt.UTC().Format(time.RFC3339Nano)}
+	}
+	return m
+}
+
+func TestCBORRoundTrip(t *testing.T) {
+	data := contohBaris(30) // termasuk lebar biji kosong (null, tanpa bentuk_biji)
+	data[0].VarietasKelas = "Pandan Wangi 水 \"kutip\""
+	data[1].ID = 70000 // argumen 4 byte
+	dihapus := time.Date(2025, 10, 18, 1, 2, 3, 0, time.UTC)
+	data[2].DeletedAt = &dihapus
+
+	var buf bytes.Buffer
+	w := NewCBORWriter(&buf)
+	if buf.Len() != 0 {
+		t.Fatal("writer menulis sebelum Write pertama")
+	}
+	for _, v := range data {
+		if err := w.Write(v); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if b := buf.Bytes(); b[0] != cborArrayTakHingga || b[len(b)-1] != cborBreak {
+		t.Fatalf("daftar bukan array panjang tak tentu: awal 0x%02x akhir 0x%02x", b[0], b[len(b)-1])
+	}
+
+	got := decodeCBOR(t, buf.Bytes()).([]any)
+	if len(got) != len(data) {
+		t.Fatalf("jumlah item = %d, ingin %d", len(got), len(data))
+	}
+	for i, v := range data {
+		if want := petaCBOR(v); !reflect.DeepEqual(got[i], want) {
+			t.Errorf("item %d = %v\ningin %v", i, got[i], want)
+		}
+	}
+}
+
+func TestCBORSatuDanKosong(t *testing.T) {
+	v := contohBaris(1)[0]
+	var buf bytes.Buffer
+	if err := tulisCBORSatu(&buf, v); err != nil {
+		t.Fatal(err)
+	}
+	if got := decodeCBOR(t, buf.Bytes()); !reflect.DeepEqual(got, petaCBOR(v)) {
+		t.Errorf("satu data = %v, ingin %v", got, petaCBOR(v))
+	}
+
+	buf.Reset()
+	w := NewCBORWriter(&buf)
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got := hex.EncodeToString(buf.Bytes()); got != "9fff" {
+		t.Errorf("daftar kosong = %s, ingin 9fff", got)
+	}
+}
@@ -0,0 +1,46 @@
+package ekspor
+
+import (
+	"encoding/csv"
+	"io"
+
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
+)
+
+func init() {
+	Register(Format{Nama: "csv", ContentType: "text/csv; charset=utf-8", Ekstensi: "csv", New: NewCSVWriter})
+}
+
+// csvWriter menulis CSV dengan baris header Kolom
+type csvWriter struct {
+	w       *csv.Writer
+	started bool
+}
+
+// NewCSVWriter membuat Writer CSV (pemisah koma, UTF-8)
+func NewCSVWriter(w io.Writer) Writer {
+	return &csvWriter{w: csv.NewWriter(w)}
+}
+
+func (c *csvWriter) start() error {
+	if c.started {
+		return nil
+	}
+	c.started = true
+	return c.w.Write(Kolom)
+}
+
+func (c *csvWriter) Write(v domain.VarietasPadi) error {
+	if err := c.start(); err != nil {
+		return err
+	}
+	return c.w.Write(nilaiTeks(v))
+}
+
+func (c *csvWriter) Close() error {
+	if err := c.start(); err != nil {
+		return err
+	}
+	c.w.Flush()
+	return c.w.Error()
+}
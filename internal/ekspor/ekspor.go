@@ -0,0 +1,107 @@
+// Package ekspor berisi penulis (writer) streaming untuk mengekspor VarietasPadi
+// ke berbagai format file. Setiap writer menulis satu baris per panggilan Write
+// sehingga pemakaian memori tetap datar berapa pun jumlah datanya.
+package ekspor
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
+)
+
+// Kolom adalah urutan kolom yang stabil untuk semua format ekspor. Kolom baru selalu
+// ditambahkan di akhir agar posisi kolom lama tidak bergeser bagi pemakai file ekspor.
+var Kolom = []string{
+	"id_padi", "varietas_kelas", "warna", "panjang_biji_mm",
+	"tekstur_permukaan", "bentuk_ujung_daun", "waktu_pembuatan", "versi",
+	"lebar_biji_mm", "kategori_panjang", "bentuk_biji",
+}
+
+// Writer menulis data satu per satu ke format tertentu.
+// Close wajib dipanggil untuk menulis bagian penutup file (misalnya footer Parquet).
+// Writer tidak menulis apa pun ke io.Writer tujuan sebelum Write atau Close pertama,
+// sehingga pemanggil masih bisa mengirim response error jika gagal sebelum data pertama.
+type Writer interface {
+	Write(v domain.VarietasPadi) error
+	Close() error
+}
+
+// Format adalah satu format ekspor yang didukung
+type Format struct {
+	Nama        string // nilai parameter ?format=
+	ContentType string
+	Ekstensi    string
+	New         func(w io.Writer) Writer
+
+	// Satu menulis satu data saja (bukan daftar), misalnya untuk GET /varietas/{id}.
+	// Boleh nil; jika nil, satu data ditulis sebagai daftar berisi satu elemen.
+	Satu func(w io.Writer, v domain.VarietasPadi) error
+}
+
+// TulisSatu menulis satu data dengan format f
+func (f Format) TulisSatu(w io.Writer, v domain.VarietasPadi) error {
+	if f.Satu != nil {
+		return f.Satu(w, v)
+	}
+	writer := f.New(w)
+	if err := writer.Write(v); err != nil {
+		return err
+	}
+	return writer.Close()
+}
+
+// formats adalah registry format ekspor berdasarkan nama
+var formats = map[string]Format{}
+
+// Register menambahkan format ekspor baru ke registry
+func Register(f Format) {
+	formats[f.Nama] = f
+}
+
+// Lookup mencari format berdasarkan nama
+func Lookup(nama string) (Format, bool) {
+	f, ok := formats[nama]
+	return f, ok
+}
+
+// Names mengembalikan nama semua format yang terdaftar, terurut
+func Names() []string {
+	names := make([]string, 0, len(formats))
+	for n := range formats {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NamaFile membentuk nama file ekspor yang memuat waktu ekspor (UTC),
+// misalnya varietas_20251017T084500Z.csv
+func NamaFile(f Format, waktu time.Time) string {
+	return fmt.Sprintf("varietas_%s.%s", waktu.UTC().Format("20060102T150405Z"), f.Ekstensi)
+}
+
+// nilaiTeks mengembalikan nilai kolom sebagai teks, sesuai urutan Kolom.
+// Nilai opsional yang kosong ditulis sebagai teks kosong.
+func nilaiTeks(v domain.VarietasPadi) []string {
+	lebar := ""
+	if v.LebarBijiMM != nil {
+		lebar = strconv.FormatFloat(*v.LebarBijiMM, 'f', -1, 64)
+	}
+	return []string{
+		strconv.Itoa(v.ID),
+		v.VarietasKelas,
+		v.Warna,
+		strconv.FormatFloat(v.PanjangBijiMM, 'f', -1, 64),
+		v.TeksturPermukaan,
+		v.BentukUjungDaun,
+		v.WaktuPembuatan.UTC().Format(time.RFC3339),
+		strconv.Itoa(v.Versi),
+		lebar,
+		v.KategoriPanjang(),
+		v.BentukBiji(),
+	}
+}
@@ -0,0 +1,30 @@
+package ekspor
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
+)
+
+func init() {
+	Register(Format{Nama: "ndjson", ContentType: "application/x-ndjson", Ekstensi: "ndjson", New: NewNDJSONWriter})
+}
+
+// ndjsonWriter menulis satu objek JSON VarietasPadi per baris
+type ndjsonWriter struct {
+	enc *json.Encoder
+}
+
+// NewNDJSONWriter membuat Writer NDJSON (newline-delimited JSON)
+func NewNDJSONWriter(w io.Writer) Writer {
+	return &ndjsonWriter{enc: json.NewEncoder(w)}
+}
+
+func (n *ndjsonWriter) Write(v domain.VarietasPadi) error {
+	return n.enc.Encode(v) // Encode menambahkan newline di akhir
+}
+
+func (n *ndjsonWriter) Close() error {
+	return nil
+}
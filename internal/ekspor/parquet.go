@@ -0,0 +1,308 @@
+package ekspor
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
+)
+
+func init() {
+	Register(Format{Nama: "parquet", ContentType: "application/vnd.apache.parquet", Ekstensi: "parquet", New: NewParquetWriter})
+}
+
+// Konstanta format Parquet (parquet.thrift) yang dipakai writer ini
+const (
+	parquetMagic = "PAR1"
+
+	parquetInt32     = 1
+	parquetInt64     = 2
+	parquetDouble    = 5
+	parquetByteArray = 6
+
+	parquetRequired = 0
+	parquetOptional = 1
+
+	parquetUTF8            = 0
+	parquetTimestampMicros = 10
+
+	parquetPlain = 0
+	parquetRLE   = 3
+
+	parquetUncompressed = 0
+	parquetDataPage     = 0
+)
+
+// barisPerRowGroup membatasi jumlah baris yang ditahan di memori sebelum ditulis
+const barisPerRowGroup = 10000
+
+// parquetColumn adalah satu kolom skema beserta buffer nilai row group yang sedang berjalan.
+// Kolom opsional juga mencatat definition level setiap baris (1 terisi, 0 null);
+// nilai null tidak ditulis ke buf.
+type parquetColumn struct {
+	nama      string
+	tipe      int32
+	converted int32 // -1 jika tanpa converted type
+	opsional  bool
+	buf       bytes.Buffer
+	definisi  []bool
+}
+
+// tulis mencatat satu nilai kolom opsional; put hanya dipanggil jika ada
+func (c *parquetColumn) tulis(ada bool, put func(*bytes.Buffer)) {
+	c.definisi = append(c.definisi, ada)
+	if ada {
+		put(&c.buf)
+	}
+}
+
+// parquetChunk adalah metadata satu column chunk yang sudah ditulis
+type parquetChunk struct {
+	offset int64
+	size   int64
+}
+
+type parquetRowGroup struct {
+	chunks  []parquetChunk
+	numRows int64
+	size    int64
+}
+
+// parquetWriter menulis file Parquet minimal: kolom REQUIRED atau OPTIONAL, encoding PLAIN,
+// tanpa kompresi, satu data page per column chunk. Baris dikumpulkan per row group
+// lalu ditulis; metadata (footer) ditulis di Close.
+type parquetWriter struct {
+	out       io.Writer
+	offset    int64
+	cols      []*parquetColumn
+	rows      int
+	rowGroups []parquetRowGroup
+	started   bool
+}
+
+// NewParquetWriter membuat Writer Parquet tanpa library pihak ketiga
+func NewParquetWriter(w io.Writer) Writer {
+	return &parquetWriter{
+		out: w,
+		cols: []*parquetColumn{
+			{nama: "id_padi", tipe: parquetInt32, converted: -1},
+			{nama: "varietas_kelas", tipe: parquetByteArray, converted: parquetUTF8},
+			{nama: "warna", tipe: parquetByteArray, converted: parquetUTF8},
+			{nama: "panjang_biji_mm", tipe: parquetDouble, converted: -1},
+			{nama: "tekstur_permukaan", tipe: parquetByteArray, converted: parquetUTF8},
+			{nama: "bentuk_ujung_daun", tipe: parquetByteArray, converted: parquetUTF8},
+			{nama: "waktu_pembuatan", tipe: parquetInt64, converted: parquetTimestampMicros},
+			{nama: "versi", tipe: parquetInt32, converted: -1},
+			{nama: "lebar_biji_mm", tipe: parquetDouble, converted: -1, opsional: true},
+			{nama: "kategori_panjang", tipe: parquetByteArray, converted: parquetUTF8},
+			{nama: "bentuk_biji", tipe: parquetByteArray, converted: parquetUTF8, opsional: true},
+		},
+	}
+}
+
+func (p *parquetWriter) write(b []byte) error {
+	n, err := p.out.Write(b)
+	p.offset += int64(n)
+	return err
+}
+
+func (p *parquetWriter) start() error {
+	if p.started {
+		return nil
+	}
+	p.started = true
+	return p.write([]byte(parquetMagic))
+}
+
+func (p *parquetWriter) Write(v domain.VarietasPadi) error {
+	if err := p.start(); err != nil {
+		return err
+	}
+
+	// Urutan harus sama dengan p.cols (dan Kolom)
+	putInt32(&p.cols[0].buf, int32(v.ID))
+	putByteArray(&p.cols[1].buf, v.VarietasKelas)
+	putByteArray(&p.cols[2].buf, v.Warna)
+	putDouble(&p.cols[3].buf, v.PanjangBijiMM)
+	putByteArray(&p.cols[4].buf, v.TeksturPermukaan)
+	putByteArray(&p.cols[5].buf, v.BentukUjungDaun)
+	putInt64(&p.cols[6].buf, v.WaktuPembuatan.UnixMicro())
+	putInt32(&p.cols[7].buf, int32(v.Versi))
+	p.cols[8].tulis(v.LebarBijiMM != nil, func(b *bytes.Buffer) { putDouble(b, *v.LebarBijiMM) })
+	putByteArray(&p.cols[9].buf, v.KategoriPanjang())
+	bentuk := v.BentukBiji()
+	p.cols[10].tulis(bentuk != "", func(b *bytes.Buffer) { putByteArray(b, bentuk) })
+
+	p.rows++
+	if p.rows == barisPerRowGroup {
+		return p.flushRowGroup()
+	}
+	return nil
+}
+
+// flushRowGroup menulis buffer semua kolom sebagai satu row group
+func (p *parquetWriter) flushRowGroup() error {
+	rg := parquetRowGroup{numRows: int64(p.rows)}
+	for _, col := range p.cols {
+		data := col.buf.Bytes()
+		if col.opsional {
+			data = append(definitionLevels(col.definisi), data...)
+		}
+
+		h := newThriftWriter()
+		h.i32(1, parquetDataPage)
+		h.i32(2, int32(len(data))) // uncompressed_page_size
+		h.i32(3, int32(len(data))) // compressed_page_size
+		h.structField(5)           // data_page_header
+		h.i32(1, int32(p.rows))    // num_values
+		h.i32(2, parquetPlain)     // encoding
+		h.i32(3, parquetRLE)       // definition_level_encoding
+		h.i32(4, parquetRLE)       // repetition_level_encoding
+		h.end()
+		h.end()
+
+		chunk := parquetChunk{offset: p.offset, size: int64(len(h.bytes()) + len(data))}
+		if err := p.write(h.bytes()); err != nil {
+			return err
+		}
+		if err := p.write(data); err != nil {
+			return err
+		}
+		rg.chunks = append(rg.chunks, chunk)
+		rg.size += chunk.size
+		col.buf.Reset()
+		col.definisi = col.definisi[:0]
+	}
+	p.rowGroups = append(p.rowGroups, rg)
+	p.rows = 0
+	return nil
+}
+
+func (p *parquetWriter) Close() error {
+	if err := p.start(); err != nil {
+		return err
+	}
+	if p.rows > 0 {
+		if err := p.flushRowGroup(); err != nil {
+			return err
+		}
+	}
+
+	footer := p.footer()
+	if err := p.write(footer); err != nil {
+		return err
+	}
+	var size [4]byte
+	binary.LittleEndian.PutUint32(size[:], uint32(len(footer)))
+	if err := p.write(size[:]); err != nil {
+		return err
+	}
+	return p.write([]byte(parquetMagic))
+}
+
+// footer menyusun FileMetaData dalam Thrift Compact Protocol
+func (p *parquetWriter) footer() []byte {
+	var totalRows int64
+	for _, rg := range p.rowGroups {
+		totalRows += rg.numRows
+	}
+
+	t := newThriftWriter()
+	t.i32(1, 1) // version
+
+	// schema: elemen root lalu satu elemen per kolom
+	t.listField(2, thriftStruct, len(p.cols)+1)
+	t.elemStruct()
+	t.str(4, "schema")
+	t.i32(5, int32(len(p.cols))) // num_children
+	t.end()
+	for _, col := range p.cols {
+		t.elemStruct()
+		t.i32(1, col.tipe)
+		if col.opsional {
+			t.i32(3, parquetOptional)
+		} else {
+			t.i32(3, parquetRequired)
+		}
+		t.str(4, col.nama)
+		if col.converted >= 0 {
+			t.i32(6, col.converted)
+		}
+		t.end()
+	}
+
+	t.i64(3, totalRows)
+
+	t.listField(4, thriftStruct, len(p.rowGroups))
+	for _, rg := range p.rowGroups {
+		t.elemStruct()
+		t.listField(1, thriftStruct, len(rg.chunks))
+		for i, chunk := range rg.chunks {
+			col := p.cols[i]
+			t.elemStruct()
+			t.i64(2, chunk.offset) // file_offset
+			t.structField(3)       // meta_data
+			t.i32(1, col.tipe)
+			t.listField(2, thriftI32, 1)
+			t.elemI32(parquetPlain)
+			t.listField(3, thriftBinary, 1)
+			t.elemStr(col.nama)
+			t.i32(4, parquetUncompressed)
+			t.i64(5, rg.numRows)
+			t.i64(6, chunk.size)
+			t.i64(7, chunk.size)
+			t.i64(9, chunk.offset) // data_page_offset
+			t.end()
+			t.end()
+		}
+		t.i64(2, rg.size)
+		t.i64(3, rg.numRows)
+		t.end()
+	}
+
+	t.str(6, "REST-API_VarietasPadi")
+	t.end()
+	return t.bytes()
+}
+
+// definitionLevels mengenkode definition level (bit width 1) sebagai satu run bit-packed
+// encoding RLE/bit-packing hybrid, didahului panjangnya 4 byte little-endian seperti
+// yang diminta data page v1. Jumlah nilai dibulatkan ke kelipatan 8 dengan bit 0.
+func definitionLevels(ada []bool) []byte {
+	grup := (len(ada) + 7) / 8
+	run := binary.AppendUvarint(nil, uint64(grup)<<1|1) // header run bit-packed
+	bits := make([]byte, grup)
+	for i, a := range ada {
+		if a {
+			bits[i/8] |= 1 << (i % 8)
+		}
+	}
+	run = append(run, bits...)
+
+	out := binary.LittleEndian.AppendUint32(nil, uint32(len(run)))
+	return append(out, run...)
+}
+
+func putInt32(b *bytes.Buffer, v int32) {
+	var tmp [4]byte
+	binary.LittleEndian.PutUint32(tmp[:], uint32(v))
+	b.Write(tmp[:])
+}
+
+func putInt64(b *bytes.Buffer, v int64) {
+	var tmp [8]byte
+	binary.LittleEndian.PutUint64(tmp[:], uint64(v))
+	b.Write(tmp[:])
+}
+
+func putDouble(b *bytes.Buffer, v float64) {
+	putInt64(b, int64(math.Float64bits(v)))
+}
+
+// putByteArray menulis BYTE_ARRAY PLAIN: panjang 4 byte little-endian lalu isinya
+func putByteArray(b *bytes.Buffer, s string) {
+	putInt32(b, int32(len(s)))
+	b.WriteString(s)
+}
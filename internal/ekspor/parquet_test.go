@@ -0,0 +1,332 @@
+package ekspor
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
+)
+
+// contohBaris membuat n data dengan lebar biji kosong di setiap baris ketiga,
+// sehingga kolom opsional lebar_biji_mm dan bentuk_biji berisi null
+func contohBaris(n int) []domain.VarietasPadi {
+	waktu := time.Date(2025, 10, 17, 8, 45, 0, 123456000, time.UTC)
+	out := make([]domain.VarietasPadi, n)
+	for i := range out {
+		v := domain.VarietasPadi{
+			ID:               i + 1,
+			VarietasKelas:    []string{"IR64", "Ciherang", "Inpari 32"}[i%3],
+			Warna:            "Putih",
+			PanjangBijiMM:    5 + float64(i%40)/10,
+			TeksturPermukaan: "Halus",
+			BentukUjungDaun:  "Runcing",
+			WaktuPembuatan:   waktu.Add(time.Duration(i) * time.Second),
+			Versi:            1 + i%4,
+		}
+		if i%3 != 2 {
+			lebar := 2 + float64(i%10)/10
+			v.LebarBijiMM = &lebar
+		}
+		out[i] = v
+	}
+	return out
+}
+
+// nilaiParquet adalah nilai kolom yang diharapkan untuk v, sesuai urutan Kolom
+// (nil untuk null)
+func nilaiParquet(v domain.VarietasPadi) []any {
+	var lebar, bentuk any
+	if v.LebarBijiMM != nil {
+		lebar = *v.LebarBijiMM
+	}
+	if b := v.BentukBiji(); b != "" {
+		bentuk = b
+	}
+	return []any{
+		int32(v.ID), v.VarietasKelas, v.Warna, v.PanjangBijiMM, v.TeksturPermukaan,
+		v.BentukUjungDaun, v.WaktuPembuatan.UnixMicro(), int32(v.Versi), lebar,
+		v.KategoriPanjang(), bentuk,
+	}
+}
+
+func tulisParquet(t *testing.T, data []domain.VarietasPadi) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := NewParquetWriter(&buf)
+	for _, v := range data {
+		if err := w.Write(v); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// bacaFooter memeriksa magic di awal dan akhir file lalu mengembalikan FileMetaData
+// beserta posisi awal footer
+func bacaFooter(t *testing.T, file []byte) (map[int16]any, int) {
+	t.Helper()
+	if len(file) < 12 || string(file[:4]) != parquetMagic || string(file[len(file)-4:]) != parquetMagic {
+		t.Fatalf("file tidak diawali dan diakhiri %q", parquetMagic)
+	}
+	panjang := int(binary.LittleEndian.Uint32(file[len(file)-8:]))
+	awal := len(file) - 8 - panjang
+	if awal < 4 {
+		t.Fatalf("panjang footer %d melewati awal file", panjang)
+	}
+	meta, n := decodeThrift(t, file[awal:len(file)-8])
+	if n != panjang {
+		t.Fatalf("FileMetaData %d byte, panjang footer %d", n, panjang)
+	}
+	return meta, awal
+}
+
+// bacaLevel mengurai definition level (bit width 1) berencoding RLE/bit-packing hybrid
+func bacaLevel(t *testing.T, b []byte, n int) []bool {
+	t.Helper()
+	r := &thriftReader{b: b}
+	var out []bool
+	for len(out) < n {
+		h, err := r.varint()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if h&1 == 1 { // bit-packed: h>>1 grup berisi 8 nilai
+			for range h >> 1 {
+				c, err := r.byte()
+				if err != nil {
+					t.Fatal(err)
+				}
+				for bit := range 8 {
+					out = append(out, c&(1<<bit) != 0)
+				}
+			}
+			continue
+		}
+		c, err := r.byte() // RLE: h>>1 kali nilai 1 byte
+		if err != nil {
+			t.Fatal(err)
+		}
+		for range h >> 1 {
+			out = append(out, c == 1)
+		}
+	}
+	if r.pos != len(b) {
+		t.Fatalf("definition level %d byte, terbaca %d", len(b), r.pos)
+	}
+	return out[:n]
+}
+
+// bacaNilai mengurai satu nilai PLAIN bertipe tipe dari awal b
+func bacaNilai(t *testing.T, tipe int64, b []byte) (any, int) {
+	t.Helper()
+	switch tipe {
+	case parquetInt32:
+		return int32(binary.LittleEndian.Uint32(b)), 4
+	case parquetInt64:
+		return int64(binary.LittleEndian.Uint64(b)), 8
+	case parquetDouble:
+		return math.Float64frombits(binary.LittleEndian.Uint64(b)), 8
+	case parquetByteArray:
+		n := int(binary.LittleEndian.Uint32(b))
+		return string(b[4 : 4+n]), 4 + n
+	}
+	t.Fatalf("tipe fisik %d tidak dikenal", tipe)
+	return nil, 0
+}
+
+func TestParquetRoundTrip(t *testing.T) {
+	data := contohBaris(2*barisPerRowGroup + 3)
+	file := tulisParquet(t, data)
+	meta, awalFooter := bacaFooter(t, file)
+
+	if meta[1] != int64(1) {
+		t.Errorf("version = %v, ingin 1", meta[1])
+	}
+	if meta[3] != int64(len(data)) {
+		t.Errorf("num_rows = %v, ingin %d", meta[3], len(data))
+	}
+
+	// Skema: root lalu satu elemen per kolom dengan urutan Kolom
+	schema := meta[2].([]any)
+	if len(schema) != len(Kolom)+1 {
+		t.Fatalf("schema berisi %d elemen, ingin %d", len(schema), len(Kolom)+1)
+	}
+	root := schema[0].(map[int16]any)
+	if root[4] != "schema" || root[5] != int64(len(Kolom)) {
+		t.Errorf("root schema = %v", root)
+	}
+	tipe := make([]int64, len(Kolom))
+	opsional := make([]bool, len(Kolom))
+	for i, nama := range Kolom {
+		el := schema[i+1].(map[int16]any)
+		if el[4] != nama {
+			t.Errorf("schema[%d].name = %v, ingin %s", i+1, el[4], nama)
+		}
+		tipe[i] = el[1].(int64)
+		opsional[i] = el[3] == int64(parquetOptional)
+		if el[3] != int64(parquetOptional) && el[3] != int64(parquetRequired) {
+			t.Errorf("schema[%d].repetition_type = %v", i+1, el[3])
+		}
+	}
+	for _, nama := range []string{"lebar_biji_mm", "bentuk_biji"} {
+		if i := indeksKolom(nama); !opsional[i] {
+			t.Errorf("kolom %s harus OPTIONAL", nama)
+		}
+	}
+
+	rowGroups := meta[4].([]any)
+	wantRows := []int64{barisPerRowGroup, barisPerRowGroup, 3}
+	if len(rowGroups) != len(wantRows) {
+		t.Fatalf("jumlah row group = %d, ingin %d", len(rowGroups), len(wantRows))
+	}
+
+	// Column chunk harus bersambung tanpa celah dari setelah magic sampai awal footer
+	posisi := int64(len(parquetMagic))
+	var got [][]any
+	for g, rgAny := range rowGroups {
+		rg := rgAny.(map[int16]any)
+		if rg[3] != wantRows[g] {
+			t.Errorf("row group %d: num_rows = %v, ingin %d", g, rg[3], wantRows[g])
+		}
+		numRows := int(wantRows[g])
+		baris := make([][]any, numRows)
+		for i := range baris {
+			baris[i] = make([]any, len(Kolom))
+		}
+
+		var totalSize int64
+		chunks := rg[1].([]any)
+		if len(chunks) != len(Kolom) {
+			t.Fatalf("row group %d berisi %d column chunk, ingin %d", g, len(chunks), len(Kolom))
+		}
+		for c, chAny := range chunks {
+			ch := chAny.(map[int16]any)
+			cm := ch[3].(map[int16]any)
+			offset, size := cm[9].(int64), cm[7].(int64)
+			if ch[2] != offset || offset != posisi {
+				t.Fatalf("row group %d kolom %d: file_offset %v, data_page_offset %d, ingin %d", g, c, ch[2], offset, posisi)
+			}
+			if cm[6] != size {
+				t.Errorf("row group %d kolom %d: ukuran uncompressed %v != compressed %d", g, c, cm[6], size)
+			}
+			if cm[1] != tipe[c] || cm[4] != int64(parquetUncompressed) || cm[5] != wantRows[g] {
+				t.Errorf("row group %d kolom %d: meta_data = %v", g, c, cm)
+			}
+			if !reflect.DeepEqual(cm[3], []any{Kolom[c]}) {
+				t.Errorf("row group %d kolom %d: path_in_schema = %v", g, c, cm[3])
+			}
+			posisi += size
+			totalSize += size
+
+			// Satu data page per column chunk: header lalu data sepanjang compressed_page_size
+			chunk := file[offset : offset+size]
+			header, n := decodeThrift(t, chunk)
+			dph := header[5].(map[int16]any)
+			if header[1] != int64(parquetDataPage) || header[3] != int64(len(chunk)-n) || header[2] != header[3] {
+				t.Fatalf("row group %d kolom %d: page header = %v, data %d byte", g, c, header, len(chunk)-n)
+			}
+			if dph[1] != int64(numRows) || dph[2] != int64(parquetPlain) {
+				t.Errorf("row group %d kolom %d: data_page_header = %v", g, c, dph)
+			}
+
+			page := chunk[n:]
+			ada := make([]bool, numRows)
+			for i := range ada {
+				ada[i] = true
+			}
+			if opsional[c] {
+				panjang := int(binary.LittleEndian.Uint32(page))
+				ada = bacaLevel(t, page[4:4+panjang], numRows)
+				page = page[4+panjang:]
+			}
+			for i := range numRows {
+				if !ada[i] {
+					continue
+				}
+				v, n := bacaNilai(t, tipe[c], page)
+				baris[i][c] = v
+				page = page[n:]
+			}
+			if len(page) != 0 {
+				t.Errorf("row group %d kolom %d: sisa %d byte setelah semua nilai", g, c, len(page))
+			}
+		}
+		if rg[2] != totalSize {
+			t.Errorf("row group %d: total_byte_size = %v, ingin %d", g, rg[2], totalSize)
+		}
+		got = append(got, baris...)
+	}
+	if posisi != int64(awalFooter) {
+		t.Errorf("column chunk terakhir berakhir di %d, footer mulai di %d", posisi, awalFooter)
+	}
+
+	if len(got) != len(data) {
+		t.Fatalf("terbaca %d baris, ingin %d", len(got), len(data))
+	}
+	var null int
+	for i, v := range data {
+		want := nilaiParquet(v)
+		if !reflect.DeepEqual(got[i], want) {
+			t.Fatalf("baris %d = %v, ingin %v", i, got[i], want)
+		}
+		if want[indeksKolom("lebar_biji_mm")] == nil {
+			null++
+		}
+	}
+	if null == 0 {
+		t.Fatal("data contoh tidak berisi null")
+	}
+}
+
+func TestParquetTanpaData(t *testing.T) {
+	file := tulisParquet(t, nil)
+	meta, awalFooter := bacaFooter(t, file)
+	if awalFooter != len(parquetMagic) {
+		t.Errorf("footer mulai di %d, ingin %d", awalFooter, len(parquetMagic))
+	}
+	if meta[3] != int64(0) || len(meta[4].([]any)) != 0 {
+		t.Errorf("num_rows = %v, row_groups = %v; ingin 0 dan kosong", meta[3], meta[4])
+	}
+}
+
+func TestDefinitionLevels(t *testing.T) {
+	tests := []struct {
+		nama string
+		ada  []bool
+		want []byte
+	}{
+		{"kosong", nil, []byte{1, 0, 0, 0, 0x01}},
+		{"satu grup", []bool{true, false, true}, []byte{2, 0, 0, 0, 0x03, 0x05}},
+		{"dua grup", []bool{true, true, true, true, true, true, true, true, false, true}, []byte{3, 0, 0, 0, 0x05, 0xFF, 0x02}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nama, func(t *testing.T) {
+			got := definitionLevels(tt.ada)
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("definitionLevels = % x, ingin % x", got, tt.want)
+			}
+			if len(tt.ada) == 0 {
+				return
+			}
+			if back := bacaLevel(t, got[4:], len(tt.ada)); !reflect.DeepEqual(back, tt.ada) {
+				t.Errorf("decode = %v, ingin %v", back, tt.ada)
+			}
+		})
+	}
+}
+
+func indeksKolom(nama string) int {
+	for i, k := range Kolom {
+		if k == nama {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,105 @@
+package ekspor
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// Tipe field Thrift Compact Protocol yang dipakai metadata Parquet
+const (
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+// thriftWriter adalah encoder Thrift Compact Protocol minimal, cukup untuk
+// menulis PageHeader dan FileMetaData Parquet. Field ditulis dengan delta id
+// terhadap field sebelumnya di struct yang sama.
+type thriftWriter struct {
+	buf  bytes.Buffer
+	last []int16 // id field terakhir per tingkat struct
+}
+
+func newThriftWriter() *thriftWriter {
+	return &thriftWriter{last: []int16{0}}
+}
+
+func (t *thriftWriter) varint(v uint64) {
+	var tmp [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(tmp[:], v)
+	t.buf.Write(tmp[:n])
+}
+
+func zigzag(v int64) uint64 {
+	return uint64((v << 1) ^ (v >> 63))
+}
+
+func (t *thriftWriter) fieldHeader(id int16, typ byte) {
+	last := &t.last[len(t.last)-1]
+	if delta := id - *last; delta > 0 && delta <= 15 {
+		t.buf.WriteByte(byte(delta)<<4 | typ)
+	} else {
+		t.buf.WriteByte(typ)
+		t.varint(zigzag(int64(id)))
+	}
+	*last = id
+}
+
+func (t *thriftWriter) i32(id int16, v int32) {
+	t.fieldHeader(id, thriftI32)
+	t.varint(zigzag(int64(v)))
+}
+
+func (t *thriftWriter) i64(id int16, v int64) {
+	t.fieldHeader(id, thriftI64)
+	t.varint(zigzag(v))
+}
+
+func (t *thriftWriter) str(id int16, s string) {
+	t.fieldHeader(id, thriftBinary)
+	t.varint(uint64(len(s)))
+	t.buf.WriteString(s)
+}
+
+// structField membuka field bertipe struct; tutup dengan end
+func (t *thriftWriter) structField(id int16) {
+	t.fieldHeader(id, thriftStruct)
+	t.last = append(t.last, 0)
+}
+
+// listField membuka field bertipe list dengan n elemen bertipe elem
+func (t *thriftWriter) listField(id int16, elem byte, n int) {
+	t.fieldHeader(id, thriftList)
+	if n < 15 {
+		t.buf.WriteByte(byte(n)<<4 | elem)
+	} else {
+		t.buf.WriteByte(0xF0 | elem)
+		t.varint(uint64(n))
+	}
+}
+
+// elemStruct membuka satu elemen struct di dalam list; tutup dengan end
+func (t *thriftWriter) elemStruct() {
+	t.last = append(t.last, 0)
+}
+
+func (t *thriftWriter) elemI32(v int32) {
+	t.varint(zigzag(int64(v)))
+}
+
+func (t *thriftWriter) elemStr(s string) {
+	t.varint(uint64(len(s)))
+	t.buf.WriteString(s)
+}
+
+// end menutup struct yang sedang ditulis (field stop)
+func (t *thriftWriter) end() {
+	t.buf.WriteByte(0)
+	t.last = t.last[:len(t.last)-1]
+}
+
+func (t *thriftWriter) bytes() []byte {
+	return t.buf.Bytes()
+}
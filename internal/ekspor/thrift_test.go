@@ -0,0 +1,240 @@
+package ekspor
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// thriftReader adalah decoder Thrift Compact Protocol untuk test, ditulis terpisah dari
+// thriftWriter agar kesalahan encoder tidak tertutupi kesalahan yang sama di decoder.
+// Struct dibaca menjadi map id field -> nilai; i32/i64 menjadi int64, binary menjadi
+// string, list menjadi []any, dan struct menjadi map[int16]any.
+type thriftReader struct {
+	b   []byte
+	pos int
+}
+
+func (r *thriftReader) byte() (byte, error) {
+	if r.pos >= len(r.b) {
+		return 0, fmt.Errorf("thrift: data habis di posisi %d", r.pos)
+	}
+	c := r.b[r.pos]
+	r.pos++
+	return c, nil
+}
+
+func (r *thriftReader) varint() (uint64, error) {
+	v, n := binary.Uvarint(r.b[r.pos:])
+	if n <= 0 {
+		return 0, fmt.Errorf("thrift: varint tidak valid di posisi %d", r.pos)
+	}
+	r.pos += n
+	return v, nil
+}
+
+func (r *thriftReader) zigzag() (int64, error) {
+	u, err := r.varint()
+	return int64(u>>1) ^ -int64(u&1), err
+}
+
+func (r *thriftReader) readStruct() (map[int16]any, error) {
+	fields := map[int16]any{}
+	var last int16
+	for {
+		h, err := r.byte()
+		if err != nil {
+			return nil, err
+		}
+		if h == 0 {
+			return fields, nil
+		}
+		typ := h & 0x0F
+		id := last + int16(h>>4)
+		if h>>4 == 0 {
+			v, err := r.zigzag()
+			if err != nil {
+				return nil, err
+			}
+			id = int16(v)
+		}
+		if _, dup := fields[id]; dup {
+			return nil, fmt.Errorf("thrift: field %d muncul dua kali", id)
+		}
+		if fields[id], err = r.readValue(typ); err != nil {
+			return nil, err
+		}
+		last = id
+	}
+}
+
+func (r *thriftReader) readValue(typ byte) (any, error) {
+	switch typ {
+	case thriftI32, thriftI64:
+		return r.zigzag()
+	case thriftBinary:
+		n, err := r.varint()
+		if err != nil {
+			return nil, err
+		}
+		if r.pos+int(n) > len(r.b) {
+			return nil, fmt.Errorf("thrift: binary melewati akhir data")
+		}
+		s := string(r.b[r.pos : r.pos+int(n)])
+		r.pos += int(n)
+		return s, nil
+	case thriftList:
+		h, err := r.byte()
+		if err != nil {
+			return nil, err
+		}
+		n, elem := int(h>>4), h&0x0F
+		if n == 15 {
+			u, err := r.varint()
+			if err != nil {
+				return nil, err
+			}
+			n = int(u)
+		}
+		list := make([]any, n)
+		for i := range list {
+			if list[i], err = r.readValue(elem); err != nil {
+				return nil, err
+			}
+		}
+		return list, nil
+	case thriftStruct:
+		return r.readStruct()
+	}
+	return nil, fmt.Errorf("thrift: tipe %d tidak didukung", typ)
+}
+
+func decodeThrift(t *testing.T, b []byte) (map[int16]any, int) {
+	t.Helper()
+	r := &thriftReader{b: b}
+	s, err := r.readStruct()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, r.pos
+}
+
+func TestThriftWriterBytes(t *testing.T) {
+	tests := []struct {
+		nama  string
+		tulis func(w *thriftWriter)
+		want  []byte
+	}{
+		{
+			nama:  "delta pendek",
+			tulis: func(w *thriftWriter) { w.i32(1, 5); w.i64(2, -1); w.end() },
+			want:  []byte{0x15, 0x0A, 0x16, 0x01, 0x00},
+		},
+		{
+			nama:  "delta lebih dari 15 memakai id penuh",
+			tulis: func(w *thriftWriter) { w.i32(1, 0); w.i32(20, 1); w.end() },
+			want:  []byte{0x15, 0x00, 0x05, 0x28, 0x02, 0x00},
+		},
+		{
+			nama:  "delta 15 masih pendek",
+			tulis: func(w *thriftWriter) { w.i32(1, 0); w.i32(16, 0); w.end() },
+			want:  []byte{0x15, 0x00, 0xF5, 0x00, 0x00},
+		},
+		{
+			nama:  "delta 16 memakai id penuh",
+			tulis: func(w *thriftWriter) { w.i32(1, 0); w.i32(17, 0); w.end() },
+			want:  []byte{0x15, 0x00, 0x05, 0x22, 0x00, 0x00},
+		},
+		{
+			nama:  "id mundur memakai id penuh",
+			tulis: func(w *thriftWriter) { w.i32(3, 0); w.i32(2, 0); w.end() },
+			want:  []byte{0x35, 0x00, 0x05, 0x04, 0x00, 0x00},
+		},
+		{
+			nama:  "string",
+			tulis: func(w *thriftWriter) { w.str(4, "ab"); w.end() },
+			want:  []byte{0x48, 0x02, 'a', 'b', 0x00},
+		},
+		{
+			// Delta field 3 dihitung dari field 2 (struct), bukan dari field di dalamnya
+			nama:  "struct bersarang",
+			tulis: func(w *thriftWriter) { w.structField(2); w.i32(7, 1); w.end(); w.i32(3, 1); w.end() },
+			want:  []byte{0x2C, 0x75, 0x02, 0x00, 0x15, 0x02, 0x00},
+		},
+		{
+			nama:  "list pendek",
+			tulis: func(w *thriftWriter) { w.listField(1, thriftI32, 2); w.elemI32(1); w.elemI32(-1); w.end() },
+			want:  []byte{0x19, 0x25, 0x02, 0x01, 0x00},
+		},
+		{
+			nama: "list 15 elemen memakai panjang varint",
+			tulis: func(w *thriftWriter) {
+				w.listField(1, thriftI32, 15)
+				for range 15 {
+					w.elemI32(0)
+				}
+				w.end()
+			},
+			want: append([]byte{0x19, 0xF5, 0x0F}, append(make([]byte, 15), 0x00)...),
+		},
+		{
+			nama: "list struct",
+			tulis: func(w *thriftWriter) {
+				w.listField(1, thriftStruct, 2)
+				w.elemStruct()
+				w.i32(1, 1)
+				w.end()
+				w.elemStruct()
+				w.i32(1, 2)
+				w.end()
+				w.i32(2, 3)
+				w.end()
+			},
+			want: []byte{0x19, 0x2C, 0x15, 0x02, 0x00, 0x15, 0x04, 0x00, 0x15, 0x06, 0x00},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nama, func(t *testing.T) {
+			w := newThriftWriter()
+			tt.tulis(w)
+			if !bytes.Equal(w.bytes(), tt.want) {
+				t.Errorf("bytes = % x, ingin % x", w.bytes(), tt.want)
+			}
+			if len(w.last) != 0 {
+				t.Errorf("tingkat struct tersisa %d, ingin 0 (end tidak seimbang)", len(w.last))
+			}
+		})
+	}
+}
+
+func TestThriftWriterRoundTrip(t *testing.T) {
+	w := newThriftWriter()
+	w.i32(1, -7)
+	w.listField(2, thriftStruct, 2)
+	for i := range 2 {
+		w.elemStruct()
+		w.str(4, fmt.Sprint("kolom", i))
+		w.structField(9)
+		w.i64(1, 1<<40)
+		w.listField(2, thriftBinary, 1)
+		w.elemStr("x")
+		w.end()
+		w.end()
+	}
+	w.i64(20, 42)
+	w.end()
+
+	got, n := decodeThrift(t, w.bytes())
+	if n != len(w.bytes()) {
+		t.Errorf("decoder membaca %d dari %d byte", n, len(w.bytes()))
+	}
+	elem := func(i int) map[int16]any {
+		return map[int16]any{4: fmt.Sprint("kolom", i), 9: map[int16]any{1: int64(1 << 40), 2: []any{"x"}}}
+	}
+	want := map[int16]any{1: int64(-7), 2: []any{elem(0), elem(1)}, 20: int64(42)}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("decode = %#v\ningin %#v", got, want)
+	}
+}
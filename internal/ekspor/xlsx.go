@@ -0,0 +1,166 @@
+package ekspor
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
+)
+
+func init() {
+	Register(Format{
+		Nama:        "xlsx",
+		ContentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+		Ekstensi:    "xlsx",
+		New:         NewXLSXWriter,
+	})
+}
+
+// Bagian statis paket XLSX (Office Open XML). Satu workbook dengan satu sheet;
+// sel teks memakai inline string sehingga tidak perlu sharedStrings.xml.
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>
+<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>
+</Types>`
+
+	xlsxRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>
+</Relationships>`
+
+	xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="Varietas" sheetId="1" r:id="rId1"/></sheets>
+</workbook>`
+
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>
+</Relationships>`
+
+	xlsxSheetHeader = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+
+	xlsxSheetFooter = `</sheetData></worksheet>`
+)
+
+// kolomAngka menandai kolom (sesuai urutan Kolom) yang ditulis sebagai sel angka, bukan teks
+var kolomAngka = func() []bool {
+	angka := map[string]bool{"id_padi": true, "panjang_biji_mm": true, "versi": true, "lebar_biji_mm": true}
+	out := make([]bool, len(Kolom))
+	for i, k := range Kolom {
+		out[i] = angka[k]
+	}
+	return out
+}()
+
+// xlsxWriter menulis paket zip XLSX secara streaming: bagian statis ditulis di awal,
+// lalu sheet1.xml ditulis baris demi baris, dan zip ditutup di Close.
+type xlsxWriter struct {
+	out     io.Writer
+	zip     *zip.Writer
+	sheet   *bufio.Writer
+	row     int
+	started bool
+}
+
+// NewXLSXWriter membuat Writer XLSX tanpa library pihak ketiga
+func NewXLSXWriter(w io.Writer) Writer {
+	return &xlsxWriter{out: w}
+}
+
+func (x *xlsxWriter) start() error {
+	if x.started {
+		return nil
+	}
+	x.started = true
+	x.zip = zip.NewWriter(x.out)
+
+	static := []struct{ name, body string }{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRels},
+		{"xl/workbook.xml", xlsxWorkbook},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+	}
+	for _, part := range static {
+		f, err := x.zip.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, part.body); err != nil {
+			return err
+		}
+	}
+
+	// sheet1.xml harus entri terakhir karena ditulis bertahap sampai Close
+	f, err := x.zip.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return err
+	}
+	x.sheet = bufio.NewWriter(f)
+	if _, err := x.sheet.WriteString(xlsxSheetHeader); err != nil {
+		return err
+	}
+	return x.writeRow(Kolom, nil)
+}
+
+// writeRow menulis satu <row>; kolom dengan angka[i] == true ditulis sebagai sel angka.
+// Nilai kosong tidak ditulis sama sekali (sel kosong).
+func (x *xlsxWriter) writeRow(values []string, angka []bool) error {
+	x.row++
+	var b strings.Builder
+	fmt.Fprintf(&b, `<row r="%d">`, x.row)
+	for i, v := range values {
+		if v == "" {
+			continue
+		}
+		ref := fmt.Sprintf("%s%d", kolomHuruf(i), x.row)
+		if angka != nil && angka[i] {
+			fmt.Fprintf(&b, `<c r="%s"><v>%s</v></c>`, ref, v)
+			continue
+		}
+		fmt.Fprintf(&b, `<c r="%s" t="inlineStr"><is><t xml:space="preserve">`, ref)
+		xml.EscapeText(&b, []byte(v))
+		b.WriteString(`</t></is></c>`)
+	}
+	b.WriteString(`</row>`)
+	_, err := x.sheet.WriteString(b.String())
+	return err
+}
+
+func (x *xlsxWriter) Write(v domain.VarietasPadi) error {
+	if err := x.start(); err != nil {
+		return err
+	}
+	return x.writeRow(nilaiTeks(v), kolomAngka)
+}
+
+func (x *xlsxWriter) Close() error {
+	if err := x.start(); err != nil {
+		return err
+	}
+	if _, err := x.sheet.WriteString(xlsxSheetFooter); err != nil {
+		return err
+	}
+	if err := x.sheet.Flush(); err != nil {
+		return err
+	}
+	return x.zip.Close()
+}
+
+// kolomHuruf mengubah indeks kolom (0 = A) menjadi huruf kolom spreadsheet
+func kolomHuruf(i int) string {
+	s := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		s = string(rune('A'+(i-1)%26)) + s
+	}
+	return s
+}
@@ -0,0 +1,206 @@
+package ekspor
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"testing"
+
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
+)
+
+// xlsxSheet adalah bagian sheet1.xml yang diperiksa test
+type xlsxSheet struct {
+	Rows []struct {
+		R     int `xml:"r,attr"`
+		Cells []struct {
+			R     string `xml:"r,attr"`
+			T     string `xml:"t,attr"`
+			Value string `xml:"v"`
+			Teks  string `xml:"is>t"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+type xlsxRelasi struct {
+	Relationships []struct {
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type xlsxTypes struct {
+	Overrides []struct {
+		PartName string `xml:"PartName,attr"`
+	} `xml:"Override"`
+}
+
+// bukaXLSX menulis data sebagai XLSX lalu mengembalikan isi setiap bagian paket,
+// beserta urutan bagiannya di zip. Setiap bagian harus XML yang well-formed.
+func bukaXLSX(t *testing.T, data []domain.VarietasPadi) (map[string][]byte, []string) {
+	t.Helper()
+	var buf bytes.Buffer
+	w := NewXLSXWriter(&buf)
+	for _, v := range data {
+		if err := w.Write(v); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("bukan zip yang valid: %v", err)
+	}
+	parts := map[string][]byte{}
+	var urutan []string
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("%s: %v", f.Name, err)
+		}
+		b, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatalf("%s: %v", f.Name, err)
+		}
+		dec := xml.NewDecoder(bytes.NewReader(b))
+		for {
+			if _, err := dec.Token(); errors.Is(err, io.EOF) {
+				break
+			} else if err != nil {
+				t.Fatalf("%s bukan XML yang valid: %v", f.Name, err)
+			}
+		}
+		parts[f.Name] = b
+		urutan = append(urutan, f.Name)
+	}
+	return parts, urutan
+}
+
+func TestXLSXPaket(t *testing.T) {
+	parts, urutan := bukaXLSX(t, contohBaris(2))
+
+	if urutan[0] != "[Content_Types].xml" {
+		t.Errorf("bagian pertama = %s, ingin [Content_Types].xml", urutan[0])
+	}
+
+	// Setiap part yang dideklarasikan di [Content_Types].xml harus ada
+	var types xlsxTypes
+	if err := xml.Unmarshal(parts["[Content_Types].xml"], &types); err != nil {
+		t.Fatal(err)
+	}
+	for _, o := range types.Overrides {
+		if _, ok := parts[o.PartName[1:]]; !ok {
+			t.Errorf("Override %s tidak ada di paket", o.PartName)
+		}
+	}
+
+	// Target relationship relatif terhadap folder induk dari folder _rels
+	for _, rels := range []string{"_rels/.rels", "xl/_rels/workbook.xml.rels"} {
+		var r xlsxRelasi
+		if err := xml.Unmarshal(parts[rels], &r); err != nil {
+			t.Fatalf("%s: %v", rels, err)
+		}
+		if len(r.Relationships) == 0 {
+			t.Errorf("%s tidak berisi relationship", rels)
+		}
+		dasar := path.Dir(path.Dir(rels))
+		for _, rel := range r.Relationships {
+			target := path.Join(dasar, rel.Target)
+			if _, ok := parts[target]; !ok {
+				t.Errorf("%s menunjuk %s yang tidak ada di paket", rels, target)
+			}
+		}
+	}
+}
+
+func TestXLSXSheet(t *testing.T) {
+	data := contohBaris(3) // baris ketiga tanpa lebar biji
+	data[0].VarietasKelas = `IR64 <"A&B"> 'x'`
+	data[1].Warna = "  Putih\tKekuningan  "
+	parts, _ := bukaXLSX(t, data)
+
+	var sheet xlsxSheet
+	if err := xml.Unmarshal(parts["xl/worksheets/sheet1.xml"], &sheet); err != nil {
+		t.Fatal(err)
+	}
+	if len(sheet.Rows) != len(data)+1 {
+		t.Fatalf("jumlah baris = %d, ingin %d", len(sheet.Rows), len(data)+1)
+	}
+
+	for r, row := range sheet.Rows {
+		if row.R != r+1 {
+			t.Errorf("row[%d].r = %d, ingin %d", r, row.R, r+1)
+		}
+		want := Kolom
+		if r > 0 {
+			want = nilaiTeks(data[r-1])
+		}
+
+		got := map[string]string{}
+		for _, c := range row.Cells {
+			got[c.R] = c.Teks
+			if c.T == "" {
+				got[c.R] = c.Value
+			} else if c.T != "inlineStr" {
+				t.Errorf("sel %s bertipe %q", c.R, c.T)
+			}
+		}
+		for i, v := range want {
+			ref := fmt.Sprintf("%s%d", kolomHuruf(i), r+1)
+			isi, ada := got[ref]
+			if v == "" {
+				if ada {
+					t.Errorf("sel %s seharusnya kosong, berisi %q", ref, isi)
+				}
+				continue
+			}
+			if isi != v {
+				t.Errorf("sel %s = %q, ingin %q", ref, isi, v)
+			}
+		}
+		if len(got) > len(want) {
+			t.Errorf("baris %d berisi %d sel, ingin paling banyak %d", r+1, len(got), len(want))
+		}
+	}
+
+	// Kolom angka ditulis sebagai sel angka (tanpa atribut t) agar bisa dihitung
+	for _, c := range sheet.Rows[1].Cells {
+		kolom := Kolom[c.R[0]-'A']
+		if angka := kolomAngka[c.R[0]-'A']; angka != (c.T == "") {
+			t.Errorf("kolom %s: t=%q, angka=%v", kolom, c.T, angka)
+		}
+	}
+}
+
+func TestXLSXTanpaData(t *testing.T) {
+	parts, _ := bukaXLSX(t, nil)
+	var sheet xlsxSheet
+	if err := xml.Unmarshal(parts["xl/worksheets/sheet1.xml"], &sheet); err != nil {
+		t.Fatal(err)
+	}
+	if len(sheet.Rows) != 1 || len(sheet.Rows[0].Cells) != len(Kolom) {
+		t.Errorf("sheet kosong harus berisi satu baris header dengan %d sel", len(Kolom))
+	}
+}
+
+func TestKolomHuruf(t *testing.T) {
+	tests := []struct {
+		i    int
+		want string
+	}{
+		{0, "A"}, {10, "K"}, {25, "Z"}, {26, "AA"}, {27, "AB"}, {51, "AZ"}, {52, "BA"},
+		{701, "ZZ"}, {702, "AAA"}, {16383, "XFD"},
+	}
+	for _, tt := range tests {
+		if got := kolomHuruf(tt.i); got != tt.want {
+			t.Errorf("kolomHuruf(%d) = %q, ingin %q", tt.i, got, tt.want)
+		}
+	}
+}
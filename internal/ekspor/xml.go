@@ -0,0 +1,80 @@
+package ekspor
+
+import (
+	"bufio"
+	"encoding/xml"
+	"io"
+
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
+)
+
+func init() {
+	Register(Format{Nama: "xml", ContentType: "application/xml; charset=utf-8", Ekstensi: "xml",
+		New: NewXMLWriter, Satu: tulisXMLSatu})
+}
+
+const xmlHeader = `<?xml version="1.0" encoding="UTF-8"?>` + "\n"
+
+// xmlWriter menulis <daftar_varietas> berisi satu elemen <varietas> per data.
+// Nama elemen anak sama dengan nama kolom/field JSON.
+type xmlWriter struct {
+	w       *bufio.Writer
+	started bool
+}
+
+// NewXMLWriter membuat Writer XML
+func NewXMLWriter(w io.Writer) Writer {
+	return &xmlWriter{w: bufio.NewWriter(w)}
+}
+
+func (x *xmlWriter) start() error {
+	if x.started {
+		return nil
+	}
+	x.started = true
+	_, err := x.w.WriteString(xmlHeader + "<daftar_varietas>\n")
+	return err
+}
+
+func (x *xmlWriter) Write(v domain.VarietasPadi) error {
+	if err := x.start(); err != nil {
+		return err
+	}
+	return tulisElemenVarietas(x.w, v)
+}
+
+func (x *xmlWriter) Close() error {
+	if err := x.start(); err != nil {
+		return err
+	}
+	if _, err := x.w.WriteString("</daftar_varietas>\n"); err != nil {
+		return err
+	}
+	return x.w.Flush()
+}
+
+// tulisXMLSatu menulis satu data sebagai dokumen dengan root <varietas>
+func tulisXMLSatu(w io.Writer, v domain.VarietasPadi) error {
+	bw := bufio.NewWriter(w)
+	if _, err := bw.WriteString(xmlHeader); err != nil {
+		return err
+	}
+	if err := tulisElemenVarietas(bw, v); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// tulisElemenVarietas menulis satu elemen <varietas> dengan anak sesuai urutan Kolom
+func tulisElemenVarietas(w *bufio.Writer, v domain.VarietasPadi) error {
+	w.WriteString("<varietas>")
+	for i, nilai := range nilaiTeks(v) {
+		w.WriteString("<" + Kolom[i] + ">")
+		if err := xml.EscapeText(w, []byte(nilai)); err != nil {
+			return err
+		}
+		w.WriteString("</" + Kolom[i] + ">")
+	}
+	_, err := w.WriteString("</varietas>\n")
+	return err
+}
@@ -0,0 +1,102 @@
+package ekspor
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+// xmlVarietas membaca elemen <varietas> apa adanya: nama dan isi setiap elemen anak
+type xmlVarietas struct {
+	Anak []struct {
+		XMLName xml.Name
+		Isi     string `xml:",chardata"`
+	} `xml:",any"`
+}
+
+func TestXMLDaftar(t *testing.T) {
+	data := contohBaris(3)
+	data[0].VarietasKelas = `IR64 <"A&B"> 'x' ]]>`
+	data[1].Warna = "Putih\tKekuningan\nbaris dua"
+
+	var buf bytes.Buffer
+	w := NewXMLWriter(&buf)
+	for _, v := range data {
+		if err := w.Write(v); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	out := buf.String()
+
+	if !strings.HasPrefix(out, xmlHeader+"<daftar_varietas>") {
+		t.Errorf("dokumen tidak diawali deklarasi XML dan <daftar_varietas>: %.60q", out)
+	}
+	if strings.Contains(out, `<"A&B">`) || strings.Contains(out, "]]>") {
+		t.Error("karakter khusus tidak di-escape")
+	}
+
+	var doc struct {
+		XMLName  xml.Name
+		Varietas []xmlVarietas `xml:"varietas"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("bukan XML yang valid: %v", err)
+	}
+	if doc.XMLName.Local != "daftar_varietas" {
+		t.Errorf("root = %s, ingin daftar_varietas", doc.XMLName.Local)
+	}
+	if len(doc.Varietas) != len(data) {
+		t.Fatalf("jumlah <varietas> = %d, ingin %d", len(doc.Varietas), len(data))
+	}
+	for i, v := range data {
+		periksaElemen(t, doc.Varietas[i], nilaiTeks(v))
+	}
+}
+
+func TestXMLSatuDanKosong(t *testing.T) {
+	v := contohBaris(3)[2] // lebar biji kosong
+	var buf bytes.Buffer
+	if err := tulisXMLSatu(&buf, v); err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		XMLName xml.Name
+		xmlVarietas
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("bukan XML yang valid: %v", err)
+	}
+	if doc.XMLName.Local != "varietas" {
+		t.Errorf("root = %s, ingin varietas", doc.XMLName.Local)
+	}
+	periksaElemen(t, doc.xmlVarietas, nilaiTeks(v))
+
+	buf.Reset()
+	w := NewXMLWriter(&buf)
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if want := xmlHeader + "<daftar_varietas>\n</daftar_varietas>\n"; buf.String() != want {
+		t.Errorf("daftar kosong = %q, ingin %q", buf.String(), want)
+	}
+}
+
+// periksaElemen memastikan elemen anak <varietas> sesuai urutan Kolom dengan isi want
+func periksaElemen(t *testing.T, v xmlVarietas, want []string) {
+	t.Helper()
+	if len(v.Anak) != len(Kolom) {
+		t.Fatalf("jumlah elemen anak = %d, ingin %d", len(v.Anak), len(Kolom))
+	}
+	for i, a := range v.Anak {
+		if a.XMLName.Local != Kolom[i] {
+			t.Errorf("elemen ke-%d = <%s>, ingin <%s>", i, a.XMLName.Local, Kolom[i])
+		}
+		if a.Isi != want[i] {
+			t.Errorf("<%s> = %q, ingin %q", Kolom[i], a.Isi, want[i])
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
+)
+
+// permintaanBatch adalah body POST /varietas/batch
+type permintaanBatch struct {
+	Operations []domain.OperasiBatch `json:"operations"`
+}
+
+// hasilOperasiJSON menambahkan detail error (format problem) ke hasil operasi yang gagal
+type hasilOperasiJSON struct {
+	domain.HasilOperasi
+	Error *Problem `json:"error,omitempty"`
+}
+
+// Batch: POST /varietas/batch?atomic=true|false (default true)
+// Menjalankan banyak operasi create/update/delete dalam satu transaksi dan membalas
+// hasil per operasi dengan urutan yang sama seperti request.
+func (h *VarietasHandler) Batch(w http.ResponseWriter, r *http.Request) {
+	atomic := true
+	if raw := r.URL.Query().Get("atomic"); raw != "" {
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			respondError(w, r, domain.NewValidationError("parameter atomic tidak valid",
+				domain.FieldError{Field: "atomic", Code: "invalid", Message: "harus true atau false"}))
+			return
+		}
+		atomic = v
+	}
+
+	var req permintaanBatch
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	hasil, err := h.service.JalankanBatch(ctx, req.Operations, atomic)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	items := make([]hasilOperasiJSON, len(hasil.Hasil))
+	for i, op := range hasil.Hasil {
+		items[i] = hasilOperasiJSON{HasilOperasi: op}
+		if op.Err != nil {
+			p := problemFromError(r, op.Err)
+			items[i].Error = &p
+		}
+	}
+
+	respondJSON(w, http.StatusOK, map[string]any{
+		"success": hasil.Gagal == 0,
+		"data": map[string]any{
+			"atomic":    hasil.Atomic,
+			"committed": hasil.Committed,
+			"berhasil":  hasil.Berhasil,
+			"gagal":     hasil.Gagal,
+			"hasil":     items,
+		},
+	})
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/ekspor"
+)
+
+// batasWaktuEkspor menggantikan WriteTimeout server untuk request ekspor yang besar
+const batasWaktuEkspor = 5 * time.Minute
+
+// Export: GET /varietas/export?format=csv|ndjson|xml|cbor|xlsx|parquet
+// Menerima filter dan sort yang sama dengan GetAll; pagination diabaikan.
+// Data dialirkan langsung dari cursor repository ke response.
+func (h *VarietasHandler) Export(w http.ResponseWriter, r *http.Request) {
+	values := r.URL.Query()
+	nama := values.Get("format")
+	if nama == "" {
+		nama = "csv"
+	}
+	format, ok := ekspor.Lookup(nama)
+	if !ok {
+		respondError(w, r, domain.NewValidationError("format ekspor tidak didukung",
+			domain.FieldError{Field: "format", Code: "one_of",
+				Message: "harus salah satu dari: " + strings.Join(ekspor.Names(), ", ")}))
+		return
+	}
+
+	q, err := parseVarietasQuery(values, "format")
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	// Ekspor besar bisa melebihi WriteTimeout server (10 detik)
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Now().Add(batasWaktuEkspor)); err != nil {
+		log.Printf("WARN: tidak bisa memperpanjang write deadline ekspor: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), batasWaktuEkspor)
+	defer cancel()
+
+	out := &lazyHeaderWriter{w: w, header: func() {
+		w.Header().Set("Content-Type", format.ContentType)
+		w.Header().Set("Content-Disposition",
+			fmt.Sprintf(`attachment; filename="%s"`, ekspor.NamaFile(format, time.Now())))
+	}}
+	writer := format.New(out)
+
+	err = h.service.EksporData(ctx, q, writer.Write)
+	if err == nil {
+		err = writer.Close()
+	}
+	if err == nil {
+		return
+	}
+
+	// Sebelum byte pertama terkirim masih bisa membalas problem+json biasa
+	if !out.started {
+		respondError(w, r, err)
+		return
+	}
+	// Setelah itu status 200 sudah terkirim; putuskan koneksi agar client tahu file terpotong
+	log.Printf("ERROR: ekspor %s terputus: %v", format.Nama, err)
+	panic(http.ErrAbortHandler)
+}
+
+// lazyHeaderWriter menunda penulisan header response sampai byte pertama ditulis,
+// sehingga error sebelum data pertama masih bisa dikirim sebagai response error.
+type lazyHeaderWriter struct {
+	w       http.ResponseWriter
+	header  func()
+	started bool
+}
+
+func (l *lazyHeaderWriter) Write(p []byte) (int, error) {
+	if !l.started {
+		l.started = true
+		l.header()
+	}
+	return l.w.Write(p)
+}
@@ -0,0 +1,165 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"reflect"
+
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
+)
+
+// Problem adalah body error standar RFC 7807 (application/problem+json).
+// Errors berisi daftar field VarietasPadi yang gagal validasi beserta alasannya.
+type Problem struct {
+	Type     string              `json:"type"`
+	Title    string              `json:"title"`
+	Status   int                 `json:"status"`
+	Detail   string              `json:"detail,omitempty"`
+	Instance string              `json:"instance,omitempty"`
+	Errors   []domain.FieldError `json:"errors,omitempty"`
+	// KandidatDuplikat adalah ID data yang mirip saat data baru ditolak sebagai duplikat
+	KandidatDuplikat []int `json:"kandidat_duplikat,omitempty"`
+}
+
+// problemType adalah jenis problem untuk satu status HTTP
+type problemType struct {
+	Type  string
+	Title string
+}
+
+// problemTypes memetakan status HTTP ke URI type dan judul problem.
+// Status yang tidak terdaftar memakai "about:blank" sesuai RFC 7807.
+var problemTypes = map[int]problemType{
+	http.StatusBadRequest:           {"/problems/validation-error", "Data tidak valid"},
+	http.StatusUnauthorized:         {"/problems/unauthorized", "Autentikasi diperlukan"},
+	http.StatusForbidden:            {"/problems/forbidden", "Akses ditolak"},
+	http.StatusNotFound:             {"/problems/not-found", "Data tidak ditemukan"},
+	http.StatusNotAcceptable:        {"/problems/not-acceptable", "Representasi tidak tersedia"},
+	http.StatusConflict:             {"/problems/conflict", "Data konflik"},
+	http.StatusPreconditionFailed:   {"/problems/precondition-failed", "Versi data sudah berubah"},
+	http.StatusUnsupportedMediaType: {"/problems/unsupported-media-type", "Media type tidak didukung"},
+	http.StatusServiceUnavailable:   {"/problems/unavailable", "Layanan sedang tidak tersedia"},
+	http.StatusInternalServerError:  {"about:blank", "Kesalahan internal server"},
+}
+
+// statusFromError memetakan error domain ke status HTTP.
+// Ini satu-satunya tempat keputusan status error dibuat; handler tidak boleh
+// membandingkan isi pesan error.
+func statusFromError(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrValidation), errors.Is(err, domain.ErrQueryTidakValid):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrUnauthorized):
+		return http.StatusUnauthorized
+	case errors.Is(err, domain.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, domain.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, domain.ErrPreconditionFailed):
+		return http.StatusPreconditionFailed
+	case errors.Is(err, domain.ErrUnavailable):
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
+
+// newProblem membuat Problem untuk status tertentu dengan type dan title standar
+func newProblem(r *http.Request, status int, detail string) Problem {
+	pt, ok := problemTypes[status]
+	if !ok {
+		pt = problemType{"about:blank", http.StatusText(status)}
+	}
+	return Problem{
+		Type:     pt.Type,
+		Title:    pt.Title,
+		Status:   status,
+		Detail:   detail,
+		Instance: r.URL.Path,
+	}
+}
+
+// respondProblem menulis Problem dengan Content-Type application/problem+json
+func respondProblem(w http.ResponseWriter, p Problem) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(p.Status)
+	json.NewEncoder(w).Encode(p)
+}
+
+// respondError menulis response error berdasarkan jenis error domain.
+// Detail error internal (500) hanya dicatat di log, tidak dikirim ke client.
+func respondError(w http.ResponseWriter, r *http.Request, err error) {
+	respondProblem(w, problemFromError(r, err))
+}
+
+// RespondError sama dengan respondError, untuk middleware di luar package handler
+func RespondError(w http.ResponseWriter, r *http.Request, err error) {
+	respondError(w, r, err)
+}
+
+// problemFromError menyusun Problem dari error domain (lihat respondError).
+// Dipakai juga untuk error per operasi di dalam response batch.
+func problemFromError(r *http.Request, err error) Problem {
+	status := statusFromError(err)
+
+	detail := err.Error()
+	if status == http.StatusInternalServerError {
+		log.Printf("ERROR: %s %s: %v", r.Method, r.URL.Path, err)
+		detail = "terjadi kesalahan internal pada server"
+	}
+
+	p := newProblem(r, status, detail)
+
+	// Sertakan daftar field yang gagal validasi agar client bisa menandai input yang salah
+	var vErr *domain.ValidationError
+	if errors.As(err, &vErr) && len(vErr.Fields) > 0 {
+		p.Detail = vErr.Message
+		p.Errors = vErr.Fields
+	}
+	var dErr *domain.DuplikatError
+	if errors.As(err, &dErr) {
+		p.KandidatDuplikat = idKandidat(dErr.Kandidat)
+	}
+	return p
+}
+
+// errInvalidID dipakai ketika path parameter {id} bukan bilangan bulat positif
+var errInvalidID = domain.NewValidationError("ID varietas tidak valid",
+	domain.FieldError{Field: "id_padi", Code: "invalid", Message: "harus bilangan bulat positif"})
+
+// errInvalidJSON dipakai ketika body request tidak bisa di-decode
+var errInvalidJSON = domain.NewValidationError("Format data JSON tidak valid")
+
+// decodeJSON membaca body JSON ke v. Jika tipe sebuah field salah
+// (misalnya panjang_biji_mm dikirim sebagai teks), field tersebut disebut di error.
+func decodeJSON(r *http.Request, v any) error {
+	err := json.NewDecoder(r.Body).Decode(v)
+	if err == nil {
+		return nil
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return domain.NewValidationError(errInvalidJSON.Message,
+			domain.FieldError{Field: typeErr.Field, Code: "type", Message: "harus bertipe " + jsonTypeName(typeErr.Type)})
+	}
+	return errInvalidJSON
+}
+
+// jsonTypeName memberi nama tipe Go dalam istilah JSON yang dipahami client
+func jsonTypeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "angka"
+	case reflect.String:
+		return "teks"
+	case reflect.Bool:
+		return "boolean"
+	}
+	return t.String()
+}
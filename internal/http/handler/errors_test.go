@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
+)
+
+func TestRespondError(t *testing.T) {
+	tests := []struct {
+		nama       string
+		err        error
+		wantStatus int
+		wantType   string
+		wantDetail string
+	}{
+		{
+			nama:       "forbidden dari kebijakan",
+			err:        fmt.Errorf("%w: HapusData membutuhkan peran curator, admin", domain.ErrForbidden),
+			wantStatus: http.StatusForbidden,
+			wantType:   "/problems/forbidden",
+			wantDetail: "akses ditolak: HapusData membutuhkan peran curator, admin",
+		},
+		{
+			nama:       "forbidden dibungkus service",
+			err:        fmt.Errorf("gagal mengubah varietas id 1: %w", fmt.Errorf("%w: data varietas id 1 bukan milik tek2", domain.ErrForbidden)),
+			wantStatus: http.StatusForbidden,
+			wantType:   "/problems/forbidden",
+			wantDetail: "gagal mengubah varietas id 1: akses ditolak: data varietas id 1 bukan milik tek2",
+		},
+		{
+			nama:       "unauthorized",
+			err:        domain.ErrUnauthorized,
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			nama:       "error internal tidak dibocorkan",
+			err:        errors.New("koneksi database putus"),
+			wantStatus: http.StatusInternalServerError,
+			wantDetail: "terjadi kesalahan internal pada server",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nama, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodDelete, "/api/varietas/1", nil)
+			w := httptest.NewRecorder()
+			RespondError(w, r, tt.err)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, ingin %d", w.Code, tt.wantStatus)
+			}
+			if ct := w.Header().Get("Content-Type"); ct != "application/problem+json" {
+				t.Errorf("Content-Type = %q, ingin application/problem+json", ct)
+			}
+			var p Problem
+			if err := json.NewDecoder(w.Body).Decode(&p); err != nil {
+				t.Fatalf("body bukan problem+json: %v", err)
+			}
+			if p.Status != tt.wantStatus || p.Instance != "/api/varietas/1" {
+				t.Errorf("problem status=%d instance=%q, ingin %d/%q", p.Status, p.Instance, tt.wantStatus, "/api/varietas/1")
+			}
+			if tt.wantType != "" && p.Type != tt.wantType {
+				t.Errorf("problem type = %q, ingin %q", p.Type, tt.wantType)
+			}
+			if tt.wantDetail != "" && p.Detail != tt.wantDetail {
+				t.Errorf("problem detail = %q, ingin %q", p.Detail, tt.wantDetail)
+			}
+		})
+	}
+}
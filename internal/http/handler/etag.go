@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
+)
+
+// etagVarietas membentuk ETag kuat dari versi data, misalnya "v3". Dipakai untuk
+// representasi JSON, yaitu response semua endpoint tulis.
+func etagVarietas(v domain.VarietasPadi) string {
+	return fmt.Sprintf(`"v%d"`, v.Versi)
+}
+
+// etagRepresentasi membentuk ETag kuat untuk satu representasi data. Setiap representasi
+// berbeda isinya, jadi ETag-nya juga harus berbeda (RFC 9110 §8.8.3): JSON memakai "v3",
+// format lain diberi nama formatnya, misalnya "v3-csv".
+func etagRepresentasi(v domain.VarietasPadi, rep representasi) string {
+	if rep.Nama == representasiJSON.Nama {
+		return etagVarietas(v)
+	}
+	return fmt.Sprintf(`"v%d-%s"`, v.Versi, rep.Nama)
+}
+
+// parseETags memecah nilai If-Match / If-None-Match menjadi daftar entity tag.
+// weak=true jika tag diawali W/ (hanya relevan untuk If-None-Match).
+func parseETags(header string) (tags []string, weak []bool) {
+	for _, part := range strings.Split(header, ",") {
+		tag := strings.TrimSpace(part)
+		if tag == "" {
+			continue
+		}
+		isWeak := strings.HasPrefix(tag, "W/")
+		tags = append(tags, strings.TrimPrefix(tag, "W/"))
+		weak = append(weak, isWeak)
+	}
+	return tags, weak
+}
+
+// versiFromETag membaca nomor versi dari ETag JSON "v<versi>"; 0 jika formatnya lain.
+// ETag representasi lain seperti "v3-csv" sengaja tidak dikenali karena endpoint tulis
+// menerima dan mengembalikan JSON, jadi perbandingan kuat dengan ETag JSON tidak pernah cocok.
+func versiFromETag(tag string) int {
+	inner := strings.TrimSuffix(strings.TrimPrefix(tag, `"v`), `"`)
+	if len(inner) != len(tag)-3 {
+		return 0
+	}
+	n, err := strconv.Atoi(inner)
+	if err != nil || n <= 0 || strconv.Itoa(n) != inner {
+		return 0
+	}
+	return n
+}
+
+// ifMatchVersi mengembalikan versi yang diharapkan dari header If-Match.
+// Header kosong atau "*" berarti tanpa syarat (0). Tag lemah dan tag yang tidak dikenal
+// tidak pernah cocok (perbandingan kuat, RFC 9110), jadi langsung ErrPreconditionFailed.
+// Jika header berisi beberapa tag, versi saat ini dibaca untuk memilih tag yang cocok.
+func (h *VarietasHandler) ifMatchVersi(ctx context.Context, r *http.Request, id int) (int, error) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" || header == "*" {
+		return 0, nil
+	}
+
+	tags, weak := parseETags(header)
+	var candidates []int
+	for i, tag := range tags {
+		if versi := versiFromETag(tag); versi > 0 && !weak[i] {
+			candidates = append(candidates, versi)
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		return 0, domain.ErrPreconditionFailed
+	case 1:
+		return candidates[0], nil
+	}
+
+	current, err := h.service.DapatkanDataByID(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	for _, versi := range candidates {
+		if versi == current.Versi {
+			return versi, nil
+		}
+	}
+	return 0, domain.ErrPreconditionFailed
+}
+
+// noneMatch bernilai true jika If-None-Match cocok dengan ETag representasi saat ini
+// (perbandingan lemah), artinya client sudah memegang versi terbaru dan cukup dibalas 304.
+func noneMatch(r *http.Request, current string) bool {
+	header := strings.TrimSpace(r.Header.Get("If-None-Match"))
+	if header == "" {
+		return false
+	}
+	if header == "*" {
+		return true
+	}
+
+	tags, _ := parseETags(header)
+	for _, tag := range tags {
+		if tag == current {
+			return true
+		}
+	}
+	return false
+}
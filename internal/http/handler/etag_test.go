@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestVersiFromETag(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want int
+	}{
+		{`"v3"`, 3},
+		{`"v120"`, 120},
+		{`"v3-csv"`, 0},
+		{`"v3-xml"`, 0},
+		{`"v03"`, 0},
+		{`"v+3"`, 0},
+		{`"v0"`, 0},
+		{`"v-1"`, 0},
+		{`"v"`, 0},
+		{`v3`, 0},
+		{`"3"`, 0},
+		{`"v3`, 0},
+	}
+	for _, tt := range tests {
+		if got := versiFromETag(tt.tag); got != tt.want {
+			t.Errorf("versiFromETag(%s) = %d, ingin %d", tt.tag, got, tt.want)
+		}
+	}
+}
+
+func TestIfMatchHanyaETagJSON(t *testing.T) {
+	const body = `{"varietas_kelas":"IR64","warna":"Coklat","panjang_biji_mm":6.5,` +
+		`"tekstur_permukaan":"Halus","bentuk_ujung_daun":"Runcing"}`
+	metode := []struct {
+		nama   string
+		method string
+		body   string
+		ok     int
+		fn     func(h *VarietasHandler) http.HandlerFunc
+	}{
+		{"PUT", http.MethodPut, body, http.StatusOK, func(h *VarietasHandler) http.HandlerFunc { return h.Update }},
+		{"PATCH", http.MethodPatch, `{"warna":"Coklat"}`, http.StatusOK, func(h *VarietasHandler) http.HandlerFunc { return h.Patch }},
+		{"DELETE", http.MethodDelete, "", http.StatusNoContent, func(h *VarietasHandler) http.HandlerFunc { return h.Delete }},
+	}
+	ifMatch := []struct {
+		nama   string
+		header string
+		cocok  bool
+	}{
+		{"ETag JSON", `"v1"`, true},
+		{"ETag JSON di antara tag lain", `"v1-csv", "v1"`, true},
+		{"tanpa syarat", `*`, true},
+		{"ETag CSV", `"v1-csv"`, false},
+		{"ETag XML", `"v1-xml"`, false},
+		{"hanya ETag format lain", `"v1-csv", "v1-cbor"`, false},
+		{"ETag lemah", `W/"v1"`, false},
+		{"versi usang", `"v2"`, false},
+	}
+
+	for _, m := range metode {
+		for _, im := range ifMatch {
+			t.Run(m.nama+"/"+im.nama, func(t *testing.T) {
+				h, svc := newTestHandler(t)
+				tambahContoh(t, svc, 1)
+
+				w := kirim(m.fn(h), m.method, "/api/varietas/1", m.body,
+					map[string]string{"id": "1"}, map[string]string{"If-Match": im.header})
+				want := http.StatusPreconditionFailed
+				if im.cocok {
+					want = m.ok
+				}
+				if w.Code != want {
+					t.Fatalf("status = %d, ingin %d: %s", w.Code, want, w.Body)
+				}
+				if im.cocok {
+					return
+				}
+				if v, err := svc.DapatkanDataByID(context.Background(), 1); err != nil || v.Versi != 1 || v.Warna != "Putih" {
+					t.Errorf("data setelah 412 = %+v, %v, ingin tidak berubah", v, err)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
+)
+
+// lipatanBawaan adalah jumlah lipatan cross-validation jika ?folds= tidak dikirim
+const lipatanBawaan = 5
+
+//go:embed templates/evaluasi_model.html
+var templateFS embed.FS
+
+// templateEvaluasi adalah laporan evaluasi model versi HTML untuk dashboard
+var templateEvaluasi = template.Must(template.New("evaluasi_model.html").
+	Funcs(template.FuncMap{
+		"persen": func(v float64) string { return fmt.Sprintf("%.1f%%", v*100) },
+	}).
+	ParseFS(templateFS, "templates/evaluasi_model.html"))
+
+// ModelEvaluation: GET /varietas/model/evaluation?folds=5
+// Menjalankan stratified k-fold cross-validation dan membalas akurasi, metrik per kelas,
+// dan matriks konfusi. Dikirim sebagai HTML jika client meminta text/html (browser).
+func (h *VarietasHandler) ModelEvaluation(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Vary", "Accept")
+	mediaType, ok := pilihMediaType(r.Header.Get("Accept"), "application/json", "text/html")
+	if !ok {
+		respondProblem(w, newProblem(r, http.StatusNotAcceptable,
+			"media type yang didukung: application/json, text/html"))
+		return
+	}
+
+	lipatan := lipatanBawaan
+	if raw := r.URL.Query().Get("folds"); raw != "" {
+		v, err := strconv.Atoi(raw)
+		if err != nil {
+			respondError(w, r, domain.NewValidationError("parameter folds tidak valid",
+				domain.FieldError{Field: "folds", Code: "invalid", Message: "harus bilangan bulat"}))
+			return
+		}
+		lipatan = v
+	}
+
+	// Cross-validation melatih model sebanyak jumlah lipatan, jadi batas waktunya lebih longgar
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	hasil, err := h.service.EvaluasiKlasifikasi(ctx, lipatan)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	if mediaType == "text/html" {
+		var buf bytes.Buffer
+		if err := templateEvaluasi.Execute(&buf, hasil); err != nil {
+			respondError(w, r, err)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		w.Write(buf.Bytes())
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]any{"success": true, "data": hasil})
+}
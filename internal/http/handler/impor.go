@@ -0,0 +1,255 @@
+package handler
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
+)
+
+// Batas ukuran impor CSV agar satu request tidak menghabiskan memori server
+const (
+	maxUkuranImpor = 10 << 20 // 10 MB
+	maxBarisImpor  = 10000
+)
+
+// batasWaktuImpor menggantikan ReadTimeout/WriteTimeout server (10 detik) untuk impor besar
+const batasWaktuImpor = 60 * time.Second
+
+// kolomImporCSV adalah kolom CSV yang wajib ada di header
+var kolomImporCSV = []string{"varietas_kelas", "warna", "panjang_biji_mm", "tekstur_permukaan", "bentuk_ujung_daun"}
+
+// kolomImporOpsional boleh tidak ada di header; sel kosong berarti nilainya tidak diisi
+var kolomImporOpsional = []string{"lebar_biji_mm"}
+
+// Import: POST /varietas/import?dry_run=true
+// Body text/csv dengan baris header berisi nama field VarietasPadi. Pemisah koma atau
+// titik koma (format Excel berbahasa Indonesia) dideteksi otomatis dari baris header.
+func (h *VarietasHandler) Import(w http.ResponseWriter, r *http.Request) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "text/csv" && mediaType != "application/csv" {
+		respondProblem(w, newProblem(r, http.StatusUnsupportedMediaType, "Content-Type harus text/csv"))
+		return
+	}
+
+	dryRun := false
+	if raw := r.URL.Query().Get("dry_run"); raw != "" {
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			respondError(w, r, domain.NewValidationError("parameter dry_run tidak valid",
+				domain.FieldError{Field: "dry_run", Code: "invalid", Message: "harus true atau false"}))
+			return
+		}
+		dryRun = v
+	}
+
+	// Upload dan penyimpanan file besar bisa melebihi timeout server
+	rc := http.NewResponseController(w)
+	if err := rc.SetReadDeadline(time.Now().Add(batasWaktuImpor)); err != nil {
+		log.Printf("WARN: tidak bisa memperpanjang read deadline impor: %v", err)
+	}
+	if err := rc.SetWriteDeadline(time.Now().Add(batasWaktuImpor)); err != nil {
+		log.Printf("WARN: tidak bisa memperpanjang write deadline impor: %v", err)
+	}
+
+	baris, err := parseImporCSV(http.MaxBytesReader(w, r.Body, maxUkuranImpor))
+	var maxErr *http.MaxBytesError
+	if errors.As(err, &maxErr) {
+		respondProblem(w, newProblem(r, http.StatusRequestEntityTooLarge,
+			fmt.Sprintf("ukuran file impor maksimal %d MB", maxUkuranImpor>>20)))
+		return
+	}
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), batasWaktuImpor)
+	defer cancel()
+
+	hasil, err := h.service.ImporData(ctx, baris, dryRun)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]any{"success": true, "data": hasil})
+}
+
+// parseImporCSV membaca CSV menjadi BarisImpor. Kesalahan pada satu baris (jumlah kolom,
+// angka tidak valid) dicatat di baris itu; kesalahan struktur file (header, tanda kutip)
+// menggagalkan seluruh impor.
+func parseImporCSV(body io.Reader) ([]domain.BarisImpor, error) {
+	br := bufio.NewReader(body)
+	delimiter, err := detectDelimiter(br)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := csv.NewReader(br)
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1 // jumlah kolom dicek per baris agar bisa dilaporkan
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, domain.NewValidationError("file CSV kosong")
+	}
+	if err != nil {
+		return nil, csvError(err)
+	}
+	kolom, err := parseHeaderImpor(header)
+	if err != nil {
+		return nil, err
+	}
+
+	var baris []domain.BarisImpor
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, csvError(err)
+		}
+		if len(baris) == maxBarisImpor {
+			return nil, domain.NewValidationError(fmt.Sprintf("file impor maksimal %d baris data", maxBarisImpor))
+		}
+
+		line, _ := reader.FieldPos(0)
+		baris = append(baris, parseBarisImpor(line, record, kolom, delimiter))
+	}
+	return baris, nil
+}
+
+// detectDelimiter menebak pemisah kolom dari baris pertama tanpa mengonsumsinya
+func detectDelimiter(br *bufio.Reader) (rune, error) {
+	first, err := br.Peek(4096)
+	if err != nil && err != io.EOF && !errors.Is(err, bufio.ErrBufferFull) {
+		return 0, err
+	}
+	if i := bytes.IndexByte(first, '\n'); i >= 0 {
+		first = first[:i]
+	}
+	if bytes.Count(first, []byte{';'}) > bytes.Count(first, []byte{','}) {
+		return ';', nil
+	}
+	return ',', nil
+}
+
+// parseHeaderImpor memetakan header CSV ke indeks kolom. Nama kolom tidak peka huruf
+// besar/kecil, dan spasi atau tanda hubung dianggap garis bawah ("Panjang Biji MM").
+func parseHeaderImpor(header []string) (map[string]int, error) {
+	kolom := map[string]int{}
+	var fields []domain.FieldError
+	for i, raw := range header {
+		name := strings.TrimPrefix(raw, "\uFEFF") // BOM dari Excel
+		name = strings.ToLower(domain.CollapseSpaces(name))
+		name = strings.NewReplacer(" ", "_", "-", "_").Replace(name)
+
+		switch {
+		case readOnlyFields[name]:
+			fields = append(fields, domain.FieldError{Field: name, Code: "read_only", Message: "kolom diisi otomatis oleh server dan tidak boleh diimpor"})
+		case !isKolomImpor(name):
+			fields = append(fields, domain.FieldError{Field: raw, Code: "unknown_field", Message: "kolom tidak dikenal"})
+		default:
+			if _, dup := kolom[name]; dup {
+				fields = append(fields, domain.FieldError{Field: name, Code: "duplicate", Message: "kolom muncul lebih dari sekali"})
+			}
+			kolom[name] = i
+		}
+	}
+	for _, name := range kolomImporCSV {
+		if _, ok := kolom[name]; !ok {
+			fields = append(fields, domain.FieldError{Field: name, Code: "required", Message: "kolom wajib ada di header"})
+		}
+	}
+
+	if len(fields) > 0 {
+		return nil, domain.NewValidationError("header CSV tidak valid", fields...)
+	}
+	return kolom, nil
+}
+
+func isKolomImpor(name string) bool {
+	return slices.Contains(kolomImporCSV, name) || slices.Contains(kolomImporOpsional, name)
+}
+
+// errAngkaTakHingga dipakai saat sel berisi NaN atau Inf, yang diterima strconv.ParseFloat
+var errAngkaTakHingga = errors.New("angka harus terhingga")
+
+// parseAngkaImpor membaca angka terhingga dari sel CSV; dengan pemisah titik koma, desimal
+// koma (misalnya 7,5) juga diterima
+func parseAngkaImpor(raw string, delimiter rune) (float64, error) {
+	raw = strings.TrimSpace(raw)
+	if delimiter == ';' {
+		raw = strings.Replace(raw, ",", ".", 1)
+	}
+	n, err := strconv.ParseFloat(raw, 64)
+	if err == nil && (math.IsNaN(n) || math.IsInf(n, 0)) {
+		return 0, errAngkaTakHingga
+	}
+	return n, err
+}
+
+// fieldAngkaImpor menjelaskan sel angka yang gagal dibaca parseAngkaImpor
+func fieldAngkaImpor(field string, err error) domain.FieldError {
+	if errors.Is(err, errAngkaTakHingga) {
+		return domain.FieldError{Field: field, Code: "range", Message: "harus angka terhingga (bukan NaN atau Inf)"}
+	}
+	return domain.FieldError{Field: field, Code: "type", Message: "harus bertipe angka"}
+}
+
+// parseBarisImpor mengubah satu record CSV menjadi BarisImpor
+func parseBarisImpor(line int, record []string, kolom map[string]int, delimiter rune) domain.BarisImpor {
+	b := domain.BarisImpor{Baris: line}
+	if len(record) != len(kolom) {
+		b.Errors = append(b.Errors, domain.FieldError{Field: "baris", Code: "columns",
+			Message: fmt.Sprintf("berisi %d kolom, seharusnya %d", len(record), len(kolom))})
+		return b
+	}
+
+	b.Data = domain.VarietasPadi{
+		VarietasKelas:    record[kolom["varietas_kelas"]],
+		Warna:            record[kolom["warna"]],
+		TeksturPermukaan: record[kolom["tekstur_permukaan"]],
+		BentukUjungDaun:  record[kolom["bentuk_ujung_daun"]],
+	}
+
+	n, err := parseAngkaImpor(record[kolom["panjang_biji_mm"]], delimiter)
+	if err != nil {
+		b.Errors = append(b.Errors, fieldAngkaImpor("panjang_biji_mm", err))
+	}
+	b.Data.PanjangBijiMM = n
+
+	if i, ok := kolom["lebar_biji_mm"]; ok && strings.TrimSpace(record[i]) != "" {
+		lebar, err := parseAngkaImpor(record[i], delimiter)
+		if err != nil {
+			b.Errors = append(b.Errors, fieldAngkaImpor("lebar_biji_mm", err))
+		}
+		b.Data.LebarBijiMM = &lebar
+	}
+	return b
+}
+
+// csvError menerjemahkan kesalahan format CSV (misalnya tanda kutip tidak ditutup)
+func csvError(err error) error {
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return domain.NewValidationError(fmt.Sprintf("format CSV tidak valid di baris %d: %v", parseErr.Line, parseErr.Err))
+	}
+	return err
+}
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
+)
+
+// tetanggaBawaan adalah jumlah data termirip jika client tidak mengirim k
+const tetanggaBawaan = 10
+
+// permintaanKemiripan adalah body POST /varietas/similar. Bobot yang tidak dikirim
+// memakai domain.BobotJarakBawaan.
+type permintaanKemiripan struct {
+	Warna            string            `json:"warna"`
+	PanjangBijiMM    float64           `json:"panjang_biji_mm"`
+	TeksturPermukaan string            `json:"tekstur_permukaan"`
+	BentukUjungDaun  string            `json:"bentuk_ujung_daun"`
+	K                *int              `json:"k"`
+	Bobot            domain.BobotJarak `json:"bobot"`
+}
+
+// Similar: GET /varietas/{id}/similar?k=10&bobot=panjang_biji_mm:2,warna:0.5
+// Membalas k data lain yang paling mirip dengan data id beserta jaraknya
+func (h *VarietasHandler) Similar(w http.ResponseWriter, r *http.Request) {
+	id, err := parseID(r)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	k := tetanggaBawaan
+	if raw := r.URL.Query().Get("k"); raw != "" {
+		v, err := strconv.Atoi(raw)
+		if err != nil {
+			respondError(w, r, domain.NewValidationError("parameter k tidak valid",
+				domain.FieldError{Field: "k", Code: "invalid", Message: "harus bilangan bulat"}))
+			return
+		}
+		k = v
+	}
+	bobot := domain.BobotJarakBawaan
+	if raw := r.URL.Query().Get("bobot"); raw != "" {
+		if bobot, err = domain.ParseBobotJarak(raw); err != nil {
+			respondError(w, r, err)
+			return
+		}
+	}
+
+	// Pencarian membandingkan acuan dengan semua data
+	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+	defer cancel()
+
+	hasil, err := h.service.CariMiripDariID(ctx, id, k, bobot)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]any{"success": true, "data": hasil})
+}
+
+// SimilarToSample: POST /varietas/similar
+// Membalas k data yang paling mirip dengan ciri morfologi sampel beserta jaraknya
+func (h *VarietasHandler) SimilarToSample(w http.ResponseWriter, r *http.Request) {
+	req := permintaanKemiripan{Bobot: domain.BobotJarakBawaan}
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, r, err)
+		return
+	}
+	k := tetanggaBawaan
+	if req.K != nil {
+		k = *req.K
+	}
+
+	sampel := domain.VarietasPadi{
+		Warna:            req.Warna,
+		PanjangBijiMM:    req.PanjangBijiMM,
+		TeksturPermukaan: req.TeksturPermukaan,
+		BentukUjungDaun:  req.BentukUjungDaun,
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+	defer cancel()
+
+	hasil, err := h.service.CariMirip(ctx, sampel, k, req.Bobot)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]any{"success": true, "data": hasil})
+}
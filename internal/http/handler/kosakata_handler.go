@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
+)
+
+// KosakataHandler melayani CRUD kosakata terkontrol (/api/warna, /api/tekstur-permukaan, ...).
+// Setiap method menerima kategori dan mengembalikan http.HandlerFunc untuk kategori tersebut.
+type KosakataHandler struct {
+	service domain.KosakataService
+}
+
+// NewKosakataHandler adalah constructor Handler kosakata.
+func NewKosakataHandler(service domain.KosakataService) *KosakataHandler {
+	return &KosakataHandler{service: service}
+}
+
+// kosakataRequest adalah body request untuk membuat/mengubah nilai baku atau menambah alias
+type kosakataRequest struct {
+	Nilai string `json:"nilai"`
+	Alias string `json:"alias"`
+}
+
+// List: GET /api/{kategori}
+func (h *KosakataHandler) List(kategori string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		data, err := h.service.DaftarKosakata(ctx, kategori)
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, map[string]any{"success": true, "total": len(data), "data": data})
+	}
+}
+
+// Get: GET /api/{kategori}/{id}
+func (h *KosakataHandler) Get(kategori string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := parseID(r)
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		data, err := h.service.DapatkanKosakata(ctx, kategori, id)
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, map[string]any{"success": true, "data": data})
+	}
+}
+
+// Create: POST /api/{kategori}
+func (h *KosakataHandler) Create(kategori string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req kosakataRequest
+		if err := decodeJSON(r, &req); err != nil {
+			respondError(w, r, err)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		data, err := h.service.TambahKosakata(ctx, domain.Kosakata{Kategori: kategori, Nilai: req.Nilai})
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+		respondJSON(w, http.StatusCreated, map[string]any{"success": true, "data": data})
+	}
+}
+
+// Update: PUT /api/{kategori}/{id}
+func (h *KosakataHandler) Update(kategori string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := parseID(r)
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+		var req kosakataRequest
+		if err := decodeJSON(r, &req); err != nil {
+			respondError(w, r, err)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		data, err := h.service.UbahKosakata(ctx, domain.Kosakata{ID: id, Kategori: kategori, Nilai: req.Nilai})
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, map[string]any{"success": true, "data": data})
+	}
+}
+
+// Delete: DELETE /api/{kategori}/{id}
+func (h *KosakataHandler) Delete(kategori string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := parseID(r)
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		if err := h.service.HapusKosakata(ctx, kategori, id); err != nil {
+			respondError(w, r, err)
+			return
+		}
+		respondJSON(w, http.StatusNoContent, nil)
+	}
+}
+
+// AddAlias: POST /api/{kategori}/{id}/alias dengan body {"alias": "..."}
+func (h *KosakataHandler) AddAlias(kategori string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := parseID(r)
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+		var req kosakataRequest
+		if err := decodeJSON(r, &req); err != nil {
+			respondError(w, r, err)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		data, err := h.service.TambahAlias(ctx, kategori, id, req.Alias)
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+		respondJSON(w, http.StatusCreated, map[string]any{"success": true, "data": data})
+	}
+}
+
+// RemoveAlias: DELETE /api/{kategori}/{id}/alias/{alias}
+func (h *KosakataHandler) RemoveAlias(kategori string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := parseID(r)
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		if err := h.service.HapusAlias(ctx, kategori, id, mux.Vars(r)["alias"]); err != nil {
+			respondError(w, r, err)
+			return
+		}
+		respondJSON(w, http.StatusNoContent, nil)
+	}
+}
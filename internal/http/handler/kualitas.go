@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
+)
+
+// decodeJSONOpsional sama seperti decodeJSON, tetapi request tanpa body dianggap objek kosong
+func decodeJSONOpsional(r *http.Request, v any) error {
+	if r.ContentLength == 0 {
+		return nil
+	}
+	return decodeJSON(r, v)
+}
+
+// Quality: GET /varietas/quality?jenis=outlier_panjang&status=terbuka
+// Memeriksa kualitas semua data aktif dan mendaftar temuannya
+func (h *VarietasHandler) Quality(w http.ResponseWriter, r *http.Request) {
+	f := domain.FilterKualitas{
+		Jenis:  r.URL.Query().Get("jenis"),
+		Status: r.URL.Query().Get("status"),
+	}
+
+	// Pemeriksaan membaca semua data aktif
+	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+	defer cancel()
+
+	laporan, err := h.service.PeriksaKualitas(ctx, f)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]any{"success": true, "data": laporan})
+}
+
+// AcknowledgeQuality: POST /varietas/quality/{kode}/ack
+// Body opsional {"catatan": "..."}. Temuan yang diakui tidak lagi dihitung terbuka
+// sampai datanya berubah.
+func (h *VarietasHandler) AcknowledgeQuality(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Catatan string `json:"catatan"`
+	}
+	if err := decodeJSONOpsional(r, &req); err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+	defer cancel()
+
+	temuan, err := h.service.AkuiTemuan(ctx, mux.Vars(r)["kode"], req.Catatan)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]any{"success": true, "data": temuan})
+}
+
+// UnacknowledgeQuality: DELETE /varietas/quality/{kode}/ack
+func (h *VarietasHandler) UnacknowledgeQuality(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	if err := h.service.BatalkanPengakuan(ctx, mux.Vars(r)["kode"]); err != nil {
+		respondError(w, r, err)
+		return
+	}
+	respondJSON(w, http.StatusNoContent, nil)
+}
+
+// FixQuality: POST /varietas/quality/{kode}/fix
+// Body opsional {"nilai": ...}; tanpa nilai, saran temuan yang dipakai. Duplikat
+// dipindahkan ke tempat sampah (204), temuan lain membalas data yang sudah diperbaiki.
+func (h *VarietasHandler) FixQuality(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Nilai any `json:"nilai"`
+	}
+	if err := decodeJSONOpsional(r, &req); err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+	defer cancel()
+
+	data, err := h.service.PerbaikiTemuan(ctx, mux.Vars(r)["kode"], req.Nilai)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+	if data == nil {
+		respondJSON(w, http.StatusNoContent, nil)
+		return
+	}
+	w.Header().Set("ETag", etagVarietas(*data))
+	respondJSON(w, http.StatusOK, map[string]any{"success": true, "data": data})
+}
@@ -0,0 +1,212 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
+)
+
+// Media type yang diterima endpoint PATCH
+const (
+	mediaMergePatch = "application/merge-patch+json" // RFC 7396
+	mediaJSONPatch  = "application/json-patch+json"  // RFC 6902
+)
+
+// errPatchTidakValid adalah pesan umum ketika dokumen patch tidak bisa diterapkan
+const errPatchTidakValid = "dokumen patch tidak valid"
+
+// readOnlyFields tidak boleh diubah lewat PATCH (termasuk field turunan)
+var readOnlyFields = map[string]bool{"id_padi": true, "waktu_pembuatan": true, "versi": true,
+	"kategori_panjang": true, "bentuk_biji": true}
+
+// optionalFields boleh dikosongkan dengan null (merge patch) atau remove (JSON Patch)
+var optionalFields = map[string]bool{"lebar_biji_mm": true}
+
+// jsonPatchOperation adalah satu operasi RFC 6902
+type jsonPatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value"`
+}
+
+// parsePatch membaca body PATCH sesuai Content-Type.
+// Mengembalikan ok=false jika media type tidak didukung (415).
+func parsePatch(r *http.Request) (patch domain.VarietasPatch, ok bool, err error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return patch, true, errInvalidJSON
+	}
+
+	switch mediaType {
+	case mediaMergePatch, "application/json":
+		patch, err = parseMergePatch(body)
+	case mediaJSONPatch:
+		patch, err = parseJSONPatch(body)
+	default:
+		return patch, false, nil
+	}
+	return patch, true, err
+}
+
+// parseMergePatch menerapkan semantik JSON Merge Patch: key yang ada diganti nilainya,
+// key yang tidak ada dibiarkan. null berarti hapus, yang tidak berlaku untuk field wajib.
+func parseMergePatch(body []byte) (domain.VarietasPatch, error) {
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return domain.VarietasPatch{}, domain.NewValidationError("dokumen merge patch harus berupa objek JSON")
+	}
+
+	// Urutkan key agar urutan error di response selalu sama
+	keys := make([]string, 0, len(doc))
+	for field := range doc {
+		keys = append(keys, field)
+	}
+	sort.Strings(keys)
+
+	var patch domain.VarietasPatch
+	var fields []domain.FieldError
+	for _, field := range keys {
+		if fe := setPatchField(&patch, field, doc[field]); fe != nil {
+			fields = append(fields, *fe)
+		}
+	}
+	if len(fields) > 0 {
+		return domain.VarietasPatch{}, domain.NewValidationError(errPatchTidakValid, fields...)
+	}
+	return patch, nil
+}
+
+// parseJSONPatch menerjemahkan operasi JSON Patch ke VarietasPatch.
+// Dokumen VarietasPadi datar, jadi path hanya berbentuk "/<field>".
+// add dan replace mengganti nilai, test menjadi syarat yang diperiksa sesuai urutannya,
+// remove hanya untuk field opsional, move/copy ditolak.
+func parseJSONPatch(body []byte) (domain.VarietasPatch, error) {
+	var ops []jsonPatchOperation
+	if err := json.Unmarshal(body, &ops); err != nil {
+		return domain.VarietasPatch{}, domain.NewValidationError("dokumen JSON Patch harus berupa array operasi")
+	}
+
+	var patch domain.VarietasPatch
+	var fields []domain.FieldError
+	for i, op := range ops {
+		field := strings.TrimPrefix(op.Path, "/")
+		if !strings.HasPrefix(op.Path, "/") || strings.Contains(field, "/") {
+			fields = append(fields, domain.FieldError{Field: fmt.Sprintf("[%d].path", i), Code: "invalid",
+				Message: "path harus berbentuk /<field>"})
+			continue
+		}
+
+		switch op.Op {
+		case "add", "replace":
+			if fe := setPatchField(&patch, field, op.Value); fe != nil {
+				fields = append(fields, *fe)
+			}
+		case "test":
+			expected, fe := patchTestValue(field, op.Value)
+			if fe != nil {
+				fields = append(fields, *fe)
+				continue
+			}
+			// Salin perubahan sejauh ini agar test dibandingkan dengan dokumen pada posisinya
+			sebelum := patch
+			sebelum.Tests = nil
+			patch.Tests = append(patch.Tests, domain.PatchTest{Field: field, Nilai: expected, Sebelum: sebelum})
+		case "remove":
+			if optionalFields[field] {
+				if fe := setPatchField(&patch, field, json.RawMessage("null")); fe != nil {
+					fields = append(fields, *fe)
+				}
+				continue
+			}
+			fields = append(fields, domain.FieldError{Field: field, Code: "required",
+				Message: "field wajib tidak boleh dihapus"})
+		default:
+			fields = append(fields, domain.FieldError{Field: fmt.Sprintf("[%d].op", i), Code: "unsupported_op",
+				Message: fmt.Sprintf("operasi '%s' tidak didukung", op.Op)})
+		}
+	}
+	if len(fields) > 0 {
+		return domain.VarietasPatch{}, domain.NewValidationError(errPatchTidakValid, fields...)
+	}
+	return patch, nil
+}
+
+// setPatchField mengisi satu field VarietasPatch dari nilai JSON mentah
+func setPatchField(patch *domain.VarietasPatch, field string, raw json.RawMessage) *domain.FieldError {
+	if readOnlyFields[field] {
+		return &domain.FieldError{Field: field, Code: "read_only", Message: "field tidak boleh diubah"}
+	}
+	kind, known := domain.VarietasFields[field]
+	if !known {
+		return &domain.FieldError{Field: field, Code: "unknown_field", Message: "field tidak dikenal"}
+	}
+	if len(raw) == 0 || string(raw) == "null" {
+		if optionalFields[field] {
+			var kosong *float64
+			patch.LebarBijiMM = &kosong
+			return nil
+		}
+		return &domain.FieldError{Field: field, Code: "required", Message: "field wajib tidak boleh dihapus (null)"}
+	}
+
+	if kind == domain.FieldNumber {
+		n := new(float64)
+		if err := json.Unmarshal(raw, n); err != nil {
+			return &domain.FieldError{Field: field, Code: "type", Message: "harus bertipe angka"}
+		}
+		switch field {
+		case "panjang_biji_mm":
+			patch.PanjangBijiMM = n
+		case "lebar_biji_mm":
+			patch.LebarBijiMM = &n
+		}
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return &domain.FieldError{Field: field, Code: "type", Message: "harus bertipe teks"}
+	}
+	switch field {
+	case "varietas_kelas":
+		patch.VarietasKelas = &s
+	case "warna":
+		patch.Warna = &s
+	case "tekstur_permukaan":
+		patch.TeksturPermukaan = &s
+	case "bentuk_ujung_daun":
+		patch.BentukUjungDaun = &s
+	}
+	return nil
+}
+
+// patchTestValue membaca nilai operasi test sesuai tipe field
+func patchTestValue(field string, raw json.RawMessage) (any, *domain.FieldError) {
+	kind, known := domain.VarietasFields[field]
+	if !known {
+		return nil, &domain.FieldError{Field: field, Code: "unknown_field", Message: "field tidak dikenal"}
+	}
+	if string(raw) == "null" && optionalFields[field] {
+		return nil, nil
+	}
+	if kind == domain.FieldNumber {
+		var n float64
+		if err := json.Unmarshal(raw, &n); err != nil {
+			return nil, &domain.FieldError{Field: field, Code: "type", Message: "harus bertipe angka"}
+		}
+		return n, nil
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return nil, &domain.FieldError{Field: field, Code: "type", Message: "harus bertipe teks"}
+	}
+	return s, nil
+}
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
+)
+
+// Merge: POST /varietas/merge
+// Body {"id_utama": 3, "id_digabung": [7, 9]}. Data id_digabung dipindahkan ke tempat
+// sampah dan selanjutnya GET /varietas/{id} untuk ID tersebut dibalas 301 ke id_utama.
+func (h *VarietasHandler) Merge(w http.ResponseWriter, r *http.Request) {
+	var req domain.PermintaanGabung
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	// Semua data yang terlibat dikunci dan dicatat riwayatnya dalam satu transaksi
+	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+	defer cancel()
+
+	hasil, err := h.service.GabungkanData(ctx, req)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	w.Header().Set("ETag", etagVarietas(hasil.Data))
+	respondJSON(w, http.StatusOK, map[string]any{"success": true, "data": hasil})
+}
+
+// respondDigabung membalas 301 Moved Permanently untuk data yang sudah digabung, dengan
+// Location menunjuk ke data utamanya (path yang sama, hanya ID yang diganti)
+func respondDigabung(w http.ResponseWriter, r *http.Request, e *domain.DigabungError) {
+	w.Header().Set("Location", path.Join(path.Dir(r.URL.Path), strconv.Itoa(e.IDBaru)))
+	respondJSON(w, http.StatusMovedPermanently, map[string]any{
+		"success":     false,
+		"message":     e.Error(),
+		"id_padi":     e.IDLama,
+		"digabung_ke": e.IDBaru,
+	})
+}
+
+// idKandidat mengambil ID dari daftar kandidat duplikat
+func idKandidat(kandidat []domain.VarietasPadi) []int {
+	ids := make([]int, len(kandidat))
+	for i, v := range kandidat {
+		ids[i] = v.ID
+	}
+	return ids
+}
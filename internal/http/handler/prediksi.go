@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
+)
+
+// kandidatBawaan adalah jumlah kandidat prediksi jika client tidak mengirim k
+const kandidatBawaan = 3
+
+// permintaanPrediksi adalah body POST /varietas/predict
+type permintaanPrediksi struct {
+	Warna            string  `json:"warna"`
+	PanjangBijiMM    float64 `json:"panjang_biji_mm"`
+	TeksturPermukaan string  `json:"tekstur_permukaan"`
+	BentukUjungDaun  string  `json:"bentuk_ujung_daun"`
+	K                *int    `json:"k"`
+}
+
+// Predict: POST /varietas/predict
+// Menebak varietas_kelas dari empat ciri morfologi dan membalas k kandidat teratas
+// beserta confidence-nya.
+func (h *VarietasHandler) Predict(w http.ResponseWriter, r *http.Request) {
+	var req permintaanPrediksi
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, r, err)
+		return
+	}
+	k := kandidatBawaan
+	if req.K != nil {
+		k = *req.K
+	}
+
+	sampel := domain.VarietasPadi{
+		Warna:            req.Warna,
+		PanjangBijiMM:    req.PanjangBijiMM,
+		TeksturPermukaan: req.TeksturPermukaan,
+		BentukUjungDaun:  req.BentukUjungDaun,
+	}
+
+	// Pelatihan ulang (jika data berubah) membaca semua data, jadi batas waktunya lebih longgar
+	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+	defer cancel()
+
+	hasil, err := h.service.PrediksiKelas(ctx, sampel, k)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]any{"success": true, "data": hasil})
+}
+
+// RetrainModel: POST /varietas/model/retrain
+// Melatih ulang model klasifikasi dari data terbaru tanpa menunggu perubahan data
+func (h *VarietasHandler) RetrainModel(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+	defer cancel()
+
+	info, err := h.service.LatihUlangModel(ctx)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]any{"success": true, "data": info})
+}
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
+)
+
+// filterKeyPattern mengenali parameter filter berbentuk field[op], contoh: panjang_biji_mm[gte]
+var filterKeyPattern = regexp.MustCompile(`^([a-z_]+)\[([a-z]+)\]$`)
+
+// Parameter query yang bukan filter di semua endpoint daftar. Parameter milik satu endpoint
+// saja (format untuk ekspor, group_by untuk statistik) diberikan lewat parseVarietasQuery.
+var reservedParams = map[string]bool{"page": true, "per_page": true, "sort": true}
+
+// isReservedParam bernilai true untuk parameter yang bukan filter. Parameter berawalan "_"
+// juga dilewati karena dipakai client untuk menghindari cache (misalnya ?_=1700000000000);
+// parameter lain yang tidak dikenal tetap ditolak agar salah ketik nama field tidak diam-diam
+// mengembalikan data tanpa filter.
+func isReservedParam(key string, khusus []string) bool {
+	return reservedParams[key] || slices.Contains(khusus, key) || strings.HasPrefix(key, "_")
+}
+
+// parseVarietasQuery mengubah query string request menjadi domain.VarietasQuery.
+// Contoh: ?page=2&per_page=50&sort=-panjang_biji_mm&warna=Kuning&panjang_biji_mm[gte]=7
+// khusus berisi parameter tambahan yang dibaca sendiri oleh endpoint pemanggil, sehingga
+// tidak diperlakukan sebagai filter; di endpoint lain parameter itu ditolak.
+func parseVarietasQuery(values url.Values, khusus ...string) (domain.VarietasQuery, error) {
+	var q domain.VarietasQuery
+
+	if raw := values.Get("page"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			return q, fmt.Errorf("%w: page harus bilangan bulat positif", domain.ErrQueryTidakValid)
+		}
+		if n > domain.MaxPage {
+			return q, fmt.Errorf("%w: page maksimal %d", domain.ErrQueryTidakValid, domain.MaxPage)
+		}
+		q.Page = n
+	}
+	if raw := values.Get("per_page"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			return q, fmt.Errorf("%w: per_page harus bilangan bulat positif", domain.ErrQueryTidakValid)
+		}
+		q.PerPage = n
+	}
+	if raw := values.Get("sort"); raw != "" {
+		sorts, err := domain.ParseSort(raw)
+		if err != nil {
+			return q, err
+		}
+		q.Sort = sorts
+	}
+
+	for key, vals := range values {
+		if isReservedParam(key, khusus) {
+			continue
+		}
+
+		field, op := key, domain.OpEq
+		if m := filterKeyPattern.FindStringSubmatch(key); m != nil {
+			field, op = m[1], domain.FilterOp(m[2])
+		}
+
+		for _, v := range vals {
+			f, err := domain.NewFilter(field, op, v)
+			if err != nil {
+				return q, err
+			}
+			q.Filters = append(q.Filters, f)
+		}
+	}
+
+	return q.Normalize(), nil
+}
+
+// pageLink membuat URL halaman lain dengan mempertahankan filter dan sort dari request asal
+func pageLink(r *http.Request, page, perPage int) string {
+	values := r.URL.Query()
+	values.Set("page", strconv.Itoa(page))
+	values.Set("per_page", strconv.Itoa(perPage))
+	return r.URL.Path + "?" + values.Encode()
+}
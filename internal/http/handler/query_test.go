@@ -0,0 +1,210 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
+)
+
+func TestParseVarietasQueryPage(t *testing.T) {
+	tests := []struct {
+		nama    string
+		page    string
+		want    int
+		invalid bool
+	}{
+		{"kosong", "", 1, false},
+		{"halaman biasa", "3", 3, false},
+		{"batas atas", strconv.Itoa(domain.MaxPage), domain.MaxPage, false},
+		{"nol", "0", 0, true},
+		{"negatif", "-1", 0, true},
+		{"bukan angka", "dua", 0, true},
+		{"melewati batas", strconv.Itoa(domain.MaxPage + 1), 0, true},
+		{"overflow offset", "100000000000000000", 0, true},
+		{"melewati int64", "100000000000000000000", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nama, func(t *testing.T) {
+			values := url.Values{"per_page": {"100"}}
+			if tt.page != "" {
+				values.Set("page", tt.page)
+			}
+			q, err := parseVarietasQuery(values)
+			if tt.invalid {
+				if !errors.Is(err, domain.ErrQueryTidakValid) {
+					t.Fatalf("error = %v, ingin ErrQueryTidakValid", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			if q.Page != tt.want {
+				t.Errorf("Page = %d, ingin %d", q.Page, tt.want)
+			}
+			if q.Offset() < 0 {
+				t.Errorf("Offset() = %d, tidak boleh negatif", q.Offset())
+			}
+		})
+	}
+}
+
+func TestGetAllPageSangatBesar(t *testing.T) {
+	h, svc := newTestHandler(t)
+	tambahContoh(t, svc, 3)
+
+	tests := []struct {
+		nama   string
+		target string
+		want   int
+	}{
+		{"overflow offset ditolak", "/api/varietas?page=100000000000000000&per_page=100", http.StatusBadRequest},
+		{"melewati MaxPage ditolak", "/api/varietas?page=" + strconv.Itoa(domain.MaxPage+1), http.StatusBadRequest},
+		{"MaxPage kosong", "/api/varietas?page=" + strconv.Itoa(domain.MaxPage) + "&per_page=100", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nama, func(t *testing.T) {
+			w := kirim(h.GetAll, http.MethodGet, tt.target, "", nil, nil)
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, ingin %d: %s", w.Code, tt.want, w.Body)
+			}
+		})
+	}
+}
+
+// tambahDataDaftar menyimpan 12 data dengan warna, panjang, dan lebar biji yang bervariasi,
+// lalu menghapus id 12 sehingga data aktif adalah id 1 sampai 11:
+//
+//	id     1     2     3     4     5     6     7     8     9     10    11
+//	warna  Putih Merah Hitam Putih Merah Hitam Putih Merah Hitam Putih Merah
+//	panjang 5    6     7     8     5     6     7     8     5     6     7
+//	lebar  2.0   2.1   2.2   -     2.4   2.5   2.6   -     2.8   2.9   3.0
+func tambahDataDaftar(t *testing.T, svc domain.VarietasService) {
+	t.Helper()
+	ctx := context.Background()
+	warna := []string{"Putih", "Merah", "Hitam"}
+	for i := range 12 {
+		v := domain.VarietasPadi{
+			VarietasKelas:    "IR64",
+			Warna:            warna[i%3],
+			PanjangBijiMM:    5 + float64(i%4),
+			TeksturPermukaan: "Halus",
+			BentukUjungDaun:  "Runcing",
+		}
+		if i%4 != 3 {
+			lebar := 2 + float64(i)/10
+			v.LebarBijiMM = &lebar
+		}
+		if _, err := svc.TambahkanData(ctx, v); err != nil {
+			t.Fatalf("TambahkanData: %v", err)
+		}
+	}
+	if err := svc.HapusData(ctx, 12, 0); err != nil {
+		t.Fatalf("HapusData: %v", err)
+	}
+}
+
+func TestGetAllFilterSortPaging(t *testing.T) {
+	h, svc := newTestHandler(t)
+	tambahDataDaftar(t, svc)
+
+	tests := []struct {
+		nama       string
+		query      string
+		want       []int
+		total      int
+		next, prev bool
+	}{
+		{"tanpa parameter", "", []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}, 11, false, false},
+		{"filter teks", "warna=Putih", []int{1, 4, 7, 10}, 4, false, false},
+		{"filter gabungan", "warna[ne]=Putih&panjang_biji_mm[gte]=7", []int{3, 8, 11}, 3, false, false},
+		{"filter field opsional tidak cocok dengan null", "lebar_biji_mm[lt]=2.5", []int{1, 2, 3, 5}, 4, false, false},
+		{"filter tanpa hasil", "panjang_biji_mm[gt]=8", []int{}, 0, false, false},
+		{"sort desc dengan id sebagai pembanding terakhir", "sort=-panjang_biji_mm",
+			[]int{4, 8, 3, 7, 11, 2, 6, 10, 1, 5, 9}, 11, false, false},
+		{"sort beberapa field", "sort=warna,-id_padi", []int{9, 6, 3, 11, 8, 5, 2, 10, 7, 4, 1}, 11, false, false},
+		{"sort asc null di akhir", "sort=lebar_biji_mm", []int{1, 2, 3, 5, 6, 7, 9, 10, 11, 4, 8}, 11, false, false},
+		{"sort desc null di awal", "sort=-lebar_biji_mm", []int{4, 8, 11, 10, 9, 7, 6, 5, 3, 2, 1}, 11, false, false},
+		{"halaman pertama", "per_page=4", []int{1, 2, 3, 4}, 11, true, false},
+		{"halaman tengah", "page=2&per_page=4", []int{5, 6, 7, 8}, 11, true, true},
+		{"halaman terakhir tidak penuh", "page=3&per_page=4", []int{9, 10, 11}, 11, false, true},
+		{"offset melewati akhir", "page=4&per_page=4", []int{}, 11, false, true},
+		{"offset jauh melewati akhir", "page=1000&per_page=100", []int{}, 11, false, true},
+		{"filter, sort, dan paging", "warna=Merah&sort=-id_padi&page=2&per_page=3", []int{2}, 4, false, true},
+		{"per_page dibatasi", "per_page=1000", []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}, 11, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nama, func(t *testing.T) {
+			w := kirim(h.GetAll, http.MethodGet, "/api/varietas?"+tt.query, "", nil, nil)
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, ingin 200: %s", w.Code, w.Body)
+			}
+			var body struct {
+				Total int                   `json:"total"`
+				Next  *string               `json:"next"`
+				Prev  *string               `json:"prev"`
+				Data  []domain.VarietasPadi `json:"data"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+
+			got := make([]int, len(body.Data))
+			for i, v := range body.Data {
+				got[i] = v.ID
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("id = %v, ingin %v", got, tt.want)
+			}
+			if body.Data == nil {
+				t.Error("data = null, ingin array kosong")
+			}
+			if body.Total != tt.total || w.Header().Get("X-Total-Count") != strconv.Itoa(tt.total) {
+				t.Errorf("total = %d (X-Total-Count %s), ingin %d", body.Total, w.Header().Get("X-Total-Count"), tt.total)
+			}
+			if (body.Next != nil) != tt.next || (body.Prev != nil) != tt.prev {
+				t.Errorf("next = %v, prev = %v, ingin ada next %v, ada prev %v", body.Next, body.Prev, tt.next, tt.prev)
+			}
+		})
+	}
+}
+
+func TestParameterKhususEndpoint(t *testing.T) {
+	h, svc := newTestHandler(t)
+	tambahContoh(t, svc, 3)
+
+	tests := []struct {
+		nama   string
+		fn     http.HandlerFunc
+		target string
+		want   int
+	}{
+		{"group_by di daftar ditolak", h.GetAll, "/api/varietas?group_by=warna", http.StatusBadRequest},
+		{"format di daftar ditolak", h.GetAll, "/api/varietas?format=csv", http.StatusBadRequest},
+		{"group_by di tempat sampah ditolak", h.GetTrash, "/api/varietas/trash?group_by=warna", http.StatusBadRequest},
+		{"group_by di statistik", h.Stats, "/api/varietas/stats?group_by=warna&warna=Putih", http.StatusOK},
+		{"format di statistik ditolak", h.Stats, "/api/varietas/stats?format=csv", http.StatusBadRequest},
+		{"format di ekspor", h.Export, "/api/varietas/export?format=ndjson&warna=Putih", http.StatusOK},
+		{"group_by di ekspor ditolak", h.Export, "/api/varietas/export?group_by=warna", http.StatusBadRequest},
+		{"parameter cache tetap dilewati", h.GetAll, "/api/varietas?_=1700000000000", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nama, func(t *testing.T) {
+			w := kirim(tt.fn, http.MethodGet, tt.target, "", nil, nil)
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, ingin %d: %s", w.Code, tt.want, w.Body)
+			}
+			if tt.want == http.StatusBadRequest && !strings.Contains(w.Body.String(), "tidak dikenal") {
+				t.Errorf("body = %s, ingin menyebut parameter yang tidak dikenal", w.Body)
+			}
+		})
+	}
+}
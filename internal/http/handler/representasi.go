@@ -0,0 +1,206 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/ekspor"
+)
+
+// representasi adalah satu bentuk response VarietasPadi yang bisa dipilih client
+// lewat header Accept. Handler hanya memanggil tulisSatu/tulisDaftar; format baru
+// cukup didaftarkan di package ekspor tanpa mengubah handler.
+type representasi struct {
+	Nama        string // nama format, dipakai di ETag (lihat etagRepresentasi)
+	MediaType   string // tanpa parameter, dipakai untuk negosiasi
+	ContentType string
+	Satu        func(w io.Writer, v domain.VarietasPadi) error
+	Daftar      func(w io.Writer, d daftarVarietas) error
+}
+
+// daftarVarietas adalah satu halaman data beserta link pagination (nil jika tidak ada)
+type daftarVarietas struct {
+	Page       domain.VarietasPage
+	Next, Prev any
+}
+
+// representasiJSON adalah representasi bawaan dengan envelope {"success", "data"}
+var representasiJSON = representasi{
+	Nama:        "json",
+	MediaType:   "application/json",
+	ContentType: "application/json",
+	Satu: func(w io.Writer, v domain.VarietasPadi) error {
+		return json.NewEncoder(w).Encode(map[string]any{"success": true, "data": v})
+	},
+	Daftar: func(w io.Writer, d daftarVarietas) error {
+		return json.NewEncoder(w).Encode(map[string]any{
+			"success":  true,
+			"total":    d.Page.Total,
+			"page":     d.Page.Page,
+			"per_page": d.Page.PerPage,
+			"next":     d.Next,
+			"prev":     d.Prev,
+			"data":     d.Page.Data,
+		})
+	},
+}
+
+// daftarRepresentasi berurutan sesuai preferensi server: JSON lebih dulu, lalu semua
+// format ekspor. Urutan ini menentukan pilihan saat Accept berisi wildcard.
+var daftarRepresentasi = func() []representasi {
+	reps := []representasi{representasiJSON}
+	for _, nama := range ekspor.Names() {
+		format, _ := ekspor.Lookup(nama)
+		mediaType, _, err := mime.ParseMediaType(format.ContentType)
+		if err != nil {
+			continue
+		}
+		reps = append(reps, representasi{
+			Nama:        nama,
+			MediaType:   mediaType,
+			ContentType: format.ContentType,
+			Satu:        format.TulisSatu,
+			Daftar: func(w io.Writer, d daftarVarietas) error {
+				writer := format.New(w)
+				for _, v := range d.Page.Data {
+					if err := writer.Write(v); err != nil {
+						return err
+					}
+				}
+				return writer.Close()
+			},
+		})
+	}
+	return reps
+}()
+
+// rentangMedia adalah satu media range dari header Accept, misalnya text/* ;q=0.5
+type rentangMedia struct {
+	tipe, subtipe string
+	q             float64
+}
+
+// parseAccept memecah header Accept. Media range yang tidak valid diabaikan.
+func parseAccept(header string) []rentangMedia {
+	var hasil []rentangMedia
+	for _, part := range strings.Split(header, ",") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		mediaType, params, err := mime.ParseMediaType(part)
+		if err != nil {
+			continue
+		}
+		tipe, subtipe, ok := strings.Cut(mediaType, "/")
+		if !ok {
+			continue
+		}
+		q := 1.0
+		if raw, ada := params["q"]; ada {
+			q, err = strconv.ParseFloat(raw, 64)
+			if err != nil || q < 0 || q > 1 {
+				continue
+			}
+		}
+		hasil = append(hasil, rentangMedia{tipe: tipe, subtipe: subtipe, q: q})
+	}
+	return hasil
+}
+
+// kualitas mengembalikan nilai q untuk mediaType dari media range paling spesifik
+// yang cocok (type/subtype > type/* > */*), atau 0 jika tidak ada yang cocok
+func kualitas(ranges []rentangMedia, mediaType string) float64 {
+	tipe, subtipe, _ := strings.Cut(mediaType, "/")
+	q, spesifik := 0.0, -1
+	for _, rg := range ranges {
+		s := -1
+		switch {
+		case rg.tipe == tipe && rg.subtipe == subtipe:
+			s = 2
+		case rg.tipe == tipe && rg.subtipe == "*":
+			s = 1
+		case rg.tipe == "*" && rg.subtipe == "*":
+			s = 0
+		}
+		if s > spesifik {
+			q, spesifik = rg.q, s
+		}
+	}
+	return q
+}
+
+// pilihRepresentasi memilih representasi terbaik untuk header Accept (RFC 9110 bagian 12.5.1).
+// Accept kosong berarti JSON. ok=false jika tidak ada representasi yang bisa diterima client.
+func pilihRepresentasi(accept string) (representasi, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return representasiJSON, true
+	}
+	ranges := parseAccept(accept)
+
+	terbaik, qTerbaik := representasi{}, 0.0
+	for _, rep := range daftarRepresentasi {
+		if q := kualitas(ranges, rep.MediaType); q > qTerbaik {
+			terbaik, qTerbaik = rep, q
+		}
+	}
+	return terbaik, qTerbaik > 0
+}
+
+// pilihMediaType seperti pilihRepresentasi tetapi untuk daftar media type biasa, berurutan
+// sesuai preferensi server. Accept kosong berarti media type pertama.
+func pilihMediaType(accept string, tersedia ...string) (string, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return tersedia[0], true
+	}
+	ranges := parseAccept(accept)
+
+	terbaik, qTerbaik := "", 0.0
+	for _, mediaType := range tersedia {
+		if q := kualitas(ranges, mediaType); q > qTerbaik {
+			terbaik, qTerbaik = mediaType, q
+		}
+	}
+	return terbaik, qTerbaik > 0
+}
+
+// negosiasi memilih representasi response dari header Accept. Jika tidak ada yang
+// cocok, 406 Not Acceptable langsung dikirim dan ok=false.
+func negosiasi(w http.ResponseWriter, r *http.Request) (representasi, bool) {
+	w.Header().Add("Vary", "Accept")
+	rep, ok := pilihRepresentasi(r.Header.Get("Accept"))
+	if !ok {
+		respondProblem(w, newProblem(r, http.StatusNotAcceptable,
+			"media type yang didukung: "+strings.Join(mediaTypeTersedia(), ", ")))
+	}
+	return rep, ok
+}
+
+// mediaTypeTersedia mengembalikan media type semua representasi, terurut
+func mediaTypeTersedia() []string {
+	types := make([]string, 0, len(daftarRepresentasi))
+	for _, rep := range daftarRepresentasi {
+		types = append(types, rep.MediaType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// respondRepresentasi menyandikan body ke buffer lebih dulu, sehingga kegagalan encoding
+// masih bisa dibalas sebagai problem+json. Ukuran body dibatasi pagination.
+func respondRepresentasi(w http.ResponseWriter, r *http.Request, rep representasi, encode func(io.Writer) error) {
+	var buf bytes.Buffer
+	if err := encode(&buf); err != nil {
+		respondError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", rep.ContentType)
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
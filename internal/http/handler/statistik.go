@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Stats: GET /varietas/stats?group_by=varietas_kelas
+// Statistik deskriptif panjang_biji_mm dan tabel frekuensi field kategorikal, per grup.
+// Filter sama seperti GET /varietas; page, per_page, dan sort diabaikan.
+func (h *VarietasHandler) Stats(w http.ResponseWriter, r *http.Request) {
+	q, err := parseVarietasQuery(r.URL.Query(), "group_by")
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+	defer cancel()
+
+	stats, err := h.service.StatistikData(ctx, q, r.URL.Query().Get("group_by"))
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]any{"success": true, "data": stats})
+}
@@ -3,8 +3,12 @@ package handler
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gorilla/mux" // Contoh router untuk mengambil path parameter
@@ -30,35 +34,76 @@ func respondJSON(w http.ResponseWriter, status int, payload any) {
 	json.NewEncoder(w).Encode(payload)
 }
 
+// parseID mengambil path parameter {id} dan memastikan nilainya bilangan bulat positif
+func parseID(r *http.Request) (int, error) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || id <= 0 {
+		return 0, errInvalidID
+	}
+	return id, nil
+}
+
 // --- FUNGSI HANDLER CRUD ---
 
 // GetAll: GET /varietas
+// Mendukung query ?page=, ?per_page=, ?sort=-field, dan filter seperti ?warna= atau ?panjang_biji_mm[gte]=
 func (h *VarietasHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	rep, ok := negosiasi(w, r)
+	if !ok {
+		return
+	}
+
+	q, err := parseVarietasQuery(r.URL.Query())
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
 	// Panggil Service Layer
 	// Kita ambil context dari request untuk diteruskan ke Service
 	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
 	defer cancel()
 
-	data, err := h.service.DapatkanSemuaData(ctx) // Panggil Service, BUKAN Repository
+	page, err := h.service.DapatkanSemuaData(ctx, q) // Panggil Service, BUKAN Repository
 	if err != nil {
-		// Logika Service Error
-		respondJSON(w, http.StatusInternalServerError, map[string]any{"success": false, "message": "Gagal mengambil data: " + err.Error()})
+		respondError(w, r, err)
 		return
 	}
 
-	respondJSON(w, http.StatusOK, map[string]any{
-		"success": true,
-		"total":   len(data),
-		"data":    data,
-	})
+	respondPage(w, r, rep, page)
+}
+
+// respondPage mengirim satu halaman data beserta metadata pagination.
+// Selain di body JSON, metadata juga dikirim lewat header X-Total-Count dan Link
+// karena format lain (CSV, NDJSON, ...) tidak punya tempat untuk envelope.
+func respondPage(w http.ResponseWriter, r *http.Request, rep representasi, page domain.VarietasPage) {
+	// Link halaman berikut/sebelumnya bernilai null jika tidak ada
+	d := daftarVarietas{Page: page}
+	var links []string
+	if page.HasNext() {
+		link := pageLink(r, page.Page+1, page.PerPage)
+		d.Next = link
+		links = append(links, fmt.Sprintf(`<%s>; rel="next"`, link))
+	}
+	if page.HasPrev() {
+		link := pageLink(r, page.Page-1, page.PerPage)
+		d.Prev = link
+		links = append(links, fmt.Sprintf(`<%s>; rel="prev"`, link))
+	}
+
+	w.Header().Set("X-Total-Count", strconv.Itoa(page.Total))
+	if len(links) > 0 {
+		w.Header().Set("Link", strings.Join(links, ", "))
+	}
+	respondRepresentasi(w, r, rep, func(out io.Writer) error { return rep.Daftar(out, d) })
 }
 
 // Create: POST /varietas
 func (h *VarietasHandler) Create(w http.ResponseWriter, r *http.Request) {
 	var varietas domain.VarietasPadi
 	// 1. Parsing Request Body
-	if err := json.NewDecoder(r.Body).Decode(&varietas); err != nil {
-		respondJSON(w, http.StatusBadRequest, map[string]any{"success": false, "message": "Format data JSON tidak valid"})
+	if err := decodeJSON(r, &varietas); err != nil {
+		respondError(w, r, err)
 		return
 	}
 
@@ -68,22 +113,31 @@ func (h *VarietasHandler) Create(w http.ResponseWriter, r *http.Request) {
 	// 2. Panggil Service Layer (Validasi terjadi di Service)
 	newVarietas, err := h.service.TambahkanData(ctx, varietas) // Panggil Service Create
 	if err != nil {
-		// Asumsi error dari Service adalah karena Validasi/Bisnis Logic
-		respondJSON(w, http.StatusBadRequest, map[string]any{"success": false, "message": "Gagal membuat data: " + err.Error()})
+		respondError(w, r, err)
 		return
 	}
 
-	respondJSON(w, http.StatusCreated, map[string]any{"success": true, "data": newVarietas})
+	resp := map[string]any{"success": true, "data": newVarietas}
+	// Peringatan duplikat hanya pelengkap; data sudah tersimpan walaupun pencariannya gagal
+	if kandidat, err := h.service.DapatkanKandidatDuplikat(ctx, newVarietas); err == nil && len(kandidat) > 0 {
+		resp["kandidat_duplikat"] = idKandidat(kandidat)
+	}
+
+	w.Header().Set("ETag", etagVarietas(newVarietas))
+	respondJSON(w, http.StatusCreated, resp)
 }
 
 // ReadByID: GET /varietas/{id}
+// Mengirim ETag dari versi data; If-None-Match yang cocok dibalas 304 Not Modified.
 func (h *VarietasHandler) GetByID(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	idStr := vars["id"]
+	rep, ok := negosiasi(w, r)
+	if !ok {
+		return
+	}
 
-	id, err := strconv.Atoi(idStr)
-	if err != nil || id <= 0 {
-		respondJSON(w, http.StatusBadRequest, map[string]any{"success": false, "message": "ID varietas tidak valid"})
+	id, err := parseID(r)
+	if err != nil {
+		respondError(w, r, err)
 		return
 	}
 
@@ -91,33 +145,38 @@ func (h *VarietasHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	defer cancel()
 
 	data, err := h.service.DapatkanDataByID(ctx, id)
+	var digabung *domain.DigabungError
+	if errors.As(err, &digabung) {
+		respondDigabung(w, r, digabung)
+		return
+	}
 	if err != nil {
-		// Cek jika errornya adalah 'not found' (dari Service Layer)
-		if err.Error() == "data varietas tidak ditemukan" {
-			respondJSON(w, http.StatusNotFound, map[string]any{"success": false, "message": err.Error()})
-			return
-		}
-		respondJSON(w, http.StatusInternalServerError, map[string]any{"success": false, "message": "Gagal mengambil data: " + err.Error()})
+		respondError(w, r, err)
 		return
 	}
 
-	respondJSON(w, http.StatusOK, map[string]any{"success": true, "data": data})
+	etag := etagRepresentasi(data, rep)
+	w.Header().Set("ETag", etag)
+	if noneMatch(r, etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	respondRepresentasi(w, r, rep, func(out io.Writer) error { return rep.Satu(out, data) })
 }
 
 // Update: PUT /varietas/{id}
+// Jika If-Match dikirim dan versinya sudah berubah, dibalas 412 Precondition Failed.
 func (h *VarietasHandler) Update(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	idStr := vars["id"]
-
-	id, err := strconv.Atoi(idStr)
-	if err != nil || id <= 0 {
-		respondJSON(w, http.StatusBadRequest, map[string]any{"success": false, "message": "ID varietas tidak valid"})
+	id, err := parseID(r)
+	if err != nil {
+		respondError(w, r, err)
 		return
 	}
 
 	var varietas domain.VarietasPadi
-	if err := json.NewDecoder(r.Body).Decode(&varietas); err != nil {
-		respondJSON(w, http.StatusBadRequest, map[string]any{"success": false, "message": "Format data JSON tidak valid"})
+	if err := decodeJSON(r, &varietas); err != nil {
+		respondError(w, r, err)
 		return
 	}
 
@@ -127,45 +186,215 @@ func (h *VarietasHandler) Update(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
 	defer cancel()
 
+	// Versi yang diharapkan hanya diambil dari If-Match, bukan dari field versi di body
+	varietas.Versi, err = h.ifMatchVersi(ctx, r, id)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
 	updatedData, err := h.service.UbahData(ctx, varietas)
 	if err != nil {
-		// Cek jika errornya adalah not found atau validation
-		if err.Error() == "data varietas tidak ditemukan" {
-			respondJSON(w, http.StatusNotFound, map[string]any{"success": false, "message": err.Error()})
-			return
-		}
-		respondJSON(w, http.StatusBadRequest, map[string]any{"success": false, "message": "Gagal mengubah data: " + err.Error()})
+		respondError(w, r, err)
 		return
 	}
 
+	w.Header().Set("ETag", etagVarietas(updatedData))
+	respondJSON(w, http.StatusOK, map[string]any{"success": true, "data": updatedData})
+}
+
+// Patch: PATCH /varietas/{id}
+// Menerima application/merge-patch+json (atau application/json) dan application/json-patch+json.
+func (h *VarietasHandler) Patch(w http.ResponseWriter, r *http.Request) {
+	id, err := parseID(r)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	patch, supported, err := parsePatch(r)
+	if !supported {
+		w.Header().Set("Accept-Patch", mediaMergePatch+", "+mediaJSONPatch)
+		respondProblem(w, newProblem(r, http.StatusUnsupportedMediaType,
+			"Content-Type harus "+mediaMergePatch+" atau "+mediaJSONPatch))
+		return
+	}
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	patch.Versi, err = h.ifMatchVersi(ctx, r, id)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	updatedData, err := h.service.UbahSebagian(ctx, id, patch)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	w.Header().Set("ETag", etagVarietas(updatedData))
 	respondJSON(w, http.StatusOK, map[string]any{"success": true, "data": updatedData})
 }
 
 // Delete: DELETE /varietas/{id}
+// Data dipindah ke tempat sampah dan bisa dipulihkan lewat POST /varietas/{id}/restore
 func (h *VarietasHandler) Delete(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	idStr := vars["id"]
-
-	id, err := strconv.Atoi(idStr)
-	if err != nil || id <= 0 {
-		respondJSON(w, http.StatusBadRequest, map[string]any{"success": false, "message": "ID varietas tidak valid"})
+	id, err := parseID(r)
+	if err != nil {
+		respondError(w, r, err)
 		return
 	}
 
 	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
 	defer cancel()
 
-	err = h.service.HapusData(ctx, id)
+	versi, err := h.ifMatchVersi(ctx, r, id)
 	if err != nil {
-		// Cek jika errornya adalah 'not found'
-		if err.Error() == "data varietas yang akan dihapus tidak ditemukan" {
-			respondJSON(w, http.StatusNotFound, map[string]any{"success": false, "message": err.Error()})
-			return
-		}
-		respondJSON(w, http.StatusInternalServerError, map[string]any{"success": false, "message": "Gagal menghapus data: " + err.Error()})
+		respondError(w, r, err)
+		return
+	}
+
+	if err := h.service.HapusData(ctx, id, versi); err != nil {
+		respondError(w, r, err)
 		return
 	}
 
 	// Menggunakan Status 204 No Content untuk operasi DELETE yang sukses tanpa mengembalikan body
 	respondJSON(w, http.StatusNoContent, nil)
 }
+
+// --- TEMPAT SAMPAH (SOFT DELETE) ---
+
+// GetTrash: GET /varietas/trash
+// Mendukung query pagination, sort, dan filter yang sama dengan GetAll
+func (h *VarietasHandler) GetTrash(w http.ResponseWriter, r *http.Request) {
+	rep, ok := negosiasi(w, r)
+	if !ok {
+		return
+	}
+
+	q, err := parseVarietasQuery(r.URL.Query())
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	page, err := h.service.DaftarSampah(ctx, q)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respondPage(w, r, rep, page)
+}
+
+// Restore: POST /varietas/{id}/restore
+func (h *VarietasHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	id, err := parseID(r)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	restored, err := h.service.PulihkanData(ctx, id)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	w.Header().Set("ETag", etagVarietas(restored))
+	respondJSON(w, http.StatusOK, map[string]any{"success": true, "data": restored})
+}
+
+// PurgeTrash: DELETE /varietas/trash?older_than=720h
+// Menghapus permanen data yang sudah berada di tempat sampah lebih lama dari older_than.
+// Parameter wajib diisi agar isi tempat sampah tidak terhapus semua karena salah klik.
+func (h *VarietasHandler) PurgeTrash(w http.ResponseWriter, r *http.Request) {
+	retensi, err := time.ParseDuration(r.URL.Query().Get("older_than"))
+	if err != nil {
+		respondError(w, r, domain.NewValidationError("parameter older_than tidak valid",
+			domain.FieldError{Field: "older_than", Code: "invalid", Message: "harus durasi Go, misalnya 720h atau 30m"}))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	n, err := h.service.BersihkanSampah(ctx, retensi)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]any{"success": true, "purged": n})
+}
+
+// --- RIWAYAT PERUBAHAN (AUDIT TRAIL) ---
+
+// History: GET /varietas/{id}/history
+// Mengembalikan semua revisi data dari yang terlama, lengkap dengan aktor dan diff per field
+func (h *VarietasHandler) History(w http.ResponseWriter, r *http.Request) {
+	id, err := parseID(r)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	riwayat, err := h.service.RiwayatData(ctx, id)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]any{"success": true, "data": riwayat})
+}
+
+// Revert: POST /varietas/{id}/history/{revisi}/revert
+// Mengembalikan data ke keadaan pada revisi tertentu; mendukung If-Match seperti PUT
+func (h *VarietasHandler) Revert(w http.ResponseWriter, r *http.Request) {
+	id, err := parseID(r)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+	revisiID, err := strconv.ParseInt(mux.Vars(r)["revisi"], 10, 64)
+	if err != nil || revisiID <= 0 {
+		respondError(w, r, domain.NewValidationError("ID revisi tidak valid",
+			domain.FieldError{Field: "revisi", Code: "invalid", Message: "harus bilangan bulat positif"}))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	versi, err := h.ifMatchVersi(ctx, r, id)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	reverted, err := h.service.KembalikanRevisi(ctx, id, revisiID, versi)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	w.Header().Set("ETag", etagVarietas(reverted))
+	respondJSON(w, http.StatusOK, map[string]any{"success": true, "data": reverted})
+}
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/repository"
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/service"
+)
+
+// newTestHandler membuat VarietasHandler di atas repository in-memory
+func newTestHandler(t *testing.T) (*VarietasHandler, domain.VarietasService) {
+	t.Helper()
+	repo := repository.NewMemoryVarietasRepository()
+	svc := service.NewVarietasService(repo, repository.NewMemoryRiwayatRepository(),
+		repository.NewMemoryKosakataRepository(repo), repository.NewMemoryTxManager(),
+		repository.NewMemoryKualitasRepository(), repository.NewMemoryPenggabunganRepository(),
+		domain.ModeDuplikatNonaktif)
+	return NewVarietasHandler(svc), svc
+}
+
+// kirim menjalankan handler dengan path parameter vars (pengganti routing mux)
+func kirim(h http.HandlerFunc, method, target, body string, vars map[string]string, header map[string]string) *httptest.ResponseRecorder {
+	var r *http.Request
+	if body == "" {
+		r = httptest.NewRequest(method, target, nil)
+	} else {
+		r = httptest.NewRequest(method, target, strings.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+	}
+	for k, v := range header {
+		r.Header.Set(k, v)
+	}
+	if vars != nil {
+		r = mux.SetURLVars(r, vars)
+	}
+	w := httptest.NewRecorder()
+	h(w, r)
+	return w
+}
+
+// tambahContoh menyimpan n data contoh lewat service dan mengembalikan hasilnya
+func tambahContoh(t *testing.T, svc domain.VarietasService, n int) []domain.VarietasPadi {
+	t.Helper()
+	hasil := make([]domain.VarietasPadi, 0, n)
+	for i := range n {
+		v, err := svc.TambahkanData(context.Background(), domain.VarietasPadi{
+			VarietasKelas:    "IR64",
+			Warna:            "Putih",
+			PanjangBijiMM:    6 + float64(i)/10,
+			TeksturPermukaan: "Halus",
+			BentukUjungDaun:  "Runcing",
+		})
+		if err != nil {
+			t.Fatalf("TambahkanData: %v", err)
+		}
+		hasil = append(hasil, v)
+	}
+	return hasil
+}
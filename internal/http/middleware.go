@@ -0,0 +1,80 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gorilla/mux"
+
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/autentikasi"
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/http/handler"
+)
+
+// HeaderActor berisi nama pelaku perubahan yang dicatat di riwayat data. Hanya dipakai
+// jika autentikasi dimatikan; nilainya tidak diverifikasi.
+const HeaderActor = "X-Actor"
+
+// actorFromHeader menyimpan isi header X-Actor di context request (lihat domain.WithActor)
+func actorFromHeader(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		aktor := domain.CollapseSpaces(r.Header.Get(HeaderActor))
+		if aktor != "" {
+			// Batas panjang sama dengan kolom riwayat_varietas.aktor
+			if utf8.RuneCountInString(aktor) > 100 {
+				aktor = string([]rune(aktor)[:100])
+			}
+			r = r.WithContext(domain.WithActor(r.Context(), strings.TrimSpace(aktor)))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// OpsiAuth mengatur autentikasi API. Verifier nil berarti autentikasi dimatikan dan
+// pelaku perubahan diambil dari header X-Actor seperti sebelumnya.
+type OpsiAuth struct {
+	Verifier *autentikasi.Verifier
+	// AnonimBaca mengizinkan GET/HEAD/OPTIONS tanpa token
+	AnonimBaca bool
+}
+
+// metodeBaca adalah metode yang tidak mengubah data
+func metodeBaca(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+// bearerAuth memverifikasi token di header Authorization dan menyimpan principal-nya di
+// context request (lihat domain.WithPrincipal). Token yang dikirim selalu diverifikasi,
+// juga pada GET; request tanpa token hanya diteruskan untuk metode baca jika AnonimBaca.
+func bearerAuth(opsi OpsiAuth) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			raw := r.Header.Get("Authorization")
+			if raw == "" {
+				if opsi.AnonimBaca && metodeBaca(r.Method) {
+					next.ServeHTTP(w, r)
+					return
+				}
+				w.Header().Set("WWW-Authenticate", `Bearer realm="varietas-padi"`)
+				handler.RespondError(w, r, fmt.Errorf("%w: kirim header Authorization: Bearer <token>", domain.ErrUnauthorized))
+				return
+			}
+
+			skema, token, _ := strings.Cut(raw, " ")
+			if !strings.EqualFold(skema, "Bearer") || strings.TrimSpace(token) == "" {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="varietas-padi", error="invalid_request"`)
+				handler.RespondError(w, r, fmt.Errorf("%w: header Authorization harus berformat Bearer <token>", domain.ErrUnauthorized))
+				return
+			}
+			principal, err := opsi.Verifier.Verifikasi(strings.TrimSpace(token))
+			if err != nil {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="varietas-padi", error="invalid_token"`)
+				handler.RespondError(w, r, err)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(domain.WithPrincipal(r.Context(), principal)))
+		})
+	}
+}
@@ -2,17 +2,25 @@ package http
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/gorilla/mux"
 
 	// Import Handler yang sudah kita buat sebelumnya
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
 	"github.com/Farewellez/REST-API_VarietasPadi/internal/http/handler"
 )
 
 // NewRouter membuat dan menginisialisasi rute-rute aplikasi
-func NewRouter(varietasHandler *handler.VarietasHandler) *mux.Router {
+func NewRouter(varietasHandler *handler.VarietasHandler, kosakataHandler *handler.KosakataHandler, auth OpsiAuth) *mux.Router {
 	r := mux.NewRouter()
 
+	// Identitas pelaku perubahan untuk riwayat (audit trail): principal dari token JWT
+	// (dipasang per sub-router API di bawah), atau header X-Actor jika autentikasi dimatikan
+	if auth.Verifier == nil {
+		r.Use(actorFromHeader)
+	}
+
 	// 1. PENANGANAN ASSET STATIS (CSS, JS, GAMBAR)
 	// Menyajikan semua file di dalam direktori 'views/'
 	// Jika file static ada di 'views/css/style.css', akan diakses melalui /static/css/style.css
@@ -44,13 +52,71 @@ func NewRouter(varietasHandler *handler.VarietasHandler) *mux.Router {
 	// 3. PENANGANAN API
 	// Sub-router untuk semua endpoint API Varietas
 	api := r.PathPrefix("/api/varietas").Subrouter()
+	if auth.Verifier != nil {
+		api.Use(bearerAuth(auth))
+	}
 
 	// --- Rute CRUD Varietas Padi ---
 	api.HandleFunc("", varietasHandler.GetAll).Methods(http.MethodGet)
 	api.HandleFunc("", varietasHandler.Create).Methods(http.MethodPost)
+
+	// Impor CSV massal dan ekspor (csv, ndjson, xlsx, parquet)
+	api.HandleFunc("/import", varietasHandler.Import).Methods(http.MethodPost)
+	api.HandleFunc("/export", varietasHandler.Export).Methods(http.MethodGet)
+
+	// Banyak operasi create/update/delete dalam satu transaksi (sinkronisasi client mobile)
+	api.HandleFunc("/batch", varietasHandler.Batch).Methods(http.MethodPost)
+
+	// Statistik deskriptif per grup (group_by)
+	api.HandleFunc("/stats", varietasHandler.Stats).Methods(http.MethodGet)
+
+	// Prediksi varietas_kelas dari ciri morfologi (model naive Bayes)
+	api.HandleFunc("/predict", varietasHandler.Predict).Methods(http.MethodPost)
+	api.HandleFunc("/model/retrain", varietasHandler.RetrainModel).Methods(http.MethodPost)
+	api.HandleFunc("/model/evaluation", varietasHandler.ModelEvaluation).Methods(http.MethodGet)
+
+	// Pencarian data termirip dari sampel baru atau dari data yang sudah ada
+	api.HandleFunc("/similar", varietasHandler.SimilarToSample).Methods(http.MethodPost)
+	api.HandleFunc("/{id}/similar", varietasHandler.Similar).Methods(http.MethodGet)
+
+	// Pemeriksaan kualitas data: daftar temuan, pengakuan reviewer, dan perbaikan
+	api.HandleFunc("/quality", varietasHandler.Quality).Methods(http.MethodGet)
+	api.HandleFunc("/quality/{kode}/ack", varietasHandler.AcknowledgeQuality).Methods(http.MethodPost)
+	api.HandleFunc("/quality/{kode}/ack", varietasHandler.UnacknowledgeQuality).Methods(http.MethodDelete)
+	api.HandleFunc("/quality/{kode}/fix", varietasHandler.FixQuality).Methods(http.MethodPost)
+
+	// Penggabungan data duplikat; ID yang digabung diarahkan ke data utama
+	api.HandleFunc("/merge", varietasHandler.Merge).Methods(http.MethodPost)
+
+	// Tempat sampah harus didaftarkan sebelum /{id} agar "trash" tidak dianggap ID
+	api.HandleFunc("/trash", varietasHandler.GetTrash).Methods(http.MethodGet)
+	api.HandleFunc("/trash", varietasHandler.PurgeTrash).Methods(http.MethodDelete)
+	api.HandleFunc("/{id}/restore", varietasHandler.Restore).Methods(http.MethodPost)
+
+	// Riwayat perubahan per data dan revert ke revisi sebelumnya
+	api.HandleFunc("/{id}/history", varietasHandler.History).Methods(http.MethodGet)
+	api.HandleFunc("/{id}/history/{revisi}/revert", varietasHandler.Revert).Methods(http.MethodPost)
+
 	api.HandleFunc("/{id}", varietasHandler.GetByID).Methods(http.MethodGet)
 	api.HandleFunc("/{id}", varietasHandler.Update).Methods(http.MethodPut)
+	api.HandleFunc("/{id}", varietasHandler.Patch).Methods(http.MethodPatch)
 	api.HandleFunc("/{id}", varietasHandler.Delete).Methods(http.MethodDelete)
 
+	// 4. KOSAKATA TERKONTROL
+	// Satu sub-router per kategori: /api/warna, /api/tekstur-permukaan, /api/bentuk-ujung-daun
+	for _, kategori := range domain.KategoriKosakata {
+		kos := r.PathPrefix("/api/" + strings.ReplaceAll(kategori, "_", "-")).Subrouter()
+		if auth.Verifier != nil {
+			kos.Use(bearerAuth(auth))
+		}
+		kos.HandleFunc("", kosakataHandler.List(kategori)).Methods(http.MethodGet)
+		kos.HandleFunc("", kosakataHandler.Create(kategori)).Methods(http.MethodPost)
+		kos.HandleFunc("/{id}", kosakataHandler.Get(kategori)).Methods(http.MethodGet)
+		kos.HandleFunc("/{id}", kosakataHandler.Update(kategori)).Methods(http.MethodPut)
+		kos.HandleFunc("/{id}", kosakataHandler.Delete(kategori)).Methods(http.MethodDelete)
+		kos.HandleFunc("/{id}/alias", kosakataHandler.AddAlias(kategori)).Methods(http.MethodPost)
+		kos.HandleFunc("/{id}/alias/{alias}", kosakataHandler.RemoveAlias(kategori)).Methods(http.MethodDelete)
+	}
+
 	return r
 }
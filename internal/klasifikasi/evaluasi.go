@@ -0,0 +1,117 @@
+package klasifikasi
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
+)
+
+// Evaluasi menjalankan stratified k-fold cross-validation: data setiap kelas dibagi rata
+// ke lipatan (diurutkan menurut ID lalu dibagi bergiliran, sehingga hasilnya bisa diulang),
+// lalu setiap lipatan diprediksi oleh model yang dilatih dari lipatan lainnya.
+// Kelas yang datanya lebih sedikit dari lipatan tetap dievaluasi; saat satu-satunya datanya
+// diuji, model memang belum mengenal kelas itu dan prediksinya dihitung salah.
+func Evaluasi(data []domain.VarietasPadi, lipatan int) (domain.EvaluasiModel, error) {
+	var berlabel []domain.VarietasPadi
+	for _, v := range data {
+		if v.VarietasKelas != "" {
+			berlabel = append(berlabel, v)
+		}
+	}
+	if len(berlabel) == 0 {
+		return domain.EvaluasiModel{}, ErrDataKosong
+	}
+	if lipatan < 2 || lipatan > len(berlabel) {
+		return domain.EvaluasiModel{}, fmt.Errorf("jumlah lipatan %d tidak bisa dipakai untuk %d data", lipatan, len(berlabel))
+	}
+
+	// Label matriks terurut; indeks dipakai untuk kelas asli maupun prediksi
+	var label []string
+	indeks := map[string]int{}
+	for _, v := range berlabel {
+		if _, ok := indeks[v.VarietasKelas]; !ok {
+			indeks[v.VarietasKelas] = 0
+			label = append(label, v.VarietasKelas)
+		}
+	}
+	sort.Strings(label)
+	for i, l := range label {
+		indeks[l] = i
+	}
+
+	sort.Slice(berlabel, func(i, j int) bool {
+		if berlabel[i].VarietasKelas != berlabel[j].VarietasKelas {
+			return berlabel[i].VarietasKelas < berlabel[j].VarietasKelas
+		}
+		return berlabel[i].ID < berlabel[j].ID
+	})
+	// Bagi bergiliran per kelas; giliran berlanjut antar kelas agar kelas kecil tidak
+	// selalu jatuh ke lipatan pertama
+	bagian := make([]int, len(berlabel))
+	for i := range berlabel {
+		bagian[i] = i % lipatan
+	}
+
+	matriks := make([][]int, len(label))
+	for i := range matriks {
+		matriks[i] = make([]int, len(label))
+	}
+	benar := 0
+	for f := range lipatan {
+		var latih, uji []domain.VarietasPadi
+		for i, v := range berlabel {
+			if bagian[i] == f {
+				uji = append(uji, v)
+			} else {
+				latih = append(latih, v)
+			}
+		}
+		if len(uji) == 0 {
+			continue
+		}
+		model, err := Latih(latih)
+		if err != nil {
+			return domain.EvaluasiModel{}, err
+		}
+		for _, v := range uji {
+			tebakan := model.Prediksi(v)[0].VarietasKelas
+			matriks[indeks[v.VarietasKelas]][indeks[tebakan]]++
+			if tebakan == v.VarietasKelas {
+				benar++
+			}
+		}
+	}
+
+	hasil := domain.EvaluasiModel{
+		Algoritma:      Algoritma,
+		JumlahLipatan:  lipatan,
+		JumlahData:     len(berlabel),
+		Akurasi:        float64(benar) / float64(len(berlabel)),
+		Kelas:          make([]domain.MetrikKelas, len(label)),
+		MatriksKonfusi: domain.MatriksKonfusi{Label: label, Nilai: matriks},
+	}
+	var totalF1 float64
+	for i, l := range label {
+		var support, diprediksi int
+		for j := range label {
+			support += matriks[i][j]
+			diprediksi += matriks[j][i]
+		}
+		m := domain.MetrikKelas{VarietasKelas: l, Support: support}
+		// Pembagian dengan nol (kelas tidak pernah diprediksi) dianggap 0
+		if diprediksi > 0 {
+			m.Precision = float64(matriks[i][i]) / float64(diprediksi)
+		}
+		if support > 0 {
+			m.Recall = float64(matriks[i][i]) / float64(support)
+		}
+		if m.Precision+m.Recall > 0 {
+			m.F1 = 2 * m.Precision * m.Recall / (m.Precision + m.Recall)
+		}
+		hasil.Kelas[i] = m
+		totalF1 += m.F1
+	}
+	hasil.MacroF1 = totalF1 / float64(len(label))
+	return hasil, nil
+}
@@ -0,0 +1,68 @@
+package klasifikasi
+
+import (
+	"math"
+	"sort"
+
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
+)
+
+// pengukur menghitung jarak antar data dengan bobot tertentu (jarak Gower). Selisih
+// panjang biji dinormalisasi dengan rentang panjang biji data pembanding beserta acuannya.
+type pengukur struct {
+	bobot   domain.BobotJarak
+	rentang float64
+}
+
+// newPengukur menyiapkan pengukur untuk membandingkan acuan dengan data
+func newPengukur(bobot domain.BobotJarak, acuan domain.VarietasPadi, data []domain.VarietasPadi) *pengukur {
+	min, max := acuan.PanjangBijiMM, acuan.PanjangBijiMM
+	for _, v := range data {
+		min, max = math.Min(min, v.PanjangBijiMM), math.Max(max, v.PanjangBijiMM)
+	}
+	return &pengukur{bobot: bobot, rentang: max - min}
+}
+
+// hitung mengembalikan jarak a dan b, dari 0 (identik) sampai 1
+func (p *pengukur) hitung(a, b domain.VarietasPadi) float64 {
+	var jarak float64
+	// Semua panjang biji sama (rentang 0) berarti ciri ini tidak membedakan data mana pun
+	if p.rentang > 0 {
+		jarak += p.bobot.PanjangBijiMM * math.Abs(a.PanjangBijiMM-b.PanjangBijiMM) / p.rentang
+	}
+	if a.Warna != b.Warna {
+		jarak += p.bobot.Warna
+	}
+	if a.TeksturPermukaan != b.TeksturPermukaan {
+		jarak += p.bobot.TeksturPermukaan
+	}
+	if a.BentukUjungDaun != b.BentukUjungDaun {
+		jarak += p.bobot.BentukUjungDaun
+	}
+	return jarak / p.bobot.Total()
+}
+
+// Terdekat mengembalikan k data yang paling mirip dengan acuan, terurut dari jarak
+// terkecil lalu ID terkecil. Data dengan ID yang sama dengan acuan (acuan itu sendiri)
+// dilewati jika acuan punya ID.
+func Terdekat(acuan domain.VarietasPadi, data []domain.VarietasPadi, bobot domain.BobotJarak, k int) []domain.Tetangga {
+	p := newPengukur(bobot, acuan, data)
+	tetangga := make([]domain.Tetangga, 0, len(data))
+	for _, v := range data {
+		if acuan.ID != 0 && v.ID == acuan.ID {
+			continue
+		}
+		jarak := p.hitung(acuan, v)
+		tetangga = append(tetangga, domain.Tetangga{Data: v, Jarak: jarak, Kemiripan: 1 - jarak})
+	}
+	sort.Slice(tetangga, func(i, j int) bool {
+		if tetangga[i].Jarak != tetangga[j].Jarak {
+			return tetangga[i].Jarak < tetangga[j].Jarak
+		}
+		return tetangga[i].Data.ID < tetangga[j].Data.ID
+	})
+	if len(tetangga) > k {
+		tetangga = tetangga[:k]
+	}
+	return tetangga
+}
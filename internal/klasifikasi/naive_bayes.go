@@ -0,0 +1,172 @@
+// Package klasifikasi berisi model naive Bayes sederhana (pure Go) untuk menebak
+// varietas_kelas dari ciri morfologi: warna, tekstur permukaan, dan bentuk ujung daun
+// (kategorikal) serta panjang biji (numerik, distribusi normal per kelas), juga pencarian
+// data yang paling mirip dengan sebuah sampel berdasarkan ciri yang sama.
+package klasifikasi
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
+)
+
+// Algoritma adalah nama model yang dilaporkan ke client
+const Algoritma = "naive-bayes"
+
+// Parameter penghalusan model
+const (
+	// alpha adalah Laplace smoothing untuk fitur kategorikal, sehingga nilai yang belum
+	// pernah muncul bersama sebuah kelas tidak membuat peluangnya nol
+	alpha = 1.0
+	// variansMinimum mencegah distribusi panjang biji terlalu runcing pada kelas yang
+	// datanya seragam (simpangan baku minimal 0.1 mm)
+	variansMinimum = 0.01
+)
+
+// ErrDataKosong dikembalikan Latih jika tidak ada data berlabel
+var ErrDataKosong = errors.New("belum ada data berlabel untuk melatih model")
+
+// fiturKategori adalah fitur kategorikal yang dipakai model, berurutan
+var fiturKategori = []func(v domain.VarietasPadi) string{
+	func(v domain.VarietasPadi) string { return v.Warna },
+	func(v domain.VarietasPadi) string { return v.TeksturPermukaan },
+	func(v domain.VarietasPadi) string { return v.BentukUjungDaun },
+}
+
+// Model adalah hasil pelatihan naive Bayes. Model tidak berubah setelah dilatih sehingga
+// aman dipakai bersamaan dari banyak goroutine.
+type Model struct {
+	kelas       []string
+	jumlahData  int
+	jumlahKelas []int              // jumlah sampel per kelas
+	frekuensi   [][]map[string]int // fitur -> kelas -> nilai -> jumlah
+	ragam       []int              // per fitur: jumlah nilai berbeda + 1 untuk nilai tak dikenal
+	rerata      []float64          // panjang biji per kelas
+	varians     []float64
+}
+
+// Latih membangun model dari data berlabel. Data tanpa varietas_kelas diabaikan.
+func Latih(data []domain.VarietasPadi) (*Model, error) {
+	indeks := map[string]int{}
+	m := &Model{}
+	for _, v := range data {
+		if v.VarietasKelas == "" {
+			continue
+		}
+		if _, ok := indeks[v.VarietasKelas]; !ok {
+			indeks[v.VarietasKelas] = len(m.kelas)
+			m.kelas = append(m.kelas, v.VarietasKelas)
+		}
+	}
+	if len(m.kelas) == 0 {
+		return nil, ErrDataKosong
+	}
+
+	k := len(m.kelas)
+	m.jumlahKelas = make([]int, k)
+	m.rerata = make([]float64, k)
+	m.varians = make([]float64, k)
+	m.frekuensi = make([][]map[string]int, len(fiturKategori))
+	m.ragam = make([]int, len(fiturKategori))
+	for f := range fiturKategori {
+		m.frekuensi[f] = make([]map[string]int, k)
+		for c := range k {
+			m.frekuensi[f][c] = map[string]int{}
+		}
+	}
+
+	// Rerata dan varians dihitung dengan algoritma Welford agar stabil secara numerik
+	m2 := make([]float64, k)
+	var rerataGlobal, m2Global float64
+	for _, v := range data {
+		c, ok := indeks[v.VarietasKelas]
+		if !ok {
+			continue
+		}
+		m.jumlahData++
+		m.jumlahKelas[c]++
+		for f, nilai := range fiturKategori {
+			m.frekuensi[f][c][nilai(v)]++
+		}
+
+		delta := v.PanjangBijiMM - m.rerata[c]
+		m.rerata[c] += delta / float64(m.jumlahKelas[c])
+		m2[c] += delta * (v.PanjangBijiMM - m.rerata[c])
+
+		deltaGlobal := v.PanjangBijiMM - rerataGlobal
+		rerataGlobal += deltaGlobal / float64(m.jumlahData)
+		m2Global += deltaGlobal * (v.PanjangBijiMM - rerataGlobal)
+	}
+
+	variansGlobal := math.Max(m2Global/float64(m.jumlahData), variansMinimum)
+	for c := range k {
+		if m.jumlahKelas[c] < 2 {
+			// Satu sampel tidak cukup untuk memperkirakan sebaran; pakai sebaran semua data
+			m.varians[c] = variansGlobal
+		} else {
+			m.varians[c] = math.Max(m2[c]/float64(m.jumlahKelas[c]), variansMinimum)
+		}
+	}
+	for f := range fiturKategori {
+		nilai := map[string]bool{}
+		for c := range k {
+			for n := range m.frekuensi[f][c] {
+				nilai[n] = true
+			}
+		}
+		m.ragam[f] = len(nilai) + 1
+	}
+	return m, nil
+}
+
+// JumlahData mengembalikan jumlah sampel berlabel yang dipakai melatih model
+func (m *Model) JumlahData() int { return m.jumlahData }
+
+// Kelas mengembalikan semua kelas yang dikenal model, terurut
+func (m *Model) Kelas() []string {
+	kelas := append([]string(nil), m.kelas...)
+	sort.Strings(kelas)
+	return kelas
+}
+
+// Prediksi menghitung peluang posterior setiap kelas untuk sampel, terurut dari yang
+// paling mungkin. Jumlah semua confidence adalah 1.
+func (m *Model) Prediksi(sampel domain.VarietasPadi) []domain.Prediksi {
+	skor := make([]float64, len(m.kelas))
+	for c := range m.kelas {
+		n := float64(m.jumlahKelas[c])
+		s := math.Log(n / float64(m.jumlahData))
+		for f, nilai := range fiturKategori {
+			frek := float64(m.frekuensi[f][c][nilai(sampel)])
+			s += math.Log((frek + alpha) / (n + alpha*float64(m.ragam[f])))
+		}
+		selisih := sampel.PanjangBijiMM - m.rerata[c]
+		s += -0.5*math.Log(2*math.Pi*m.varians[c]) - selisih*selisih/(2*m.varians[c])
+		skor[c] = s
+	}
+
+	// Softmax dengan log-sum-exp agar skor log yang sangat kecil tidak underflow
+	maks := math.Inf(-1)
+	for _, s := range skor {
+		maks = math.Max(maks, s)
+	}
+	var total float64
+	for c, s := range skor {
+		skor[c] = math.Exp(s - maks)
+		total += skor[c]
+	}
+
+	hasil := make([]domain.Prediksi, len(m.kelas))
+	for c, kelas := range m.kelas {
+		hasil[c] = domain.Prediksi{VarietasKelas: kelas, Confidence: skor[c] / total}
+	}
+	sort.SliceStable(hasil, func(i, j int) bool {
+		if hasil[i].Confidence != hasil[j].Confidence {
+			return hasil[i].Confidence > hasil[j].Confidence
+		}
+		return hasil[i].VarietasKelas < hasil[j].VarietasKelas
+	})
+	return hasil
+}
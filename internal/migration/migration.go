@@ -0,0 +1,311 @@
+// internal/migration/migration.go
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Semua file migrasi ditanam (embed) ke dalam binary, jadi tidak perlu
+// menyalin folder sql/ ke container.
+//
+//go:embed sql/*.sql
+var files embed.FS
+
+// lockKey adalah kunci pg_advisory_lock agar dua replika tidak migrasi bersamaan
+const lockKey int64 = 7_415_020_251
+
+// batasWaktuTandaDirty membatasi penulisan penanda dirty setelah migrasi gagal
+const batasWaktuTandaDirty = 5 * time.Second
+
+// fileNamePattern mengenali nama file seperti 0001_create_tabel.up.sql
+var fileNamePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// ErrDirty dikembalikan jika migrasi sebelumnya gagal di tengah jalan.
+// Perbaiki database secara manual lalu jalankan perintah force.
+var ErrDirty = errors.New("database dalam status dirty, jalankan 'force <versi>' setelah diperbaiki")
+
+// Migration adalah satu langkah perubahan skema beserta kebalikannya
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status adalah kondisi satu migrasi di database
+type Status struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	Dirty     bool
+	AppliedAt time.Time
+}
+
+// Migrator menjalankan migrasi terhadap satu database
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+// New membuat Migrator dengan migrasi yang sudah ditanam di binary
+func New(db *sql.DB) (*Migrator, error) {
+	migrations, err := load(files)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// load membaca dan mengurutkan file migrasi berdasarkan versi
+func load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, "sql")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, e := range entries {
+		m := fileNamePattern.FindStringSubmatch(e.Name())
+		if m == nil {
+			return nil, fmt.Errorf("nama file migrasi tidak valid: %s", e.Name())
+		}
+		version, _ := strconv.ParseInt(m[1], 10, 64)
+		body, err := fs.ReadFile(fsys, "sql/"+e.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		mig, ok := byVersion[version]
+		if !ok {
+			mig = &Migration{Version: version, Name: m[2]}
+			byVersion[version] = mig
+		}
+		if mig.Name != m[2] {
+			return nil, fmt.Errorf("versi %d dipakai oleh dua migrasi berbeda", version)
+		}
+		if m[3] == "up" {
+			mig.Up = string(body)
+		} else {
+			mig.Down = string(body)
+		}
+	}
+
+	result := make([]Migration, 0, len(byVersion))
+	for _, mig := range byVersion {
+		if mig.Up == "" {
+			return nil, fmt.Errorf("migrasi %d_%s tidak punya file .up.sql", mig.Version, mig.Name)
+		}
+		result = append(result, *mig)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Version < result[j].Version })
+	return result, nil
+}
+
+// withLock menjalankan fn di satu koneksi khusus yang memegang advisory lock.
+// Advisory lock bersifat per-session, jadi semua query harus lewat koneksi yang sama.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, lockKey); err != nil {
+		return fmt.Errorf("gagal mengambil advisory lock migrasi: %w", err)
+	}
+	// Unlock memakai context baru agar tetap jalan walau ctx sudah dibatalkan
+	defer conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, lockKey)
+
+	if _, err := conn.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version    BIGINT PRIMARY KEY,
+			name       TEXT        NOT NULL,
+			dirty      BOOLEAN     NOT NULL DEFAULT FALSE,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+		)
+	`); err != nil {
+		return fmt.Errorf("gagal membuat tabel schema_migrations: %w", err)
+	}
+
+	return fn(conn)
+}
+
+// applied membaca catatan migrasi yang sudah dijalankan, diindeks per versi
+func applied(ctx context.Context, conn *sql.Conn) (map[int64]Status, error) {
+	rows, err := conn.QueryContext(ctx, `SELECT version, name, dirty, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := map[int64]Status{}
+	for rows.Next() {
+		var s Status
+		if err := rows.Scan(&s.Version, &s.Name, &s.Dirty, &s.AppliedAt); err != nil {
+			return nil, err
+		}
+		s.Applied = !s.Dirty
+		result[s.Version] = s
+	}
+	return result, rows.Err()
+}
+
+// checkDirty menolak migrasi baru jika ada versi yang masih dirty
+func checkDirty(done map[int64]Status) error {
+	for _, s := range done {
+		if s.Dirty {
+			return fmt.Errorf("%w (versi %d)", ErrDirty, s.Version)
+		}
+	}
+	return nil
+}
+
+// run menjalankan satu skrip migrasi di dalam transaksi lalu memperbarui schema_migrations.
+// Jika skrip gagal, versi tersebut ditandai dirty di luar transaksi. Penanda dirty tidak
+// memakai ctx langsung karena skrip bisa gagal justru karena ctx dibatalkan (timeout atau
+// Ctrl+C), dan penandanya tetap harus tersimpan.
+func run(ctx context.Context, conn *sql.Conn, mig Migration, script string, up bool) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		tx.Rollback()
+		tandaCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), batasWaktuTandaDirty)
+		defer cancel()
+		if _, dErr := conn.ExecContext(tandaCtx, `
+			INSERT INTO schema_migrations (version, name, dirty) VALUES ($1, $2, TRUE)
+			ON CONFLICT (version) DO UPDATE SET dirty = TRUE
+		`, mig.Version, mig.Name); dErr != nil {
+			return fmt.Errorf("migrasi %d_%s gagal: %w (penanda dirty juga gagal disimpan: %v)", mig.Version, mig.Name, err, dErr)
+		}
+		return fmt.Errorf("migrasi %d_%s gagal: %w", mig.Version, mig.Name, err)
+	}
+
+	if up {
+		_, err = tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`, mig.Version, mig.Name)
+	} else {
+		_, err = tx.ExecContext(ctx, `DELETE FROM schema_migrations WHERE version = $1`, mig.Version)
+	}
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// Up menjalankan semua migrasi yang belum diterapkan, berurutan dari versi terkecil.
+// Mengembalikan jumlah migrasi yang dijalankan.
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	count := 0
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		done, err := applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if err := checkDirty(done); err != nil {
+			return err
+		}
+
+		for _, mig := range m.migrations {
+			if _, ok := done[mig.Version]; ok {
+				continue
+			}
+			if err := run(ctx, conn, mig, mig.Up, true); err != nil {
+				return err
+			}
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+// Down membatalkan n migrasi terakhir yang sudah diterapkan, dari versi terbesar.
+func (m *Migrator) Down(ctx context.Context, n int) (int, error) {
+	count := 0
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		done, err := applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if err := checkDirty(done); err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && count < n; i-- {
+			mig := m.migrations[i]
+			if _, ok := done[mig.Version]; !ok {
+				continue
+			}
+			if mig.Down == "" {
+				return fmt.Errorf("migrasi %d_%s tidak punya file .down.sql", mig.Version, mig.Name)
+			}
+			if err := run(ctx, conn, mig, mig.Down, false); err != nil {
+				return err
+			}
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+// Status mengembalikan kondisi setiap migrasi yang dikenal binary ini
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	var result []Status
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		done, err := applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, mig := range m.migrations {
+			s, ok := done[mig.Version]
+			if !ok {
+				s = Status{Version: mig.Version, Name: mig.Name}
+			}
+			result = append(result, s)
+		}
+		return nil
+	})
+	return result, err
+}
+
+// Force menandai database berada tepat di versi tertentu tanpa menjalankan SQL apa pun:
+// semua versi <= version dianggap sudah diterapkan, sisanya dianggap belum.
+// Dipakai untuk membersihkan status dirty setelah perbaikan manual.
+func (m *Migrator) Force(ctx context.Context, version int64) error {
+	return m.withLock(ctx, func(conn *sql.Conn) error {
+		tx, err := conn.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM schema_migrations WHERE version > $1`, version); err != nil {
+			return err
+		}
+		for _, mig := range m.migrations {
+			if mig.Version > version {
+				break
+			}
+			if _, err := tx.ExecContext(ctx, `
+				INSERT INTO schema_migrations (version, name) VALUES ($1, $2)
+				ON CONFLICT (version) DO UPDATE SET dirty = FALSE
+			`, mig.Version, mig.Name); err != nil {
+				return err
+			}
+		}
+		return tx.Commit()
+	})
+}
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_pengamatan_varietas_kelas;
+DROP TABLE IF EXISTS DataPengamatanPadi;
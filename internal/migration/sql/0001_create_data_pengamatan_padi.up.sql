@@ -0,0 +1,13 @@
+-- Tabel utama hasil pengamatan morfologi varietas padi.
+-- IF NOT EXISTS agar environment lama yang tabelnya dibuat manual tetap bisa diadopsi.
+CREATE TABLE IF NOT EXISTS DataPengamatanPadi (
+    id_padi           SERIAL PRIMARY KEY,
+    varietas_kelas    VARCHAR(100)     NOT NULL,
+    warna             VARCHAR(50)      NOT NULL,
+    panjang_biji_mm   DOUBLE PRECISION NOT NULL,
+    tekstur_permukaan VARCHAR(50)      NOT NULL,
+    bentuk_ujung_daun VARCHAR(50)      NOT NULL,
+    waktu_pembuatan   TIMESTAMPTZ      NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_pengamatan_varietas_kelas ON DataPengamatanPadi (varietas_kelas);
@@ -0,0 +1,10 @@
+ALTER TABLE DataPengamatanPadi DROP CONSTRAINT IF EXISTS fk_pengamatan_warna;
+ALTER TABLE DataPengamatanPadi DROP CONSTRAINT IF EXISTS fk_pengamatan_tekstur_permukaan;
+ALTER TABLE DataPengamatanPadi DROP CONSTRAINT IF EXISTS fk_pengamatan_bentuk_ujung_daun;
+
+DROP TABLE IF EXISTS warna_alias;
+DROP TABLE IF EXISTS tekstur_permukaan_alias;
+DROP TABLE IF EXISTS bentuk_ujung_daun_alias;
+DROP TABLE IF EXISTS warna;
+DROP TABLE IF EXISTS tekstur_permukaan;
+DROP TABLE IF EXISTS bentuk_ujung_daun;
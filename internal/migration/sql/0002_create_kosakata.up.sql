@@ -0,0 +1,51 @@
+-- Kosakata terkontrol untuk field kategorikal: warna, tekstur_permukaan, bentuk_ujung_daun.
+-- Setiap kategori punya tabel nilai baku dan tabel alias ejaan (disimpan huruf kecil).
+DO $$
+DECLARE
+    kategori TEXT;
+BEGIN
+    FOREACH kategori IN ARRAY ARRAY['warna', 'tekstur_permukaan', 'bentuk_ujung_daun'] LOOP
+        EXECUTE format('CREATE TABLE %I (
+            id    SERIAL PRIMARY KEY,
+            nilai VARCHAR(50) NOT NULL UNIQUE
+        )', kategori);
+        EXECUTE format('CREATE UNIQUE INDEX %I ON %I (lower(nilai))', kategori || '_nilai_lower_idx', kategori);
+        EXECUTE format('CREATE TABLE %I (
+            alias       VARCHAR(50) PRIMARY KEY,
+            kosakata_id INT NOT NULL REFERENCES %I (id) ON DELETE CASCADE
+        )', kategori || '_alias', kategori);
+    END LOOP;
+END $$;
+
+-- Nilai baku awal (sama dengan domain.KosakataAwal)
+INSERT INTO warna (nilai) VALUES
+    ('Kuning'), ('Kuning Emas'), ('Putih'), ('Krem'), ('Coklat'), ('Merah'), ('Hitam'), ('Ungu');
+INSERT INTO tekstur_permukaan (nilai) VALUES ('Halus'), ('Kasar'), ('Berbulu');
+INSERT INTO bentuk_ujung_daun (nilai) VALUES ('Runcing'), ('Meruncing'), ('Tumpul'), ('Membulat');
+
+-- Adopsi data lama: nilai yang belum dikenal ditambahkan sebagai nilai baku baru,
+-- lalu semua data diseragamkan ke ejaan baku (misalnya "kuning" menjadi "Kuning")
+-- sebelum foreign key dipasang.
+DO $$
+DECLARE
+    kategori TEXT;
+BEGIN
+    FOREACH kategori IN ARRAY ARRAY['warna', 'tekstur_permukaan', 'bentuk_ujung_daun'] LOOP
+        EXECUTE format('UPDATE DataPengamatanPadi SET %1$I = ''Tidak Diketahui'' WHERE btrim(%1$I) = ''''', kategori);
+        EXECUTE format('
+            INSERT INTO %1$I (nilai)
+            SELECT DISTINCT ON (lower(btrim(p.%1$I))) initcap(btrim(p.%1$I))
+            FROM DataPengamatanPadi p
+            WHERE NOT EXISTS (SELECT 1 FROM %1$I k WHERE lower(k.nilai) = lower(btrim(p.%1$I)))
+        ', kategori);
+        EXECUTE format('
+            UPDATE DataPengamatanPadi p SET %1$I = k.nilai
+            FROM %1$I k
+            WHERE lower(k.nilai) = lower(btrim(p.%1$I)) AND p.%1$I <> k.nilai
+        ', kategori);
+        EXECUTE format('
+            ALTER TABLE DataPengamatanPadi ADD CONSTRAINT %I
+            FOREIGN KEY (%I) REFERENCES %I (nilai) ON UPDATE CASCADE ON DELETE RESTRICT
+        ', 'fk_pengamatan_' || kategori, kategori, kategori);
+    END LOOP;
+END $$;
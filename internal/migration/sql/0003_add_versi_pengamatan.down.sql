@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS trg_pengamatan_versi ON DataPengamatanPadi;
+DROP FUNCTION IF EXISTS naikkan_versi_pengamatan();
+ALTER TABLE DataPengamatanPadi DROP COLUMN IF EXISTS versi;
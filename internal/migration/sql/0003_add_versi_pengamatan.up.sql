@@ -0,0 +1,16 @@
+-- Nomor versi baris untuk optimistic concurrency (ETag / If-Match).
+-- Data lama mulai dari versi 1.
+ALTER TABLE DataPengamatanPadi ADD COLUMN IF NOT EXISTS versi INTEGER NOT NULL DEFAULT 1;
+
+-- Versi dinaikkan oleh trigger, bukan oleh aplikasi, agar perubahan lewat
+-- ON UPDATE CASCADE dari tabel kosakata juga mengubah ETag.
+CREATE OR REPLACE FUNCTION naikkan_versi_pengamatan() RETURNS trigger AS $$
+BEGIN
+    NEW.versi := OLD.versi + 1;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_pengamatan_versi
+    BEFORE UPDATE ON DataPengamatanPadi
+    FOR EACH ROW EXECUTE FUNCTION naikkan_versi_pengamatan();
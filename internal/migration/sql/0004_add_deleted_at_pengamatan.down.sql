@@ -0,0 +1,4 @@
+-- Baris yang masih di tempat sampah ikut terhapus permanen saat rollback
+DELETE FROM DataPengamatanPadi WHERE deleted_at IS NOT NULL;
+DROP INDEX IF EXISTS idx_pengamatan_deleted_at;
+ALTER TABLE DataPengamatanPadi DROP COLUMN IF EXISTS deleted_at;
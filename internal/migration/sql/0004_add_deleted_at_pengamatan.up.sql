@@ -0,0 +1,9 @@
+-- Soft delete: baris yang dihapus hanya ditandai waktu penghapusannya dan
+-- bisa dipulihkan sampai dibersihkan permanen setelah masa retensi.
+ALTER TABLE DataPengamatanPadi ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+-- Index parsial hanya berisi baris di tempat sampah (biasanya sedikit), untuk daftar
+-- tempat sampah dan pembersihan permanen (deleted_at < batas retensi). Query data aktif
+-- (deleted_at IS NULL) tetap memakai primary key atau index kolom lain.
+CREATE INDEX IF NOT EXISTS idx_pengamatan_deleted_at
+    ON DataPengamatanPadi (deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,16 @@
+-- Riwayat (audit trail) semua perubahan DataPengamatanPadi.
+-- Sengaja tanpa foreign key agar riwayat tetap ada setelah data dihapus permanen.
+CREATE TABLE IF NOT EXISTS riwayat_varietas (
+    id         BIGSERIAL PRIMARY KEY,
+    id_padi    INTEGER      NOT NULL,
+    versi      INTEGER      NOT NULL,
+    operasi    VARCHAR(20)  NOT NULL,
+    aktor      VARCHAR(100) NOT NULL,
+    keterangan TEXT         NOT NULL DEFAULT '',
+    waktu      TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    sebelum    JSONB,
+    sesudah    JSONB,
+    perubahan  JSONB        NOT NULL DEFAULT '{}'
+);
+
+CREATE INDEX IF NOT EXISTS idx_riwayat_varietas_id_padi ON riwayat_varietas (id_padi, id);
@@ -0,0 +1,2 @@
+ALTER TABLE DataPengamatanPadi DROP CONSTRAINT IF EXISTS chk_pengamatan_lebar_biji;
+ALTER TABLE DataPengamatanPadi DROP COLUMN IF EXISTS lebar_biji_mm;
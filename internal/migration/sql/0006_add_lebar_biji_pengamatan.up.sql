@@ -0,0 +1,6 @@
+-- Lebar biji (mm) untuk rasio panjang/lebar (bentuk biji). Opsional: data lama
+-- belum punya ukuran lebar sehingga dibiarkan NULL.
+ALTER TABLE DataPengamatanPadi ADD COLUMN IF NOT EXISTS lebar_biji_mm DOUBLE PRECISION;
+
+ALTER TABLE DataPengamatanPadi
+    ADD CONSTRAINT chk_pengamatan_lebar_biji CHECK (lebar_biji_mm IS NULL OR lebar_biji_mm > 0);
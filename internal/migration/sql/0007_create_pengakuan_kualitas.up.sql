@@ -0,0 +1,12 @@
+-- Pengakuan reviewer atas temuan pemeriksaan kualitas data (outlier, duplikat, nilai langka).
+-- Temuan sendiri dihitung ulang setiap kali diperiksa; yang disimpan hanya pengakuannya.
+-- Tanpa foreign key, sama seperti riwayat_varietas: pengakuan data yang sudah dihapus
+-- permanen tidak lagi cocok dengan temuan mana pun.
+CREATE TABLE IF NOT EXISTS pengakuan_kualitas (
+    kode       VARCHAR(100) PRIMARY KEY,
+    id_padi    INTEGER      NOT NULL,
+    versi_data INTEGER      NOT NULL,
+    catatan    TEXT         NOT NULL DEFAULT '',
+    aktor      VARCHAR(100) NOT NULL,
+    waktu      TIMESTAMPTZ  NOT NULL DEFAULT now()
+);
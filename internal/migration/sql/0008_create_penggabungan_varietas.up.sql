@@ -0,0 +1,10 @@
+-- Penunjuk dari ID data yang sudah digabung (merge) ke data utamanya, untuk redirect 301.
+-- Tanpa foreign key agar penunjuk tetap ada setelah data lama dihapus permanen.
+CREATE TABLE IF NOT EXISTS penggabungan_varietas (
+    id_lama INTEGER      PRIMARY KEY,
+    id_baru INTEGER      NOT NULL,
+    aktor   VARCHAR(100) NOT NULL,
+    waktu   TIMESTAMPTZ  NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_penggabungan_varietas_id_baru ON penggabungan_varietas (id_baru);
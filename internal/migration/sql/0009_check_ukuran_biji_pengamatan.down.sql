@@ -0,0 +1,5 @@
+ALTER TABLE DataPengamatanPadi DROP CONSTRAINT IF EXISTS chk_pengamatan_panjang_biji;
+
+ALTER TABLE DataPengamatanPadi DROP CONSTRAINT IF EXISTS chk_pengamatan_lebar_biji;
+ALTER TABLE DataPengamatanPadi
+    ADD CONSTRAINT chk_pengamatan_lebar_biji CHECK (lebar_biji_mm IS NULL OR lebar_biji_mm > 0);
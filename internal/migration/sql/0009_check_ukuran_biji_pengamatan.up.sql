@@ -0,0 +1,16 @@
+-- Ukuran biji harus angka terhingga di rentang validasi aplikasi (domain.MinPanjangBijiMM
+-- sampai domain.MaxPanjangBijiMM, domain.MinLebarBijiMM sampai domain.MaxLebarBijiMM).
+-- DOUBLE PRECISION menerima 'NaN' dan 'Infinity'; keduanya lolos CHECK lama (NaN > 0 di
+-- PostgreSQL) lalu membuat serialisasi JSON gagal. PostgreSQL mengurutkan NaN di atas semua
+-- angka, jadi BETWEEN menolak NaN maupun Infinity.
+-- NOT VALID: baris lama yang mungkin sudah di luar rentang tidak menggagalkan migrasi,
+-- tetapi semua INSERT/UPDATE baru diperiksa. Jalankan VALIDATE CONSTRAINT setelah
+-- baris lama diperbaiki.
+ALTER TABLE DataPengamatanPadi
+    ADD CONSTRAINT chk_pengamatan_panjang_biji
+    CHECK (panjang_biji_mm = panjang_biji_mm AND panjang_biji_mm BETWEEN 3 AND 15) NOT VALID;
+
+ALTER TABLE DataPengamatanPadi DROP CONSTRAINT IF EXISTS chk_pengamatan_lebar_biji;
+ALTER TABLE DataPengamatanPadi
+    ADD CONSTRAINT chk_pengamatan_lebar_biji
+    CHECK (lebar_biji_mm IS NULL OR (lebar_biji_mm = lebar_biji_mm AND lebar_biji_mm BETWEEN 1 AND 6)) NOT VALID;
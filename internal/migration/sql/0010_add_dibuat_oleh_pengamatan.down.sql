@@ -0,0 +1 @@
+ALTER TABLE DataPengamatanPadi DROP COLUMN IF EXISTS dibuat_oleh;
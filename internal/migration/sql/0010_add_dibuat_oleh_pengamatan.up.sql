@@ -0,0 +1,13 @@
+-- Pemilik data: subjek token (atau aktor) yang membuat baris. Dipakai hak akses "data
+-- sendiri" dan diperiksa pada baris yang sudah dikunci (SELECT ... FOR UPDATE), sehingga
+-- pemeriksaan dan perubahannya berada di transaksi yang sama.
+ALTER TABLE DataPengamatanPadi ADD COLUMN IF NOT EXISTS dibuat_oleh VARCHAR(100) NOT NULL DEFAULT '';
+
+-- Data lama diisi dari aktor revisi "create" di riwayat. Trigger versi dimatikan sementara
+-- agar pengisian ini tidak membuat ETag yang dipegang client menjadi usang.
+ALTER TABLE DataPengamatanPadi DISABLE TRIGGER trg_pengamatan_versi;
+UPDATE DataPengamatanPadi d
+SET dibuat_oleh = r.aktor
+FROM riwayat_varietas r
+WHERE r.id_padi = d.id_padi AND r.operasi = 'create' AND d.dibuat_oleh = '';
+ALTER TABLE DataPengamatanPadi ENABLE TRIGGER trg_pengamatan_versi;
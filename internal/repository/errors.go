@@ -0,0 +1,45 @@
+// internal/repository/errors.go
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
+)
+
+// translateError menerjemahkan error driver PostgreSQL menjadi error domain.
+// sql.ErrNoRows sengaja dibiarkan apa adanya karena itu bagian dari kontrak repository.
+func translateError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch {
+		case pgErr.Code == "40001", pgErr.Code == "40P01": // serialization_failure, deadlock_detected
+			return fmt.Errorf("%w: %s", domain.ErrKonflikTransaksi, pgErr.Message)
+		case pgErr.Code == "23505": // unique_violation
+			return fmt.Errorf("%w: %s", domain.ErrConflict, pgErr.Detail)
+		case pgErr.Code == "23503", pgErr.Code == "23514", pgErr.Code == "23502": // foreign_key, check, not_null
+			return domain.NewValidationError("data ditolak oleh database",
+				domain.FieldError{Field: pgErr.ColumnName, Message: pgErr.Message})
+		case strings.HasPrefix(pgErr.Code, "08"), strings.HasPrefix(pgErr.Code, "57P"): // connection_exception, admin shutdown
+			return fmt.Errorf("%w: %s", domain.ErrUnavailable, pgErr.Message)
+		}
+		return err
+	}
+
+	var connErr *pgconn.ConnectError
+	if errors.As(err, &connErr) || errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, context.DeadlineExceeded) || pgconn.Timeout(err) {
+		return fmt.Errorf("%w: %v", domain.ErrUnavailable, err)
+	}
+	return err
+}
@@ -0,0 +1,220 @@
+// internal/repository/kosakata_repository.go
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
+)
+
+// KosakataRepository mengimplementasikan domain.KosakataRepository di PostgreSQL.
+// Setiap kategori punya tabel <kategori> (nilai baku) dan <kategori>_alias.
+// Seperti repository lain, query memakai transaksi aktif di context jika ada.
+type KosakataRepository struct {
+	db *sql.DB
+}
+
+func NewKosakataRepository(db *sql.DB) *KosakataRepository {
+	return &KosakataRepository{db: db}
+}
+
+// tabel memastikan kategori ada di whitelist sebelum dipakai sebagai nama tabel
+func tabel(kategori string) (string, error) {
+	if !domain.ValidKategori(kategori) {
+		return "", fmt.Errorf("%w: kategori kosakata '%s' tidak dikenal", domain.ErrQueryTidakValid, kategori)
+	}
+	return kategori, nil
+}
+
+// isForeignKeyViolation mengecek error foreign_key_violation (23503)
+func isForeignKeyViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23503"
+}
+
+// splitAlias memecah hasil string_agg alias (dipisah baris baru) menjadi slice
+func splitAlias(raw string) []string {
+	if raw == "" {
+		return []string{}
+	}
+	return strings.Split(raw, "\n")
+}
+
+func (r *KosakataRepository) FindAll(ctx context.Context, kategori string) ([]domain.Kosakata, error) {
+	t, err := tabel(kategori)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT k.id, k.nilai, COALESCE(string_agg(a.alias, E'\n' ORDER BY a.alias), '')
+		FROM %[1]s k
+		LEFT JOIN %[1]s_alias a ON a.kosakata_id = k.id
+		GROUP BY k.id, k.nilai
+		ORDER BY k.nilai
+	`, t)
+
+	rows, err := dbFor(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	defer rows.Close()
+
+	result := []domain.Kosakata{}
+	for rows.Next() {
+		k := domain.Kosakata{Kategori: kategori}
+		var alias string
+		if err := rows.Scan(&k.ID, &k.Nilai, &alias); err != nil {
+			return nil, translateError(err)
+		}
+		k.Alias = splitAlias(alias)
+		result = append(result, k)
+	}
+	return result, translateError(rows.Err())
+}
+
+func (r *KosakataRepository) FindByID(ctx context.Context, kategori string, id int) (domain.Kosakata, error) {
+	t, err := tabel(kategori)
+	if err != nil {
+		return domain.Kosakata{}, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT k.id, k.nilai, COALESCE(string_agg(a.alias, E'\n' ORDER BY a.alias), '')
+		FROM %[1]s k
+		LEFT JOIN %[1]s_alias a ON a.kosakata_id = k.id
+		WHERE k.id = $1
+		GROUP BY k.id, k.nilai
+	`, t)
+
+	k := domain.Kosakata{Kategori: kategori}
+	var alias string
+	if err := dbFor(ctx, r.db).QueryRowContext(ctx, query, id).Scan(&k.ID, &k.Nilai, &alias); err != nil {
+		return domain.Kosakata{}, translateError(err)
+	}
+	k.Alias = splitAlias(alias)
+	return k, nil
+}
+
+func (r *KosakataRepository) Create(ctx context.Context, data domain.Kosakata) (domain.Kosakata, error) {
+	t, err := tabel(data.Kategori)
+	if err != nil {
+		return domain.Kosakata{}, err
+	}
+
+	query := fmt.Sprintf(`INSERT INTO %s (nilai) VALUES ($1) RETURNING id`, t)
+	if err := dbFor(ctx, r.db).QueryRowContext(ctx, query, data.Nilai).Scan(&data.ID); err != nil {
+		return domain.Kosakata{}, translateError(err)
+	}
+	data.Alias = []string{}
+	return data, nil
+}
+
+func (r *KosakataRepository) Update(ctx context.Context, data domain.Kosakata) (domain.Kosakata, error) {
+	t, err := tabel(data.Kategori)
+	if err != nil {
+		return domain.Kosakata{}, err
+	}
+
+	// Foreign key ON UPDATE CASCADE ikut mengganti ejaan di DataPengamatanPadi
+	query := fmt.Sprintf(`UPDATE %s SET nilai = $2 WHERE id = $1`, t)
+	res, err := dbFor(ctx, r.db).ExecContext(ctx, query, data.ID, data.Nilai)
+	if err != nil {
+		return domain.Kosakata{}, translateError(err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return domain.Kosakata{}, translateError(err)
+	} else if n == 0 {
+		return domain.Kosakata{}, sql.ErrNoRows
+	}
+	return r.FindByID(ctx, data.Kategori, data.ID)
+}
+
+func (r *KosakataRepository) Delete(ctx context.Context, kategori string, id int) error {
+	t, err := tabel(kategori)
+	if err != nil {
+		return err
+	}
+
+	res, err := dbFor(ctx, r.db).ExecContext(ctx, fmt.Sprintf(`DELETE FROM %s WHERE id = $1`, t), id)
+	if err != nil {
+		// ON DELETE RESTRICT: nilai yang masih dipakai data pengamatan tidak boleh dihapus
+		if isForeignKeyViolation(err) {
+			return fmt.Errorf("%w: nilai %s masih dipakai data pengamatan", domain.ErrConflict, kategori)
+		}
+		return translateError(err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return translateError(err)
+	} else if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *KosakataRepository) AddAlias(ctx context.Context, kategori string, id int, alias string) (domain.Kosakata, error) {
+	t, err := tabel(kategori)
+	if err != nil {
+		return domain.Kosakata{}, err
+	}
+
+	query := fmt.Sprintf(`INSERT INTO %s_alias (alias, kosakata_id) VALUES (lower($1), $2)`, t)
+	if _, err := dbFor(ctx, r.db).ExecContext(ctx, query, alias, id); err != nil {
+		// kosakata_id tidak ada berarti nilai bakunya tidak ditemukan
+		if isForeignKeyViolation(err) {
+			return domain.Kosakata{}, sql.ErrNoRows
+		}
+		return domain.Kosakata{}, translateError(err)
+	}
+	return r.FindByID(ctx, kategori, id)
+}
+
+func (r *KosakataRepository) RemoveAlias(ctx context.Context, kategori string, id int, alias string) error {
+	t, err := tabel(kategori)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`DELETE FROM %s_alias WHERE alias = lower($1) AND kosakata_id = $2`, t)
+	res, err := dbFor(ctx, r.db).ExecContext(ctx, query, alias, id)
+	if err != nil {
+		return translateError(err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return translateError(err)
+	} else if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *KosakataRepository) Resolve(ctx context.Context, kategori string, value string) (string, error) {
+	t, err := tabel(kategori)
+	if err != nil {
+		return "", err
+	}
+
+	// Cocokkan dulu ke nilai baku, lalu ke alias (join ke tabel nilai baku)
+	query := fmt.Sprintf(`
+		SELECT nilai FROM (
+			SELECT k.nilai, 1 AS prioritas FROM %[1]s k WHERE lower(k.nilai) = lower($1)
+			UNION ALL
+			SELECT k.nilai, 2 AS prioritas FROM %[1]s_alias a JOIN %[1]s k ON k.id = a.kosakata_id
+			WHERE a.alias = lower($1)
+		) cocok
+		ORDER BY prioritas
+		LIMIT 1
+	`, t)
+
+	var nilai string
+	if err := dbFor(ctx, r.db).QueryRowContext(ctx, query, value).Scan(&nilai); err != nil {
+		return "", translateError(err)
+	}
+	return nilai, nil
+}
@@ -0,0 +1,73 @@
+// internal/repository/kualitas_repository.go
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
+)
+
+// KualitasRepository mengimplementasikan domain.KualitasRepository di tabel pengakuan_kualitas
+type KualitasRepository struct {
+	db *sql.DB
+}
+
+func NewKualitasRepository(db *sql.DB) *KualitasRepository {
+	return &KualitasRepository{db: db}
+}
+
+// kolomPengakuan adalah daftar kolom yang dibaca ke domain.PengakuanKualitas (urutan sama dengan scanPengakuan)
+const kolomPengakuan = `kode, id_padi, versi_data, catatan, aktor, waktu`
+
+func scanPengakuan(row rowScanner) (domain.PengakuanKualitas, error) {
+	var p domain.PengakuanKualitas
+	err := row.Scan(&p.Kode, &p.IDPadi, &p.VersiData, &p.Catatan, &p.Aktor, &p.Waktu)
+	return p, err
+}
+
+func (r *KualitasRepository) FindAll(ctx context.Context) ([]domain.PengakuanKualitas, error) {
+	rows, err := dbFor(ctx, r.db).QueryContext(ctx, `SELECT `+kolomPengakuan+` FROM pengakuan_kualitas ORDER BY kode`)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	defer rows.Close()
+
+	result := []domain.PengakuanKualitas{}
+	for rows.Next() {
+		p, err := scanPengakuan(rows)
+		if err != nil {
+			return nil, translateError(err)
+		}
+		result = append(result, p)
+	}
+	return result, translateError(rows.Err())
+}
+
+// Save menulis pengakuan dengan upsert; waktu diisi ulang oleh database
+func (r *KualitasRepository) Save(ctx context.Context, p domain.PengakuanKualitas) (domain.PengakuanKualitas, error) {
+	saved, err := scanPengakuan(dbFor(ctx, r.db).QueryRowContext(ctx, `
+        INSERT INTO pengakuan_kualitas (kode, id_padi, versi_data, catatan, aktor)
+        VALUES ($1, $2, $3, $4, $5)
+        ON CONFLICT (kode) DO UPDATE
+        SET id_padi = EXCLUDED.id_padi, versi_data = EXCLUDED.versi_data,
+            catatan = EXCLUDED.catatan, aktor = EXCLUDED.aktor, waktu = now()
+        RETURNING `+kolomPengakuan, p.Kode, p.IDPadi, p.VersiData, p.Catatan, p.Aktor))
+	if err != nil {
+		return domain.PengakuanKualitas{}, translateError(err)
+	}
+	return saved, nil
+}
+
+func (r *KualitasRepository) Delete(ctx context.Context, kode string) error {
+	res, err := dbFor(ctx, r.db).ExecContext(ctx, `DELETE FROM pengakuan_kualitas WHERE kode = $1`, kode)
+	if err != nil {
+		return translateError(err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return translateError(err)
+	} else if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,254 @@
+// internal/repository/memory_kosakata_repository.go
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
+)
+
+// MemoryKosakataRepository adalah implementasi in-memory dari domain.KosakataRepository.
+// Perilaku foreign key PostgreSQL (RESTRICT saat hapus, CASCADE saat ganti ejaan)
+// ditiru dengan memeriksa dan memperbarui MemoryVarietasRepository.
+type MemoryKosakataRepository struct {
+	mu       sync.RWMutex
+	data     map[string]map[int]domain.Kosakata // kategori -> id -> kosakata
+	nextID   int
+	varietas *MemoryVarietasRepository
+}
+
+// NewMemoryKosakataRepository membuat repository kosakata berisi domain.KosakataAwal
+func NewMemoryKosakataRepository(varietas *MemoryVarietasRepository) *MemoryKosakataRepository {
+	r := &MemoryKosakataRepository{data: map[string]map[int]domain.Kosakata{}, nextID: 1, varietas: varietas}
+	for _, kategori := range domain.KategoriKosakata {
+		r.data[kategori] = map[int]domain.Kosakata{}
+		for _, nilai := range domain.KosakataAwal[kategori] {
+			r.data[kategori][r.nextID] = domain.Kosakata{ID: r.nextID, Kategori: kategori, Nilai: nilai, Alias: []string{}}
+			r.nextID++
+		}
+	}
+	return r
+}
+
+// kategoriData mengembalikan map kosakata untuk kategori yang valid
+func (r *MemoryKosakataRepository) kategoriData(kategori string) (map[int]domain.Kosakata, error) {
+	if _, err := tabel(kategori); err != nil {
+		return nil, err
+	}
+	return r.data[kategori], nil
+}
+
+// salin mengembalikan salinan kosakata agar slice Alias tidak ikut termodifikasi pemanggil
+func salin(k domain.Kosakata) domain.Kosakata {
+	k.Alias = append([]string{}, k.Alias...)
+	return k
+}
+
+// bentrok mengecek apakah nilai (tidak peka huruf besar/kecil) sudah dipakai kosakata lain
+func bentrok(items map[int]domain.Kosakata, nilai string, exceptID int) bool {
+	for id, k := range items {
+		if id != exceptID && strings.EqualFold(k.Nilai, nilai) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *MemoryKosakataRepository) FindAll(ctx context.Context, kategori string) ([]domain.Kosakata, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	items, err := r.kategoriData(kategori)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.Kosakata, 0, len(items))
+	for _, k := range items {
+		result = append(result, salin(k))
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Nilai < result[j].Nilai })
+	return result, nil
+}
+
+func (r *MemoryKosakataRepository) FindByID(ctx context.Context, kategori string, id int) (domain.Kosakata, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	items, err := r.kategoriData(kategori)
+	if err != nil {
+		return domain.Kosakata{}, err
+	}
+	k, ok := items[id]
+	if !ok {
+		return domain.Kosakata{}, sql.ErrNoRows
+	}
+	return salin(k), nil
+}
+
+func (r *MemoryKosakataRepository) Create(ctx context.Context, data domain.Kosakata) (domain.Kosakata, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	items, err := r.kategoriData(data.Kategori)
+	if err != nil {
+		return domain.Kosakata{}, err
+	}
+	if bentrok(items, data.Nilai, 0) {
+		return domain.Kosakata{}, fmt.Errorf("%w: nilai '%s' sudah ada", domain.ErrConflict, data.Nilai)
+	}
+
+	data.ID = r.nextID
+	data.Alias = []string{}
+	r.nextID++
+	r.catatLama(ctx, data.Kategori, data.ID)
+	items[data.ID] = data
+	return salin(data), nil
+}
+
+func (r *MemoryKosakataRepository) Update(ctx context.Context, data domain.Kosakata) (domain.Kosakata, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	items, err := r.kategoriData(data.Kategori)
+	if err != nil {
+		return domain.Kosakata{}, err
+	}
+	existing, ok := items[data.ID]
+	if !ok {
+		return domain.Kosakata{}, sql.ErrNoRows
+	}
+	if bentrok(items, data.Nilai, data.ID) {
+		return domain.Kosakata{}, fmt.Errorf("%w: nilai '%s' sudah ada", domain.ErrConflict, data.Nilai)
+	}
+
+	// Sama seperti ON UPDATE CASCADE
+	r.varietas.renameKategori(ctx, data.Kategori, existing.Nilai, data.Nilai)
+	r.catatLama(ctx, data.Kategori, data.ID)
+	existing.Nilai = data.Nilai
+	items[data.ID] = existing
+	return salin(existing), nil
+}
+
+func (r *MemoryKosakataRepository) Delete(ctx context.Context, kategori string, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	items, err := r.kategoriData(kategori)
+	if err != nil {
+		return err
+	}
+	k, ok := items[id]
+	if !ok {
+		return sql.ErrNoRows
+	}
+
+	// Sama seperti ON DELETE RESTRICT
+	if r.varietas.usesKategori(kategori, k.Nilai) {
+		return fmt.Errorf("%w: nilai %s masih dipakai data pengamatan", domain.ErrConflict, kategori)
+	}
+	r.catatLama(ctx, kategori, id)
+	delete(items, id)
+	return nil
+}
+
+func (r *MemoryKosakataRepository) AddAlias(ctx context.Context, kategori string, id int, alias string) (domain.Kosakata, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	items, err := r.kategoriData(kategori)
+	if err != nil {
+		return domain.Kosakata{}, err
+	}
+	k, ok := items[id]
+	if !ok {
+		return domain.Kosakata{}, sql.ErrNoRows
+	}
+
+	alias = strings.ToLower(alias)
+	for _, other := range items {
+		for _, a := range other.Alias {
+			if a == alias {
+				return domain.Kosakata{}, fmt.Errorf("%w: alias '%s' sudah ada", domain.ErrConflict, alias)
+			}
+		}
+	}
+
+	k.Alias = append(append([]string{}, k.Alias...), alias)
+	sort.Strings(k.Alias)
+	r.catatLama(ctx, kategori, id)
+	items[id] = k
+	return salin(k), nil
+}
+
+func (r *MemoryKosakataRepository) RemoveAlias(ctx context.Context, kategori string, id int, alias string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	items, err := r.kategoriData(kategori)
+	if err != nil {
+		return err
+	}
+	k, ok := items[id]
+	if !ok {
+		return sql.ErrNoRows
+	}
+
+	alias = strings.ToLower(alias)
+	for i, a := range k.Alias {
+		if a == alias {
+			k.Alias = append(append([]string{}, k.Alias[:i]...), k.Alias[i+1:]...)
+			r.catatLama(ctx, kategori, id)
+			items[id] = k
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
+// catatLama mencatat nilai kosakata id sebelum diubah di undo log transaksi (lihat
+// MemoryTxManager), sama seperti MemoryVarietasRepository.catatLama. Alias tidak perlu
+// disalin karena setiap perubahan membuat slice baru. Pemanggil harus memegang lock tulis.
+func (r *MemoryKosakataRepository) catatLama(ctx context.Context, kategori string, id int) {
+	lama, ada := r.data[kategori][id]
+	catatUndo(ctx, func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if ada {
+			r.data[kategori][id] = lama
+		} else {
+			delete(r.data[kategori], id)
+		}
+	})
+}
+
+func (r *MemoryKosakataRepository) Resolve(ctx context.Context, kategori string, value string) (string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	items, err := r.kategoriData(kategori)
+	if err != nil {
+		return "", err
+	}
+
+	// Nilai baku diprioritaskan di atas alias, sama seperti versi PostgreSQL
+	for _, k := range items {
+		if strings.EqualFold(k.Nilai, value) {
+			return k.Nilai, nil
+		}
+	}
+	lower := strings.ToLower(value)
+	for _, k := range items {
+		for _, a := range k.Alias {
+			if a == lower {
+				return k.Nilai, nil
+			}
+		}
+	}
+	return "", sql.ErrNoRows
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
+)
+
+// cariKosakata mengembalikan kosakata warna dengan nilai tertentu
+func cariKosakata(t *testing.T, repo *MemoryKosakataRepository, nilai string) domain.Kosakata {
+	t.Helper()
+	items, err := repo.FindAll(context.Background(), domain.KategoriWarna)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, k := range items {
+		if k.Nilai == nilai {
+			return k
+		}
+	}
+	t.Fatalf("kosakata warna %q tidak ada", nilai)
+	return domain.Kosakata{}
+}
+
+func TestKosakataRollback(t *testing.T) {
+	varietas, riwayat := repoUji(t) // semua data berwarna Putih
+	repo := NewMemoryKosakataRepository(varietas)
+	tx := NewMemoryTxManager()
+	ctx := context.Background()
+
+	putih, merah := cariKosakata(t, repo, "Putih"), cariKosakata(t, repo, "Merah")
+	if _, err := repo.AddAlias(ctx, domain.KategoriWarna, merah.ID, "abang"); err != nil {
+		t.Fatal(err)
+	}
+	awalVarietas := ambilKeadaan(t, varietas, riwayat)
+	awalKosakata, err := repo.FindAll(ctx, domain.KategoriWarna)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = tx.WithinTx(ctx, func(ctx context.Context) error {
+		// Ganti ejaan ikut mengubah semua data yang memakainya (ON UPDATE CASCADE)
+		if _, err := repo.Update(ctx, domain.Kosakata{ID: putih.ID, Kategori: domain.KategoriWarna, Nilai: "Putih Susu"}); err != nil {
+			t.Fatal(err)
+		}
+		if v, _ := varietas.FindByID(ctx, 1); v.Warna != "Putih Susu" || v.Versi != 2 {
+			t.Errorf("data id 1 di dalam transaksi = %+v, ingin Putih Susu versi 2", v)
+		}
+		if _, err := repo.Create(ctx, domain.Kosakata{Kategori: domain.KategoriWarna, Nilai: "Jingga"}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := repo.AddAlias(ctx, domain.KategoriWarna, merah.ID, "red"); err != nil {
+			t.Fatal(err)
+		}
+		if err := repo.RemoveAlias(ctx, domain.KategoriWarna, merah.ID, "abang"); err != nil {
+			t.Fatal(err)
+		}
+		if err := repo.Delete(ctx, domain.KategoriWarna, cariKosakata(t, repo, "Ungu").ID); err != nil {
+			t.Fatal(err)
+		}
+		return errUji
+	})
+	if !errors.Is(err, errUji) {
+		t.Fatalf("WithinTx = %v, ingin errUji", err)
+	}
+
+	got, err := repo.FindAll(ctx, domain.KategoriWarna)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, awalKosakata) {
+		t.Errorf("kosakata setelah rollback = %+v\ningin %+v", got, awalKosakata)
+	}
+	periksaKeadaan(t, ambilKeadaan(t, varietas, riwayat), awalVarietas)
+	if nilai, err := repo.Resolve(ctx, domain.KategoriWarna, "putih"); err != nil || nilai != "Putih" {
+		t.Errorf("Resolve(putih) = %q, %v, ingin Putih", nilai, err)
+	}
+}
+
+func TestKosakataGantiEjaanTanpaTransaksi(t *testing.T) {
+	varietas, _ := repoUji(t)
+	repo := NewMemoryKosakataRepository(varietas)
+	ctx := context.Background()
+
+	putih := cariKosakata(t, repo, "Putih")
+	if _, err := repo.Update(ctx, domain.Kosakata{ID: putih.ID, Kategori: domain.KategoriWarna, Nilai: "Putih Susu"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []int{1, 2} {
+		if v, err := varietas.FindByID(ctx, id); err != nil || v.Warna != "Putih Susu" || v.Versi != 2 {
+			t.Errorf("data id %d = %+v, %v, ingin Putih Susu versi 2", id, v, err)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+// internal/repository/memory_kualitas_repository.go
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
+)
+
+// MemoryKualitasRepository adalah implementasi domain.KualitasRepository di memori
+type MemoryKualitasRepository struct {
+	mu        sync.RWMutex
+	pengakuan map[string]domain.PengakuanKualitas
+	now       func() time.Time
+}
+
+// NewMemoryKualitasRepository membuat repository pengakuan kualitas in-memory yang kosong
+func NewMemoryKualitasRepository() *MemoryKualitasRepository {
+	return &MemoryKualitasRepository{pengakuan: map[string]domain.PengakuanKualitas{}, now: time.Now}
+}
+
+func (r *MemoryKualitasRepository) FindAll(ctx context.Context) ([]domain.PengakuanKualitas, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]domain.PengakuanKualitas, 0, len(r.pengakuan))
+	for _, p := range r.pengakuan {
+		result = append(result, p)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Kode < result[j].Kode })
+	return result, nil
+}
+
+func (r *MemoryKualitasRepository) Save(ctx context.Context, p domain.PengakuanKualitas) (domain.PengakuanKualitas, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	p.Waktu = r.now()
+	r.pengakuan[p.Kode] = p
+	return p, nil
+}
+
+func (r *MemoryKualitasRepository) Delete(ctx context.Context, kode string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.pengakuan[kode]; !ok {
+		return sql.ErrNoRows
+	}
+	delete(r.pengakuan, kode)
+	return nil
+}
@@ -0,0 +1,70 @@
+// internal/repository/memory_penggabungan_repository.go
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
+)
+
+// MemoryPenggabunganRepository adalah implementasi domain.PenggabunganRepository di memori
+type MemoryPenggabunganRepository struct {
+	mu   sync.RWMutex
+	data map[int]domain.Penggabungan // id_lama -> penggabungan
+	now  func() time.Time
+}
+
+// NewMemoryPenggabunganRepository membuat repository penggabungan in-memory yang kosong
+func NewMemoryPenggabunganRepository() *MemoryPenggabunganRepository {
+	return &MemoryPenggabunganRepository{data: map[int]domain.Penggabungan{}, now: time.Now}
+}
+
+func (r *MemoryPenggabunganRepository) Save(ctx context.Context, p ...domain.Penggabungan) ([]domain.Penggabungan, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	saved := make([]domain.Penggabungan, 0, len(p))
+	for _, g := range p {
+		for id, lama := range r.data {
+			if lama.IDBaru == g.IDLama {
+				r.catatLama(ctx, id)
+				lama.IDBaru = g.IDBaru
+				r.data[id] = lama
+			}
+		}
+		g.Waktu = r.now()
+		r.catatLama(ctx, g.IDLama)
+		r.data[g.IDLama] = g
+		saved = append(saved, g)
+	}
+	return saved, nil
+}
+
+func (r *MemoryPenggabunganRepository) FindByID(ctx context.Context, id int) (domain.Penggabungan, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	g, ok := r.data[id]
+	if !ok {
+		return domain.Penggabungan{}, sql.ErrNoRows
+	}
+	return g, nil
+}
+
+// catatLama mencatat penggabungan id sebelum diubah di undo log transaksi (lihat
+// MemoryTxManager). Pemanggil harus memegang lock tulis.
+func (r *MemoryPenggabunganRepository) catatLama(ctx context.Context, id int) {
+	lama, ada := r.data[id]
+	catatUndo(ctx, func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if ada {
+			r.data[id] = lama
+		} else {
+			delete(r.data, id)
+		}
+	})
+}
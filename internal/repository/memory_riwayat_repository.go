@@ -0,0 +1,83 @@
+// internal/repository/memory_riwayat_repository.go
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
+)
+
+// MemoryRiwayatRepository adalah implementasi domain.RiwayatRepository di memori,
+// pasangan MemoryVarietasRepository. ID revisi berurutan mulai dari 1.
+type MemoryRiwayatRepository struct {
+	mu      sync.RWMutex
+	riwayat []domain.Revisi
+	now     func() time.Time
+}
+
+// NewMemoryRiwayatRepository membuat repository riwayat in-memory yang kosong
+func NewMemoryRiwayatRepository() *MemoryRiwayatRepository {
+	return &MemoryRiwayatRepository{now: time.Now}
+}
+
+// Catat menambahkan revisi ke riwayat. Snapshot disalin agar perubahan data berikutnya
+// tidak mengubah isi riwayat.
+func (r *MemoryRiwayatRepository) Catat(ctx context.Context, revisi ...domain.Revisi) error {
+	salin := func(v *domain.VarietasPadi) *domain.VarietasPadi {
+		if v == nil {
+			return nil
+		}
+		c := *v
+		return &c
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, rev := range revisi {
+		rev.ID = int64(len(r.riwayat) + 1)
+		rev.Waktu = r.now()
+		rev.Sebelum, rev.Sesudah = salin(rev.Sebelum), salin(rev.Sesudah)
+		r.riwayat = append(r.riwayat, rev)
+		r.catatBatal(ctx, rev.ID)
+	}
+	return nil
+}
+
+func (r *MemoryRiwayatRepository) History(ctx context.Context, id int) ([]domain.Revisi, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := []domain.Revisi{}
+	for _, rev := range r.riwayat {
+		if rev.IDPadi == id {
+			result = append(result, rev)
+		}
+	}
+	return result, nil
+}
+
+func (r *MemoryRiwayatRepository) FindRevision(ctx context.Context, id int, revisiID int64) (domain.Revisi, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if revisiID < 1 || revisiID > int64(len(r.riwayat)) || r.riwayat[revisiID-1].IDPadi != id {
+		return domain.Revisi{}, sql.ErrNoRows
+	}
+	return r.riwayat[revisiID-1], nil
+}
+
+// catatBatal mencatat di undo log transaksi (lihat MemoryTxManager) bahwa revisi id dibuang
+// saat rollback. Posisinya dikosongkan, bukan dihapus, agar ID revisi lain tetap sama dengan
+// indeksnya; ID yang terlewat sama seperti sequence PostgreSQL setelah rollback.
+// Pemanggil harus memegang lock tulis.
+func (r *MemoryRiwayatRepository) catatBatal(ctx context.Context, id int64) {
+	catatUndo(ctx, func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.riwayat[id-1] = domain.Revisi{ID: id}
+	})
+}
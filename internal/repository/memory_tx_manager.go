@@ -0,0 +1,91 @@
+// internal/repository/memory_tx_manager.go
+package repository
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryTxManager mengimplementasikan domain.TxManager untuk repository in-memory.
+// Transaksi berjalan satu per satu (setara isolasi serializable, jadi tidak pernah
+// perlu diulang). Setiap perubahan repository in-memory di dalam transaksi dicatat di
+// undo log, dan rollback hanya membatalkan perubahan itu dengan urutan terbalik, sehingga
+// perubahan lain di luar transaksi yang terjadi bersamaan tidak ikut hilang.
+type MemoryTxManager struct {
+	mu sync.Mutex
+}
+
+// NewMemoryTxManager membuat TxManager untuk repository in-memory
+func NewMemoryTxManager() *MemoryTxManager {
+	return &MemoryTxManager{}
+}
+
+type memoryTxKey struct{}
+
+// undoLog berisi langkah pembatalan setiap perubahan di satu transaksi, sesuai urutannya
+type undoLog struct {
+	mu      sync.Mutex
+	langkah []func()
+}
+
+// catatUndo menambahkan langkah pembatalan ke undo log transaksi di ctx. Di luar
+// transaksi perubahan langsung permanen, jadi tidak ada yang dicatat. fn dijalankan
+// tanpa lock apa pun sehingga harus mengambil lock repository-nya sendiri.
+func catatUndo(ctx context.Context, fn func()) {
+	log, ok := ctx.Value(memoryTxKey{}).(*undoLog)
+	if !ok {
+		return
+	}
+	log.mu.Lock()
+	log.langkah = append(log.langkah, fn)
+	log.mu.Unlock()
+}
+
+// titik mengembalikan posisi undo log saat ini (untuk savepoint)
+func (u *undoLog) titik() int {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return len(u.langkah)
+}
+
+// batalkan menjalankan langkah pembatalan setelah posisi n dari yang terakhir, lalu membuangnya
+func (u *undoLog) batalkan(n int) {
+	u.mu.Lock()
+	langkah := u.langkah[n:]
+	u.langkah = u.langkah[:n]
+	u.mu.Unlock()
+
+	for i := len(langkah) - 1; i >= 0; i-- {
+		langkah[i]()
+	}
+}
+
+func (m *MemoryTxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if ctx.Value(memoryTxKey{}) != nil {
+		return fn(ctx)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	log := &undoLog{}
+	return jalankanMemori(context.WithValue(ctx, memoryTxKey{}, log), log, fn)
+}
+
+func (m *MemoryTxManager) Savepoint(ctx context.Context, fn func(ctx context.Context) error) error {
+	log, ok := ctx.Value(memoryTxKey{}).(*undoLog)
+	if !ok {
+		return m.WithinTx(ctx, fn)
+	}
+	return jalankanMemori(ctx, log, fn)
+}
+
+// jalankanMemori menjalankan fn dan membatalkan perubahan yang dibuatnya jika fn gagal
+func jalankanMemori(ctx context.Context, log *undoLog, fn func(ctx context.Context) error) error {
+	awal := log.titik()
+	if err := fn(ctx); err != nil {
+		log.batalkan(awal)
+		return err
+	}
+	return nil
+}
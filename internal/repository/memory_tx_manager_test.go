@@ -0,0 +1,266 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
+)
+
+var errUji = errors.New("gagal sengaja")
+
+// repoUji membuat repository varietas dan riwayat in-memory dengan empat data: id 1 dan 2
+// aktif, id 3 dan 4 di tempat sampah
+func repoUji(t *testing.T) (*MemoryVarietasRepository, *MemoryRiwayatRepository) {
+	t.Helper()
+	ctx := context.Background()
+	repo, riwayat := NewMemoryVarietasRepository(), NewMemoryRiwayatRepository()
+	for i := range 4 {
+		v, err := repo.Create(ctx, domain.VarietasPadi{VarietasKelas: "IR64", Warna: "Putih", PanjangBijiMM: 6 + float64(i)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := riwayat.Catat(ctx, domain.Revisi{IDPadi: v.ID, Versi: v.Versi, Operasi: domain.OperasiCreate, Sesudah: &v}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, id := range []int{3, 4} {
+		if _, err := repo.Delete(ctx, id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return repo, riwayat
+}
+
+// keadaan adalah salinan isi kedua repository untuk dibandingkan setelah rollback
+type keadaan struct {
+	data    map[int]domain.VarietasPadi
+	riwayat map[int][]domain.Revisi
+}
+
+func ambilKeadaan(t *testing.T, repo *MemoryVarietasRepository, riwayat *MemoryRiwayatRepository) keadaan {
+	t.Helper()
+	repo.mu.RLock()
+	k := keadaan{data: maps.Clone(repo.data), riwayat: map[int][]domain.Revisi{}}
+	repo.mu.RUnlock()
+	for id := 1; id <= 6; id++ {
+		h, err := riwayat.History(context.Background(), id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		k.riwayat[id] = h
+	}
+	return k
+}
+
+func periksaKeadaan(t *testing.T, got, want keadaan) {
+	t.Helper()
+	for id := 1; id <= 6; id++ {
+		g, gAda := got.data[id]
+		w, wAda := want.data[id]
+		if gAda != wAda || !reflect.DeepEqual(g, w) {
+			t.Errorf("data id %d = %+v (ada %v), ingin %+v (ada %v)", id, g, gAda, w, wAda)
+		}
+		if !reflect.DeepEqual(got.riwayat[id], want.riwayat[id]) {
+			t.Errorf("riwayat id %d berisi %d revisi, ingin %d", id, len(got.riwayat[id]), len(want.riwayat[id]))
+		}
+	}
+}
+
+// ubahSemua menjalankan setiap jenis perubahan repository in-memory
+func ubahSemua(ctx context.Context, t *testing.T, repo *MemoryVarietasRepository, riwayat *MemoryRiwayatRepository) {
+	t.Helper()
+	baru, err := repo.Create(ctx, domain.VarietasPadi{VarietasKelas: "Ciherang"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ubah, err := repo.Update(ctx, domain.VarietasPadi{ID: 1, VarietasKelas: "Inpari", Warna: "Coklat"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	warna := "Merah"
+	if _, err := repo.Patch(ctx, 1, domain.VarietasPatch{Warna: &warna}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.Delete(ctx, 2); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.Restore(ctx, 3); err != nil {
+		t.Fatal(err)
+	}
+	if purged, err := repo.Purge(ctx, time.Now().Add(time.Hour)); err != nil || len(purged) != 2 {
+		t.Fatalf("Purge = %d data, %v, ingin 2 (id 2 dan 4)", len(purged), err)
+	}
+	err = riwayat.Catat(ctx,
+		domain.Revisi{IDPadi: baru.ID, Versi: baru.Versi, Operasi: domain.OperasiCreate, Sesudah: &baru},
+		domain.Revisi{IDPadi: 1, Versi: ubah.Versi, Operasi: domain.OperasiUpdate, Sesudah: &ubah})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWithinTxRollback(t *testing.T) {
+	repo, riwayat := repoUji(t)
+	tx := NewMemoryTxManager()
+	awal := ambilKeadaan(t, repo, riwayat)
+
+	err := tx.WithinTx(context.Background(), func(ctx context.Context) error {
+		ubahSemua(ctx, t, repo, riwayat)
+		return errUji
+	})
+	if !errors.Is(err, errUji) {
+		t.Fatalf("WithinTx = %v, ingin errUji", err)
+	}
+	// Baris, versi, DeletedAt, data yang di-purge, dan riwayat kembali seperti semula
+	periksaKeadaan(t, ambilKeadaan(t, repo, riwayat), awal)
+
+	// nextID tidak dipulihkan (seperti sequence PostgreSQL), jadi ID 5 tidak dipakai ulang
+	v, err := repo.Create(context.Background(), domain.VarietasPadi{})
+	if err != nil || v.ID != 6 {
+		t.Errorf("Create setelah rollback = id %d, %v, ingin id 6", v.ID, err)
+	}
+}
+
+func TestWithinTxCommit(t *testing.T) {
+	repo, riwayat := repoUji(t)
+	tx := NewMemoryTxManager()
+
+	err := tx.WithinTx(context.Background(), func(ctx context.Context) error {
+		ubahSemua(ctx, t, repo, riwayat)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	k := ambilKeadaan(t, repo, riwayat)
+	if v := k.data[1]; v.Warna != "Merah" || v.Versi != 3 {
+		t.Errorf("data id 1 = %+v, ingin Merah versi 3", v)
+	}
+	if v := k.data[3]; v.DeletedAt != nil || v.Versi != 3 {
+		t.Errorf("data id 3 = %+v, ingin dipulihkan dengan versi 3", v)
+	}
+	if _, ada := k.data[2]; ada {
+		t.Error("data id 2 seharusnya sudah di-purge")
+	}
+	if len(k.riwayat[5]) != 1 || len(k.riwayat[1]) != 2 {
+		t.Errorf("riwayat id 5 dan 1 berisi %d dan %d revisi, ingin 1 dan 2", len(k.riwayat[5]), len(k.riwayat[1]))
+	}
+}
+
+func TestSavepointBersarang(t *testing.T) {
+	ubahWarna := func(ctx context.Context, t *testing.T, repo *MemoryVarietasRepository, id int, warna string) {
+		t.Helper()
+		if _, err := repo.Patch(ctx, id, domain.VarietasPatch{Warna: &warna}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("savepoint gagal hanya membatalkan perubahannya sendiri", func(t *testing.T) {
+		repo, riwayat := repoUji(t)
+		tx := NewMemoryTxManager()
+		awal := ambilKeadaan(t, repo, riwayat)
+
+		err := tx.WithinTx(context.Background(), func(ctx context.Context) error {
+			ubahWarna(ctx, t, repo, 1, "Coklat")
+			err := tx.Savepoint(ctx, func(ctx context.Context) error {
+				ubahWarna(ctx, t, repo, 2, "Hitam")
+				if err := tx.Savepoint(ctx, func(ctx context.Context) error {
+					ubahWarna(ctx, t, repo, 1, "Merah")
+					return nil
+				}); err != nil {
+					return err
+				}
+				// Savepoint paling dalam yang gagal tidak membatalkan savepoint induknya
+				if err := tx.Savepoint(ctx, func(ctx context.Context) error {
+					ubahWarna(ctx, t, repo, 2, "Ungu")
+					_, err := repo.Restore(ctx, 4)
+					if err != nil {
+						t.Fatal(err)
+					}
+					return errUji
+				}); !errors.Is(err, errUji) {
+					t.Errorf("Savepoint dalam = %v, ingin errUji", err)
+				}
+				if v, _ := repo.FindByID(ctx, 2); v.Warna != "Hitam" || v.Versi != 2 {
+					t.Errorf("data id 2 setelah savepoint dalam gagal = %+v, ingin Hitam versi 2", v)
+				}
+				if _, err := repo.FindByID(ctx, 4); err == nil {
+					t.Error("data id 4 masih dipulihkan setelah savepoint dalam gagal")
+				}
+				return errUji
+			})
+			if !errors.Is(err, errUji) {
+				t.Errorf("Savepoint = %v, ingin errUji", err)
+			}
+			ubahWarna(ctx, t, repo, 1, "Kuning")
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Perubahan di luar savepoint tersimpan; seluruh isi savepoint yang gagal (termasuk
+		// savepoint anaknya yang berhasil) dibatalkan. Versi id 1: Coklat 2, Merah 3
+		// (dibatalkan), Kuning 3.
+		want := keadaan{data: maps.Clone(awal.data), riwayat: awal.riwayat}
+		satu := want.data[1]
+		satu.Warna, satu.Versi = "Kuning", 3
+		want.data[1] = satu
+		periksaKeadaan(t, ambilKeadaan(t, repo, riwayat), want)
+	})
+
+	t.Run("transaksi gagal membatalkan savepoint yang berhasil", func(t *testing.T) {
+		repo, riwayat := repoUji(t)
+		tx := NewMemoryTxManager()
+		awal := ambilKeadaan(t, repo, riwayat)
+
+		err := tx.WithinTx(context.Background(), func(ctx context.Context) error {
+			ubahWarna(ctx, t, repo, 1, "Coklat")
+			if err := tx.Savepoint(ctx, func(ctx context.Context) error {
+				ubahSemua(ctx, t, repo, riwayat)
+				return tx.Savepoint(ctx, func(ctx context.Context) error {
+					ubahWarna(ctx, t, repo, 3, "Hitam")
+					return nil
+				})
+			}); err != nil {
+				t.Fatal(err)
+			}
+			return errUji
+		})
+		if !errors.Is(err, errUji) {
+			t.Fatalf("WithinTx = %v, ingin errUji", err)
+		}
+		periksaKeadaan(t, ambilKeadaan(t, repo, riwayat), awal)
+	})
+}
+
+func TestPerubahanDiLuarTransaksiTidakIkutDibatalkan(t *testing.T) {
+	repo, riwayat := repoUji(t)
+	tx := NewMemoryTxManager()
+
+	err := tx.WithinTx(context.Background(), func(ctx context.Context) error {
+		warna := "Coklat"
+		if _, err := repo.Patch(ctx, 1, domain.VarietasPatch{Warna: &warna}); err != nil {
+			t.Fatal(err)
+		}
+		// Perubahan dari luar transaksi (ctx tanpa undo log) terjadi bersamaan
+		if _, err := repo.Delete(context.Background(), 2); err != nil {
+			t.Fatal(err)
+		}
+		return errUji
+	})
+	if !errors.Is(err, errUji) {
+		t.Fatalf("WithinTx = %v, ingin errUji", err)
+	}
+	k := ambilKeadaan(t, repo, riwayat)
+	if v := k.data[1]; v.Warna != "Putih" || v.Versi != 1 {
+		t.Errorf("data id 1 = %+v, ingin Putih versi 1", v)
+	}
+	if v := k.data[2]; v.DeletedAt == nil || v.Versi != 2 {
+		t.Errorf("data id 2 = %+v, ingin tetap terhapus dengan versi 2", v)
+	}
+}
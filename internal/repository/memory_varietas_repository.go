@@ -0,0 +1,284 @@
+// internal/repository/memory_varietas_repository.go
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
+)
+
+// MemoryVarietasRepository adalah implementasi domain.VarietasRepository yang menyimpan
+// data di memori. Dipakai untuk test dan demo offline tanpa koneksi ke Neon.
+// Semantiknya mengikuti versi PostgreSQL: ID auto-increment, waktu_pembuatan diisi
+// saat insert, versi naik setiap perubahan, dan sql.ErrNoRows jika ID tidak ditemukan.
+type MemoryVarietasRepository struct {
+	mu     sync.RWMutex
+	data   map[int]domain.VarietasPadi
+	nextID int
+	now    func() time.Time
+}
+
+// NewMemoryVarietasRepository membuat repository in-memory yang kosong
+func NewMemoryVarietasRepository() *MemoryVarietasRepository {
+	return &MemoryVarietasRepository{
+		data:   map[int]domain.VarietasPadi{},
+		nextID: 1,
+		now:    time.Now,
+	}
+}
+
+// sorted mengembalikan salinan data yang lolos filter, terurut sesuai query
+func (r *MemoryVarietasRepository) sorted(q domain.VarietasQuery) []domain.VarietasPadi {
+	result := make([]domain.VarietasPadi, 0, len(r.data))
+	for _, v := range r.data {
+		if q.Match(v) {
+			result = append(result, v)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return q.Less(result[i], result[j]) })
+	return result
+}
+
+func (r *MemoryVarietasRepository) FindAll(ctx context.Context) ([]domain.VarietasPadi, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.sorted(domain.VarietasQuery{}), nil
+}
+
+func (r *MemoryVarietasRepository) FindPage(ctx context.Context, q domain.VarietasQuery) (domain.VarietasPage, error) {
+	q = q.Normalize()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	all := r.sorted(q)
+	page := domain.VarietasPage{Page: q.Page, PerPage: q.PerPage, Total: len(all), Data: []domain.VarietasPadi{}}
+
+	start := q.Offset()
+	if start >= len(all) {
+		return page, nil
+	}
+	end := min(start+q.PerPage, len(all))
+	page.Data = append(page.Data, all[start:end]...)
+	return page, nil
+}
+
+// Iterate memanggil fn untuk salinan data yang cocok dengan q. Lock dilepas sebelum
+// fn dipanggil agar penulis lambat (misalnya client ekspor) tidak menahan operasi lain.
+func (r *MemoryVarietasRepository) Iterate(ctx context.Context, q domain.VarietasQuery, fn func(domain.VarietasPadi) error) error {
+	r.mu.RLock()
+	all := r.sorted(q)
+	r.mu.RUnlock()
+
+	for _, v := range all {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *MemoryVarietasRepository) Create(ctx context.Context, data domain.VarietasPadi) (domain.VarietasPadi, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.insert(ctx, data), nil
+}
+
+// CreateBulk menyimpan semua data di bawah satu lock (setara satu transaksi)
+func (r *MemoryVarietasRepository) CreateBulk(ctx context.Context, data []domain.VarietasPadi) ([]domain.VarietasPadi, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	created := make([]domain.VarietasPadi, 0, len(data))
+	for _, d := range data {
+		created = append(created, r.insert(ctx, d))
+	}
+	return created, nil
+}
+
+// insert menyimpan satu data baru. Pemanggil harus memegang lock tulis.
+func (r *MemoryVarietasRepository) insert(ctx context.Context, data domain.VarietasPadi) domain.VarietasPadi {
+	// Sama seperti SERIAL + DEFAULT now() di PostgreSQL
+	data.ID = r.nextID
+	data.WaktuPembuatan = r.now()
+	data.Versi = 1
+	data.DeletedAt = nil
+	r.nextID++
+
+	r.catatLama(ctx, data.ID)
+	r.data[data.ID] = data
+	return data
+}
+
+func (r *MemoryVarietasRepository) FindByID(ctx context.Context, id int) (domain.VarietasPadi, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.find(id, false)
+}
+
+// Lock sama dengan FindByID (atau mencari di tempat sampah jika terhapus). Penguncian
+// baris tidak diperlukan karena MemoryTxManager menjalankan transaksi satu per satu.
+func (r *MemoryVarietasRepository) Lock(ctx context.Context, id int, terhapus bool) (domain.VarietasPadi, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.find(id, terhapus)
+}
+
+// LockDuplikat tidak melakukan apa pun karena MemoryTxManager menjalankan transaksi satu per satu
+func (r *MemoryVarietasRepository) LockDuplikat(ctx context.Context, kunci string) error {
+	return nil
+}
+
+// find mengambil data aktif (atau di tempat sampah jika terhapus). Pemanggil harus memegang lock.
+func (r *MemoryVarietasRepository) find(id int, terhapus bool) (domain.VarietasPadi, error) {
+	p, ok := r.data[id]
+	if !ok || (p.DeletedAt != nil) != terhapus {
+		return domain.VarietasPadi{}, sql.ErrNoRows
+	}
+	return p, nil
+}
+
+func (r *MemoryVarietasRepository) Update(ctx context.Context, data domain.VarietasPadi) (domain.VarietasPadi, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, err := r.find(data.ID, false)
+	if err != nil {
+		return domain.VarietasPadi{}, err
+	}
+
+	// waktu_pembuatan dan dibuat_oleh tidak pernah berubah saat update; versi naik seperti
+	// trigger di PostgreSQL
+	data.WaktuPembuatan = existing.WaktuPembuatan
+	data.DibuatOleh = existing.DibuatOleh
+	data.Versi = existing.Versi + 1
+	data.DeletedAt = nil
+	r.catatLama(ctx, data.ID)
+	r.data[data.ID] = data
+	return data, nil
+}
+
+// Delete adalah soft delete: data hanya diberi DeletedAt dan bisa dipulihkan lewat Restore
+func (r *MemoryVarietasRepository) Delete(ctx context.Context, id int) (domain.VarietasPadi, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	deleted, err := r.find(id, false)
+	if err != nil {
+		return domain.VarietasPadi{}, err
+	}
+	now := r.now()
+	deleted.DeletedAt = &now
+	deleted.Versi++
+	r.catatLama(ctx, id)
+	r.data[id] = deleted
+	return deleted, nil
+}
+
+// Restore mengeluarkan data dari tempat sampah
+func (r *MemoryVarietasRepository) Restore(ctx context.Context, id int) (domain.VarietasPadi, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	restored, err := r.find(id, true)
+	if err != nil {
+		return domain.VarietasPadi{}, err
+	}
+	restored.DeletedAt = nil
+	restored.Versi++
+	r.catatLama(ctx, id)
+	r.data[id] = restored
+	return restored, nil
+}
+
+// Purge menghapus permanen data di tempat sampah yang dihapus sebelum before
+func (r *MemoryVarietasRepository) Purge(ctx context.Context, before time.Time) ([]domain.VarietasPadi, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	purged := []domain.VarietasPadi{}
+	for id, v := range r.data {
+		if v.DeletedAt != nil && v.DeletedAt.Before(before) {
+			r.catatLama(ctx, id)
+			delete(r.data, id)
+			purged = append(purged, v)
+		}
+	}
+	sort.Slice(purged, func(i, j int) bool { return purged[i].ID < purged[j].ID })
+	return purged, nil
+}
+
+// usesKategori mengecek apakah ada data yang memakai nilai kosakata tertentu
+func (r *MemoryVarietasRepository) usesKategori(kategori, nilai string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, v := range r.data {
+		if v.KategoriValue(kategori) == nilai {
+			return true
+		}
+	}
+	return false
+}
+
+// renameKategori mengganti ejaan nilai kosakata di semua data (meniru ON UPDATE CASCADE)
+func (r *MemoryVarietasRepository) renameKategori(ctx context.Context, kategori, lama, baru string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, v := range r.data {
+		if v.KategoriValue(kategori) == lama {
+			v = v.WithKategoriValue(kategori, baru)
+			v.Versi++
+			r.catatLama(ctx, id)
+			r.data[id] = v
+		}
+	}
+}
+
+func (r *MemoryVarietasRepository) Patch(ctx context.Context, id int, patch domain.VarietasPatch) (domain.VarietasPadi, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, err := r.find(id, false)
+	if err != nil {
+		return domain.VarietasPadi{}, err
+	}
+	if patch.IsEmpty() {
+		return existing, nil
+	}
+
+	updated := patch.Apply(existing)
+	updated.Versi++
+	r.catatLama(ctx, id)
+	r.data[id] = updated
+	return updated, nil
+}
+
+// catatLama mencatat nilai data id sebelum diubah di undo log transaksi (lihat
+// MemoryTxManager). Rollback hanya memulihkan data id itu, sehingga perubahan data lain
+// tidak ikut hilang; nextID tidak dipulihkan, sama seperti sequence PostgreSQL.
+// Pemanggil harus memegang lock tulis.
+func (r *MemoryVarietasRepository) catatLama(ctx context.Context, id int) {
+	lama, ada := r.data[id]
+	catatUndo(ctx, func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if ada {
+			r.data[id] = lama
+		} else {
+			delete(r.data, id)
+		}
+	})
+}
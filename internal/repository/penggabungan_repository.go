@@ -0,0 +1,55 @@
+// internal/repository/penggabungan_repository.go
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
+)
+
+// PenggabunganRepository mengimplementasikan domain.PenggabunganRepository di tabel
+// penggabungan_varietas
+type PenggabunganRepository struct {
+	db *sql.DB
+}
+
+func NewPenggabunganRepository(db *sql.DB) *PenggabunganRepository {
+	return &PenggabunganRepository{db: db}
+}
+
+// Save dipanggil di dalam transaksi penggabungan (lihat VarietasService.GabungkanData)
+func (r *PenggabunganRepository) Save(ctx context.Context, p ...domain.Penggabungan) ([]domain.Penggabungan, error) {
+	conn := dbFor(ctx, r.db)
+	saved := make([]domain.Penggabungan, 0, len(p))
+	for _, g := range p {
+		// Data yang dulu digabung ke IDLama sekarang ikut mengarah ke IDBaru
+		if _, err := conn.ExecContext(ctx,
+			`UPDATE penggabungan_varietas SET id_baru = $1 WHERE id_baru = $2`, g.IDBaru, g.IDLama); err != nil {
+			return nil, translateError(err)
+		}
+
+		err := conn.QueryRowContext(ctx, `
+            INSERT INTO penggabungan_varietas (id_lama, id_baru, aktor)
+            VALUES ($1, $2, $3)
+            RETURNING id_lama, id_baru, aktor, waktu`, g.IDLama, g.IDBaru, g.Aktor,
+		).Scan(&g.IDLama, &g.IDBaru, &g.Aktor, &g.Waktu)
+		if err != nil {
+			return nil, translateError(err)
+		}
+		saved = append(saved, g)
+	}
+	return saved, nil
+}
+
+func (r *PenggabunganRepository) FindByID(ctx context.Context, id int) (domain.Penggabungan, error) {
+	var g domain.Penggabungan
+	err := dbFor(ctx, r.db).QueryRowContext(ctx, `
+        SELECT id_lama, id_baru, aktor, waktu
+        FROM penggabungan_varietas
+        WHERE id_lama = $1`, id).Scan(&g.IDLama, &g.IDBaru, &g.Aktor, &g.Waktu)
+	if err != nil {
+		return domain.Penggabungan{}, translateError(err)
+	}
+	return g, nil
+}
@@ -0,0 +1,152 @@
+// internal/repository/riwayat_repository.go
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
+)
+
+// RiwayatRepository mengimplementasikan domain.RiwayatRepository di tabel riwayat_varietas.
+// Tabel ini tidak punya foreign key ke DataPengamatanPadi agar riwayat tetap ada setelah purge.
+type RiwayatRepository struct {
+	db  *sql.DB
+	txm *TxManager
+}
+
+func NewRiwayatRepository(db *sql.DB) *RiwayatRepository {
+	return &RiwayatRepository{db: db, txm: NewTxManager(db)}
+}
+
+// kolomRiwayat adalah daftar kolom yang dibaca ke domain.Revisi (urutan sama dengan scanRevisi)
+const kolomRiwayat = `id, id_padi, versi, operasi, aktor, keterangan, waktu, sebelum, sesudah, perubahan`
+
+// kolomTulisRiwayat adalah kolom yang diisi saat menulis riwayat (urutan sama dengan revisiValues)
+const kolomTulisRiwayat = `id_padi, versi, operasi, aktor, keterangan, sebelum, sesudah, perubahan`
+
+// revisiValues menyusun nilai kolomTulisRiwayat; snapshot dan diff dikirim sebagai teks JSON
+func revisiValues(rev domain.Revisi) ([]any, error) {
+	sebelum, err := jsonOrNull(rev.Sebelum)
+	if err != nil {
+		return nil, err
+	}
+	sesudah, err := jsonOrNull(rev.Sesudah)
+	if err != nil {
+		return nil, err
+	}
+	perubahan, err := json.Marshal(rev.Perubahan)
+	if err != nil {
+		return nil, err
+	}
+	return []any{rev.IDPadi, rev.Versi, rev.Operasi, rev.Aktor, rev.Keterangan, sebelum, sesudah, string(perubahan)}, nil
+}
+
+// jsonOrNull mengubah snapshot menjadi teks JSON, atau NULL jika snapshot kosong
+func jsonOrNull(v *domain.VarietasPadi) (any, error) {
+	if v == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// scanRevisi membaca satu baris kolomRiwayat
+func scanRevisi(row rowScanner) (domain.Revisi, error) {
+	var rev domain.Revisi
+	var sebelum, sesudah, perubahan []byte
+	err := row.Scan(&rev.ID, &rev.IDPadi, &rev.Versi, &rev.Operasi, &rev.Aktor, &rev.Keterangan,
+		&rev.Waktu, &sebelum, &sesudah, &perubahan)
+	if err != nil {
+		return domain.Revisi{}, err
+	}
+
+	if sebelum != nil {
+		rev.Sebelum = &domain.VarietasPadi{}
+		if err := json.Unmarshal(sebelum, rev.Sebelum); err != nil {
+			return domain.Revisi{}, err
+		}
+	}
+	if sesudah != nil {
+		rev.Sesudah = &domain.VarietasPadi{}
+		if err := json.Unmarshal(sesudah, rev.Sesudah); err != nil {
+			return domain.Revisi{}, err
+		}
+	}
+	if err := json.Unmarshal(perubahan, &rev.Perubahan); err != nil {
+		return domain.Revisi{}, err
+	}
+	return rev, nil
+}
+
+// Catat menyimpan revisi di transaksi aktif ctx. Satu revisi ditulis dengan INSERT biasa,
+// banyak revisi sekaligus (impor, purge) lewat COPY.
+func (r *RiwayatRepository) Catat(ctx context.Context, revisi ...domain.Revisi) error {
+	switch len(revisi) {
+	case 0:
+		return nil
+	case 1:
+		values, err := revisiValues(revisi[0])
+		if err != nil {
+			return err
+		}
+		_, err = dbFor(ctx, r.db).ExecContext(ctx, `
+            INSERT INTO riwayat_varietas (`+kolomTulisRiwayat+`)
+            VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`, values...)
+		return translateError(err)
+	}
+
+	err := r.txm.WithinTx(ctx, func(ctx context.Context) error {
+		return withPgx(ctx, func(conn *pgx.Conn) error {
+			_, err := conn.CopyFrom(ctx, pgx.Identifier{"riwayat_varietas"}, strings.Split(kolomTulisRiwayat, ", "),
+				pgx.CopyFromSlice(len(revisi), func(i int) ([]any, error) {
+					return revisiValues(revisi[i])
+				}))
+			return err
+		})
+	})
+	return translateError(err)
+}
+
+// History mengembalikan semua revisi satu data, urut dari yang terlama.
+// Riwayat tetap bisa dibaca walaupun data sudah di tempat sampah atau dihapus permanen.
+func (r *RiwayatRepository) History(ctx context.Context, id int) ([]domain.Revisi, error) {
+	rows, err := dbFor(ctx, r.db).QueryContext(ctx, `
+        SELECT `+kolomRiwayat+`
+        FROM riwayat_varietas
+        WHERE id_padi = $1
+        ORDER BY id`, id)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	defer rows.Close()
+
+	result := []domain.Revisi{}
+	for rows.Next() {
+		rev, err := scanRevisi(rows)
+		if err != nil {
+			return nil, translateError(err)
+		}
+		result = append(result, rev)
+	}
+	return result, translateError(rows.Err())
+}
+
+// FindRevision mengambil satu revisi milik data id. sql.ErrNoRows jika tidak ada.
+func (r *RiwayatRepository) FindRevision(ctx context.Context, id int, revisiID int64) (domain.Revisi, error) {
+	rev, err := scanRevisi(dbFor(ctx, r.db).QueryRowContext(ctx, `
+        SELECT `+kolomRiwayat+`
+        FROM riwayat_varietas
+        WHERE id_padi = $1 AND id = $2`, id, revisiID))
+	if err != nil {
+		return domain.Revisi{}, translateError(err)
+	}
+	return rev, nil
+}
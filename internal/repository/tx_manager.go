@@ -0,0 +1,142 @@
+// internal/repository/tx_manager.go
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
+)
+
+// TxManager mengimplementasikan domain.TxManager untuk PostgreSQL. Transaksi aktif disimpan
+// di context sehingga semua repository di package ini yang menerima context tersebut
+// menjalankan query-nya di transaksi yang sama.
+type TxManager struct {
+	db *sql.DB
+	// maxPercobaan dan jedaAwal mengatur pengulangan transaksi yang gagal karena
+	// serialization failure (40001) atau deadlock (40P01)
+	maxPercobaan int
+	jedaAwal     time.Duration
+}
+
+// NewTxManager membuat TxManager untuk pool koneksi db
+func NewTxManager(db *sql.DB) *TxManager {
+	return &TxManager{db: db, maxPercobaan: 5, jedaAwal: 10 * time.Millisecond}
+}
+
+type txKey struct{}
+
+// txAktif adalah transaksi yang sedang berjalan beserta koneksi dedicated-nya.
+// conn dibutuhkan untuk API yang hanya ada di pgx (COPY) di dalam transaksi yang sama.
+type txAktif struct {
+	tx   *sql.Tx
+	conn *sql.Conn
+}
+
+// txFromContext mengembalikan transaksi aktif di ctx, atau nil
+func txFromContext(ctx context.Context) *txAktif {
+	aktif, _ := ctx.Value(txKey{}).(*txAktif)
+	return aktif
+}
+
+// querier dipenuhi oleh *sql.DB dan *sql.Tx
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// dbFor mengembalikan transaksi aktif di ctx, atau pool koneksi db jika tidak ada
+func dbFor(ctx context.Context, db *sql.DB) querier {
+	if aktif := txFromContext(ctx); aktif != nil {
+		return aktif.tx
+	}
+	return db
+}
+
+// WithinTx menjalankan fn dalam satu transaksi dan mengulanginya (dengan jeda acak yang
+// makin lama) jika gagal karena domain.ErrKonflikTransaksi. Pada isolasi READ COMMITTED
+// bawaan PostgreSQL ini terutama terjadi karena deadlock antar transaksi.
+func (m *TxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if txFromContext(ctx) != nil {
+		return fn(ctx)
+	}
+
+	jeda := m.jedaAwal
+	for percobaan := 1; ; percobaan++ {
+		err := m.jalankan(ctx, fn)
+		if err == nil || !errors.Is(err, domain.ErrKonflikTransaksi) || percobaan == m.maxPercobaan {
+			return err
+		}
+
+		timer := time.NewTimer(jeda/2 + rand.N(jeda))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		jeda *= 2
+	}
+}
+
+// jalankan menjalankan fn satu kali dalam transaksi baru di koneksi dedicated
+func (m *TxManager) jalankan(ctx context.Context, fn func(ctx context.Context) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return translateError(err)
+	}
+	defer conn.Close()
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return translateError(err)
+	}
+	if err := fn(context.WithValue(ctx, txKey{}, &txAktif{tx: tx, conn: conn})); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return translateError(tx.Commit())
+}
+
+// Savepoint menjalankan fn di dalam SAVEPOINT. Jika fn gagal, transaksi dikembalikan ke
+// savepoint sehingga tetap bisa dipakai untuk operasi berikutnya.
+func (m *TxManager) Savepoint(ctx context.Context, fn func(ctx context.Context) error) error {
+	aktif := txFromContext(ctx)
+	if aktif == nil {
+		return m.WithinTx(ctx, fn)
+	}
+
+	if _, err := aktif.tx.ExecContext(ctx, `SAVEPOINT operasi`); err != nil {
+		return translateError(err)
+	}
+	if err := fn(ctx); err != nil {
+		if _, rbErr := aktif.tx.ExecContext(ctx, `ROLLBACK TO SAVEPOINT operasi`); rbErr != nil {
+			return errors.Join(err, translateError(rbErr))
+		}
+		return err
+	}
+	_, err := aktif.tx.ExecContext(ctx, `RELEASE SAVEPOINT operasi`)
+	return translateError(err)
+}
+
+// errTanpaTransaksi menandakan bug: withPgx dipanggil di luar WithinTx
+var errTanpaTransaksi = errors.New("operasi pgx membutuhkan transaksi aktif di context")
+
+// withPgx menjalankan fn dengan *pgx.Conn milik transaksi aktif di ctx, untuk API yang
+// hanya tersedia di pgx seperti COPY. Perintah lewat conn ikut transaksi yang sama.
+func withPgx(ctx context.Context, fn func(conn *pgx.Conn) error) error {
+	aktif := txFromContext(ctx)
+	if aktif == nil {
+		return errTanpaTransaksi
+	}
+	return aktif.conn.Raw(func(driverConn any) error {
+		return fn(driverConn.(*stdlib.Conn).Conn())
+	})
+}
@@ -0,0 +1,115 @@
+// internal/repository/varietas_bulk.go
+package repository
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
+)
+
+// kolomImpor adalah kolom tabel staging impor_varietas yang diisi lewat COPY
+var kolomImpor = []string{"id_padi", "varietas_kelas", "warna", "panjang_biji_mm", "lebar_biji_mm", "tekstur_permukaan", "bentuk_ujung_daun", "dibuat_oleh"}
+
+// CreateBulk menyimpan banyak data sekaligus memakai protokol COPY PostgreSQL.
+// Alurnya dalam satu transaksi: ambil ID dari sequence, COPY ke tabel staging sementara,
+// lalu INSERT ... SELECT ke DataPengamatanPadi. ID diambil lebih dulu agar urutan hasil
+// pasti sama dengan urutan input.
+func (r *VarietasRepository) CreateBulk(ctx context.Context, data []domain.VarietasPadi) ([]domain.VarietasPadi, error) {
+	if len(data) == 0 {
+		return []domain.VarietasPadi{}, nil
+	}
+
+	var created []domain.VarietasPadi
+	err := r.txm.WithinTx(ctx, func(ctx context.Context) error {
+		// COPY hanya tersedia di API pgx, jadi pakai *pgx.Conn milik transaksi aktif
+		return withPgx(ctx, func(conn *pgx.Conn) error {
+			var err error
+			created, err = copyVarietas(ctx, conn, data)
+			return err
+		})
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return created, nil
+}
+
+// copyVarietas menjalankan langkah-langkah CreateBulk di transaksi yang sedang berjalan di conn
+func copyVarietas(ctx context.Context, conn *pgx.Conn, data []domain.VarietasPadi) ([]domain.VarietasPadi, error) {
+	ids, err := reserveIDs(ctx, conn, len(data))
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = conn.Exec(ctx, `
+        CREATE TEMP TABLE impor_varietas (
+            id_padi           INTEGER,
+            varietas_kelas    VARCHAR(100),
+            warna             VARCHAR(50),
+            panjang_biji_mm   DOUBLE PRECISION,
+            lebar_biji_mm     DOUBLE PRECISION,
+            tekstur_permukaan VARCHAR(50),
+            bentuk_ujung_daun VARCHAR(50),
+            dibuat_oleh       VARCHAR(100)
+        ) ON COMMIT DROP`)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = conn.CopyFrom(ctx, pgx.Identifier{"impor_varietas"}, kolomImpor,
+		pgx.CopyFromSlice(len(data), func(i int) ([]any, error) {
+			d := data[i]
+			return []any{ids[i], d.VarietasKelas, d.Warna, d.PanjangBijiMM, d.LebarBijiMM, d.TeksturPermukaan, d.BentukUjungDaun, d.DibuatOleh}, nil
+		}))
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := conn.Query(ctx, `
+        INSERT INTO DataPengamatanPadi (`+strings.Join(kolomImpor, ", ")+`)
+        SELECT `+strings.Join(kolomImpor, ", ")+` FROM impor_varietas
+        RETURNING `+kolomPengamatan)
+	if err != nil {
+		return nil, err
+	}
+	created := make([]domain.VarietasPadi, 0, len(data))
+	for rows.Next() {
+		p, err := scanVarietas(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		created = append(created, p)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	sort.Slice(created, func(i, j int) bool { return created[i].ID < created[j].ID })
+
+	// Tabel staging dibuang sekarang juga agar CreateBulk bisa dipanggil lagi di transaksi yang sama
+	if _, err := conn.Exec(ctx, `DROP TABLE impor_varietas`); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// reserveIDs mengambil n ID berurutan dari sequence id_padi
+func reserveIDs(ctx context.Context, conn *pgx.Conn, n int) ([]int, error) {
+	rows, err := conn.Query(ctx, `
+        SELECT nextval(pg_get_serial_sequence('datapengamatanpadi', 'id_padi'))
+        FROM generate_series(1, $1)`, n)
+	if err != nil {
+		return nil, err
+	}
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return nil, err
+	}
+	sort.Ints(ids)
+	return ids, nil
+}
@@ -0,0 +1,118 @@
+// internal/repository/varietas_query.go
+package repository
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
+)
+
+// kolomVarietas memetakan nama field domain (JSON) ke nama kolom SQL.
+// Hanya kolom yang ada di peta ini yang bisa masuk ke query dinamis.
+var kolomVarietas = map[string]string{
+	"id_padi":           "id_padi",
+	"varietas_kelas":    "varietas_kelas",
+	"warna":             "warna",
+	"panjang_biji_mm":   "panjang_biji_mm",
+	"lebar_biji_mm":     "lebar_biji_mm",
+	"tekstur_permukaan": "tekstur_permukaan",
+	"bentuk_ujung_daun": "bentuk_ujung_daun",
+}
+
+// ekspresiKolom mengembalikan ekspresi SQL untuk sebuah field: nama kolom untuk field yang
+// disimpan, atau ekspresi CASE untuk field turunan. Ekspresi turunan disusun dari tabel
+// ambang yang aktif sehingga hasil filter/sort SQL sama dengan perhitungan di domain.
+func ekspresiKolom(field string) (string, bool) {
+	panjang, bentuk := domain.TabelKategoriBiji()
+	switch field {
+	case "kategori_panjang":
+		return ekspresiAmbang(panjang, "panjang_biji_mm"), true
+	case "bentuk_biji":
+		// Sama dengan VarietasPadi.BentukBiji: kosong jika lebar belum diukur
+		return "(CASE WHEN lebar_biji_mm IS NULL OR lebar_biji_mm <= 0 THEN '' ELSE " +
+			ekspresiAmbang(bentuk, "panjang_biji_mm / lebar_biji_mm") + " END)", true
+	}
+	col, ok := kolomVarietas[field]
+	return col, ok
+}
+
+// ekspresiAmbang menerjemahkan TabelAmbang menjadi CASE WHEN nilai > batas THEN kategori ...
+func ekspresiAmbang(t domain.TabelAmbang, nilai string) string {
+	var b strings.Builder
+	b.WriteString("(CASE")
+	for _, a := range t.Ambang {
+		fmt.Fprintf(&b, " WHEN %s > %s THEN %s", nilai, strconv.FormatFloat(a.Batas, 'g', -1, 64), literalTeks(a.Kategori))
+	}
+	fmt.Fprintf(&b, " ELSE %s END)", literalTeks(t.Sisa))
+	return b.String()
+}
+
+// literalTeks menulis string konstanta SQL. Hanya untuk nama kategori dari konfigurasi
+// server; nilai dari request selalu dikirim sebagai parameter.
+func literalTeks(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// operatorSQL memetakan operator filter domain ke operator SQL
+var operatorSQL = map[domain.FilterOp]string{
+	domain.OpEq:  "=",
+	domain.OpNe:  "<>",
+	domain.OpGt:  ">",
+	domain.OpGte: ">=",
+	domain.OpLt:  "<",
+	domain.OpLte: "<=",
+}
+
+// Kondisi lingkup data: aktif atau di tempat sampah (soft delete)
+const (
+	scopeAktif    = "deleted_at IS NULL"
+	scopeTerhapus = "deleted_at IS NOT NULL"
+)
+
+// buildWhere menyusun klausa WHERE berparameter ($1, $2, ...) dari filter query.
+// Nilai filter selalu dikirim sebagai argumen, tidak pernah digabung ke string SQL.
+// Klausa selalu diawali kondisi lingkup sesuai q.Terhapus.
+func buildWhere(q domain.VarietasQuery) (string, []any, error) {
+	scope := scopeAktif
+	if q.Terhapus {
+		scope = scopeTerhapus
+	}
+
+	conds := make([]string, 0, len(q.Filters)+1)
+	conds = append(conds, scope)
+	args := make([]any, 0, len(q.Filters))
+	for _, f := range q.Filters {
+		col, ok := ekspresiKolom(f.Field)
+		if !ok {
+			return "", nil, fmt.Errorf("%w: field filter '%s' tidak dikenal", domain.ErrQueryTidakValid, f.Field)
+		}
+		op, ok := operatorSQL[f.Op]
+		if !ok {
+			return "", nil, fmt.Errorf("%w: operator '%s' tidak dikenal", domain.ErrQueryTidakValid, f.Op)
+		}
+		args = append(args, f.Value)
+		conds = append(conds, fmt.Sprintf("%s %s $%d", col, op, len(args)))
+	}
+	return "WHERE " + strings.Join(conds, " AND "), args, nil
+}
+
+// buildOrderBy menyusun klausa ORDER BY dari daftar sort.
+// id_padi selalu ditambahkan di akhir agar urutan antar halaman stabil.
+func buildOrderBy(sorts []domain.SortField) (string, error) {
+	parts := make([]string, 0, len(sorts)+1)
+	for _, s := range sorts {
+		col, ok := ekspresiKolom(s.Field)
+		if !ok {
+			return "", fmt.Errorf("%w: field sort '%s' tidak dikenal", domain.ErrQueryTidakValid, s.Field)
+		}
+		dir := "ASC"
+		if s.Desc {
+			dir = "DESC"
+		}
+		parts = append(parts, col+" "+dir)
+	}
+	parts = append(parts, "id_padi ASC")
+	return "ORDER BY " + strings.Join(parts, ", "), nil
+}
@@ -5,89 +5,265 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
 )
 
+// VarietasRepository mengimplementasikan domain.VarietasRepository di PostgreSQL.
+// Setiap method memakai transaksi aktif di context jika ada (lihat TxManager).
 type VarietasRepository struct {
-	db *sql.DB
+	db  *sql.DB
+	txm *TxManager // untuk operasi multi-perintah yang dipanggil di luar WithinTx
 }
 
 func NewVarietasRepository(db *sql.DB) *VarietasRepository {
-	return &VarietasRepository{db: db}
+	return &VarietasRepository{db: db, txm: NewTxManager(db)}
+}
+
+// kolomPengamatan adalah daftar kolom yang dibaca ke domain.VarietasPadi.
+// Urutannya harus sama dengan scanVarietas.
+const kolomPengamatan = `id_padi, varietas_kelas, warna, panjang_biji_mm, lebar_biji_mm,
+		       tekstur_permukaan, bentuk_ujung_daun, waktu_pembuatan, versi, deleted_at, dibuat_oleh`
+
+// conn mengembalikan transaksi aktif di ctx, atau pool koneksi jika tidak ada
+func (r *VarietasRepository) conn(ctx context.Context) querier {
+	return dbFor(ctx, r.db)
+}
+
+// rowScanner dipenuhi oleh *sql.Row dan *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanVarietas membaca satu baris kolomPengamatan
+func scanVarietas(row rowScanner) (domain.VarietasPadi, error) {
+	var p domain.VarietasPadi
+	err := row.Scan(&p.ID, &p.VarietasKelas, &p.Warna, &p.PanjangBijiMM, &p.LebarBijiMM,
+		&p.TeksturPermukaan, &p.BentukUjungDaun, &p.WaktuPembuatan, &p.Versi, &p.DeletedAt, &p.DibuatOleh)
+	return p, err
 }
 
 func (r *VarietasRepository) FindAll(ctx context.Context) ([]domain.VarietasPadi, error) {
 	query := `
-		SELECT id_padi, varietas_kelas, warna, panjang_biji_mm, 
-		       tekstur_permukaan, bentuk_ujung_daun, waktu_pembuatan
+		SELECT ` + kolomPengamatan + `
 		FROM DataPengamatanPadi
+		WHERE deleted_at IS NULL
 		ORDER BY id_padi
 	`
 
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := r.conn(ctx).QueryContext(ctx, query)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
 	defer rows.Close()
 
 	var result []domain.VarietasPadi
 	for rows.Next() {
-		var p domain.VarietasPadi
-		if err := rows.Scan(&p.ID, &p.VarietasKelas, &p.Warna, &p.PanjangBijiMM,
-			&p.TeksturPermukaan, &p.BentukUjungDaun, &p.WaktuPembuatan); err != nil {
-			return nil, err
+		p, err := scanVarietas(rows)
+		if err != nil {
+			return nil, translateError(err)
 		}
 		result = append(result, p)
 	}
-	return result, rows.Err()
+	return result, translateError(rows.Err())
+}
+
+// FindPage mengambil satu halaman data sesuai filter, sort, dan pagination di VarietasQuery.
+// Total dihitung dengan query COUNT terpisah memakai klausa WHERE yang sama.
+func (r *VarietasRepository) FindPage(ctx context.Context, q domain.VarietasQuery) (domain.VarietasPage, error) {
+	q = q.Normalize()
+
+	where, args, err := buildWhere(q)
+	if err != nil {
+		return domain.VarietasPage{}, err
+	}
+	orderBy, err := buildOrderBy(q.Sort)
+	if err != nil {
+		return domain.VarietasPage{}, err
+	}
+
+	page := domain.VarietasPage{Page: q.Page, PerPage: q.PerPage, Data: []domain.VarietasPadi{}}
+
+	countQuery := `SELECT COUNT(*) FROM DataPengamatanPadi ` + where
+	if err := r.conn(ctx).QueryRowContext(ctx, countQuery, args...).Scan(&page.Total); err != nil {
+		return domain.VarietasPage{}, translateError(err)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM DataPengamatanPadi
+		%s
+		%s
+		LIMIT $%d OFFSET $%d
+	`, kolomPengamatan, where, orderBy, len(args)+1, len(args)+2)
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, append(args, q.PerPage, q.Offset())...)
+	if err != nil {
+		return domain.VarietasPage{}, translateError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		p, err := scanVarietas(rows)
+		if err != nil {
+			return domain.VarietasPage{}, translateError(err)
+		}
+		page.Data = append(page.Data, p)
+	}
+	return page, translateError(rows.Err())
+}
+
+// ukuranFetchEkspor adalah jumlah baris yang diambil per FETCH saat Iterate
+const ukuranFetchEkspor = 500
+
+// Iterate membaca data dengan server-side cursor (DECLARE ... CURSOR + FETCH) di dalam
+// transaksi read-only, sehingga hanya ukuranFetchEkspor baris yang ada di memori sekaligus.
+func (r *VarietasRepository) Iterate(ctx context.Context, q domain.VarietasQuery, fn func(domain.VarietasPadi) error) error {
+	where, args, err := buildWhere(q)
+	if err != nil {
+		return err
+	}
+	orderBy, err := buildOrderBy(q.Sort)
+	if err != nil {
+		return err
+	}
+
+	aktif := txFromContext(ctx)
+	var tx *sql.Tx
+	if aktif != nil {
+		tx = aktif.tx
+	} else {
+		tx, err = r.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+		if err != nil {
+			return translateError(err)
+		}
+		defer tx.Rollback() // cursor ikut ditutup saat transaksi selesai
+	}
+
+	_, err = tx.ExecContext(ctx, fmt.Sprintf(`
+		DECLARE ekspor_varietas NO SCROLL CURSOR FOR
+		SELECT %s
+		FROM DataPengamatanPadi
+		%s
+		%s
+	`, kolomPengamatan, where, orderBy), args...)
+	if err != nil {
+		return translateError(err)
+	}
+	if aktif != nil {
+		// Di dalam transaksi WithinTx cursor harus ditutup sendiri agar nama cursor bisa dipakai lagi
+		defer tx.ExecContext(ctx, `CLOSE ekspor_varietas`)
+	}
+
+	fetch := fmt.Sprintf(`FETCH %d FROM ekspor_varietas`, ukuranFetchEkspor)
+	for {
+		n, err := fetchBatch(ctx, tx, fetch, fn)
+		if err != nil {
+			return err
+		}
+		if n < ukuranFetchEkspor {
+			return nil
+		}
+	}
+}
+
+// fetchBatch menjalankan satu FETCH dan meneruskan setiap baris ke fn
+func fetchBatch(ctx context.Context, tx *sql.Tx, fetch string, fn func(domain.VarietasPadi) error) (int, error) {
+	rows, err := tx.QueryContext(ctx, fetch)
+	if err != nil {
+		return 0, translateError(err)
+	}
+	defer rows.Close()
+
+	n := 0
+	for rows.Next() {
+		p, err := scanVarietas(rows)
+		if err != nil {
+			return n, translateError(err)
+		}
+		if err := fn(p); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, translateError(rows.Err())
 }
 
 // Mengimplementasikan interface domain.VarietasRepository
+// Operasi tulis hanya menulis data; riwayatnya dicatat service lewat RiwayatRepository
+// di transaksi yang sama.
 func (r *VarietasRepository) Create(ctx context.Context, data domain.VarietasPadi) (domain.VarietasPadi, error) {
 	query := `
-        INSERT INTO DataPengamatanPadi (varietas_kelas, warna, panjang_biji_mm, 
-                                      tekstur_permukaan, bentuk_ujung_daun)
-        VALUES ($1, $2, $3, $4, $5)
-        RETURNING id_padi
-    `
-	err := r.db.QueryRow(query,
+        INSERT INTO DataPengamatanPadi (varietas_kelas, warna, panjang_biji_mm, lebar_biji_mm,
+                                      tekstur_permukaan, bentuk_ujung_daun, dibuat_oleh)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
+        RETURNING ` + kolomPengamatan
+
+	created, err := scanVarietas(r.conn(ctx).QueryRowContext(ctx, query,
 		data.VarietasKelas,
 		data.Warna,
 		data.PanjangBijiMM,
+		data.LebarBijiMM,
 		data.TeksturPermukaan,
 		data.BentukUjungDaun,
-	).Scan(&data.ID)
-
+		data.DibuatOleh,
+	))
 	if err != nil {
-		return domain.VarietasPadi{}, err
+		return domain.VarietasPadi{}, translateError(err)
 	}
-	return data, nil
+	return created, nil
+}
+
+// kelasLockDuplikat adalah kunci pertama pg_advisory_xact_lock(int, int) untuk LockDuplikat,
+// agar tidak bertabrakan dengan advisory lock lain (misalnya lock migrasi)
+const kelasLockDuplikat = 0x64757073 // "dups"
+
+// LockDuplikat mengambil advisory lock transaksi untuk hash kunci duplikat. Hash yang kebetulan
+// sama hanya membuat dua transaksi saling menunggu, tidak pernah meloloskan duplikat.
+func (r *VarietasRepository) LockDuplikat(ctx context.Context, kunci string) error {
+	_, err := r.conn(ctx).ExecContext(ctx, `SELECT pg_advisory_xact_lock($1, hashtext($2))`, kelasLockDuplikat, kunci)
+	return translateError(err)
 }
 
 // internal/repository/varietas_repository.go (Tambahan)
 
 func (r *VarietasRepository) FindByID(ctx context.Context, id int) (domain.VarietasPadi, error) {
 	query := `
-        SELECT id_padi, varietas_kelas, warna, panjang_biji_mm, 
-               tekstur_permukaan, bentuk_ujung_daun, waktu_pembuatan
+        SELECT ` + kolomPengamatan + `
         FROM DataPengamatanPadi
-        WHERE id_padi = $1
+        WHERE id_padi = $1 AND deleted_at IS NULL
     `
-	var p domain.VarietasPadi
 
 	// Gunakan QueryRowContext untuk operasi Read tunggal
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&p.ID, &p.VarietasKelas, &p.Warna, &p.PanjangBijiMM,
-		&p.TeksturPermukaan, &p.BentukUjungDaun, &p.WaktuPembuatan,
-	)
+	p, err := scanVarietas(r.conn(ctx).QueryRowContext(ctx, query, id))
 
 	if err != nil {
 		// Jika data tidak ditemukan, kembalikan error spesifik dari sql
 		if errors.Is(err, sql.ErrNoRows) {
 			return domain.VarietasPadi{}, sql.ErrNoRows
 		}
-		return domain.VarietasPadi{}, err
+		return domain.VarietasPadi{}, translateError(err)
+	}
+	return p, nil
+}
+
+// Lock membaca data id dengan FOR UPDATE. Baris tetap terkunci sampai transaksi di ctx
+// selesai, jadi harus dipanggil di dalam TxManager.WithinTx.
+func (r *VarietasRepository) Lock(ctx context.Context, id int, terhapus bool) (domain.VarietasPadi, error) {
+	scope := scopeAktif
+	if terhapus {
+		scope = scopeTerhapus
+	}
+	p, err := scanVarietas(r.conn(ctx).QueryRowContext(ctx, `
+        SELECT `+kolomPengamatan+`
+        FROM DataPengamatanPadi
+        WHERE id_padi = $1 AND `+scope+`
+        FOR UPDATE`, id))
+	if err != nil {
+		return domain.VarietasPadi{}, translateError(err)
 	}
 	return p, nil
 }
@@ -95,50 +271,120 @@ func (r *VarietasRepository) FindByID(ctx context.Context, id int) (domain.Varie
 // internal/repository/varietas_repository.go (Tambahan)
 
 func (r *VarietasRepository) Update(ctx context.Context, data domain.VarietasPadi) (domain.VarietasPadi, error) {
+	// Kolom versi dinaikkan oleh trigger trg_pengamatan_versi
 	query := `
         UPDATE DataPengamatanPadi
-        SET varietas_kelas=$2, warna=$3, panjang_biji_mm=$4, 
-            tekstur_permukaan=$5, bentuk_ujung_daun=$6
-        WHERE id_padi = $1
-        RETURNING id_padi, waktu_pembuatan
-    `
+        SET varietas_kelas=$2, warna=$3, panjang_biji_mm=$4, lebar_biji_mm=$5,
+            tekstur_permukaan=$6, bentuk_ujung_daun=$7
+        WHERE id_padi = $1 AND deleted_at IS NULL
+        RETURNING ` + kolomPengamatan
 
-	// Gunakan QueryRowContext untuk mendapatkan kembali data yang baru diupdate
-	err := r.db.QueryRowContext(ctx, query,
+	updated, err := scanVarietas(r.conn(ctx).QueryRowContext(ctx, query,
 		data.ID,
 		data.VarietasKelas,
 		data.Warna,
 		data.PanjangBijiMM,
+		data.LebarBijiMM,
 		data.TeksturPermukaan,
 		data.BentukUjungDaun,
-	).Scan(&data.ID, &data.WaktuPembuatan)
-
+	))
 	if err != nil {
-		return domain.VarietasPadi{}, err
+		return domain.VarietasPadi{}, translateError(err)
 	}
-	return data, nil
+	return updated, nil
 }
 
 // internal/repository/varietas_repository.go (Tambahan)
 
-func (r *VarietasRepository) Delete(ctx context.Context, id int) error {
-	query := `DELETE FROM DataPengamatanPadi WHERE id_padi = $1`
+// Delete adalah soft delete: baris hanya diberi deleted_at dan bisa dipulihkan lewat Restore
+func (r *VarietasRepository) Delete(ctx context.Context, id int) (domain.VarietasPadi, error) {
+	deleted, err := scanVarietas(r.conn(ctx).QueryRowContext(ctx, `
+        UPDATE DataPengamatanPadi SET deleted_at = now()
+        WHERE id_padi = $1 AND deleted_at IS NULL
+        RETURNING `+kolomPengamatan, id))
+	if err != nil {
+		return domain.VarietasPadi{}, translateError(err)
+	}
+	return deleted, nil
+}
 
-	// Gunakan ExecContext untuk operasi yang tidak mengembalikan row
-	res, err := r.db.ExecContext(ctx, query, id)
+// Restore mengeluarkan data dari tempat sampah
+func (r *VarietasRepository) Restore(ctx context.Context, id int) (domain.VarietasPadi, error) {
+	restored, err := scanVarietas(r.conn(ctx).QueryRowContext(ctx, `
+        UPDATE DataPengamatanPadi SET deleted_at = NULL
+        WHERE id_padi = $1 AND deleted_at IS NOT NULL
+        RETURNING `+kolomPengamatan, id))
 	if err != nil {
-		return err
+		return domain.VarietasPadi{}, translateError(err) // sql.ErrNoRows jika id tidak ada di tempat sampah
 	}
+	return restored, nil
+}
 
-	// Cek apakah ada row yang terpengaruh (terhapus)
-	rowsAffected, err := res.RowsAffected()
+// Purge menghapus permanen (hard delete) data di tempat sampah yang dihapus sebelum before
+func (r *VarietasRepository) Purge(ctx context.Context, before time.Time) ([]domain.VarietasPadi, error) {
+	rows, err := r.conn(ctx).QueryContext(ctx, `
+        DELETE FROM DataPengamatanPadi
+        WHERE deleted_at IS NOT NULL AND deleted_at < $1
+        RETURNING `+kolomPengamatan, before)
 	if err != nil {
-		return err
+		return nil, translateError(err)
 	}
-	if rowsAffected == 0 {
-		// Ini penting: jika tidak ada row yang terhapus, kita kembalikan sql.ErrNoRows
-		return sql.ErrNoRows
+	defer rows.Close()
+
+	purged := []domain.VarietasPadi{}
+	for rows.Next() {
+		p, err := scanVarietas(rows)
+		if err != nil {
+			return nil, translateError(err)
+		}
+		purged = append(purged, p)
 	}
+	return purged, translateError(rows.Err())
+}
 
-	return nil
+// Patch hanya memperbarui kolom yang diisi di patch (partial update)
+func (r *VarietasRepository) Patch(ctx context.Context, id int, patch domain.VarietasPatch) (domain.VarietasPadi, error) {
+	// Susun SET hanya dari kolom yang dikirim; nama kolom berasal dari whitelist kolomVarietas
+	values := map[string]any{}
+	if patch.VarietasKelas != nil {
+		values["varietas_kelas"] = *patch.VarietasKelas
+	}
+	if patch.Warna != nil {
+		values["warna"] = *patch.Warna
+	}
+	if patch.PanjangBijiMM != nil {
+		values["panjang_biji_mm"] = *patch.PanjangBijiMM
+	}
+	if patch.LebarBijiMM != nil {
+		values["lebar_biji_mm"] = *patch.LebarBijiMM // nil menjadi NULL
+	}
+	if patch.TeksturPermukaan != nil {
+		values["tekstur_permukaan"] = *patch.TeksturPermukaan
+	}
+	if patch.BentukUjungDaun != nil {
+		values["bentuk_ujung_daun"] = *patch.BentukUjungDaun
+	}
+	if len(values) == 0 {
+		return r.FindByID(ctx, id)
+	}
+
+	args := []any{id}
+	sets := make([]string, 0, len(values))
+	for _, field := range patch.Fields() {
+		args = append(args, values[field])
+		sets = append(sets, fmt.Sprintf("%s = $%d", kolomVarietas[field], len(args)))
+	}
+
+	query := fmt.Sprintf(`
+        UPDATE DataPengamatanPadi
+        SET %s
+        WHERE id_padi = $1 AND deleted_at IS NULL
+        RETURNING %s
+    `, strings.Join(sets, ", "), kolomPengamatan)
+
+	updated, err := scanVarietas(r.conn(ctx).QueryRowContext(ctx, query, args...))
+	if err != nil {
+		return domain.VarietasPadi{}, translateError(err)
+	}
+	return updated, nil
 }
@@ -0,0 +1,115 @@
+// internal/repository/varietas_statistik.go
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
+)
+
+// Statistik menghitung statistik deskriptif langsung di PostgreSQL (memenuhi
+// domain.StatistikRepository): satu query agregat untuk panjang biji dan satu query
+// UNION ALL untuk semua tabel frekuensi, keduanya dengan klausa WHERE yang sama.
+func (r *VarietasRepository) Statistik(ctx context.Context, q domain.VarietasQuery, groupBy string) (domain.StatistikVarietas, error) {
+	where, args, err := buildWhere(q)
+	if err != nil {
+		return domain.StatistikVarietas{}, err
+	}
+
+	// Tanpa group_by semua data masuk satu grup dengan nama kosong
+	grup, groupClause := `''::text`, ""
+	if groupBy != "" {
+		col, ok := ekspresiKolom(groupBy)
+		if !ok {
+			return domain.StatistikVarietas{}, fmt.Errorf("%w: field group_by '%s' tidak dikenal", domain.ErrQueryTidakValid, groupBy)
+		}
+		grup, groupClause = col, "GROUP BY "+col
+	}
+
+	hasil := domain.StatistikVarietas{GroupBy: groupBy, Grup: []domain.StatistikGrup{}}
+	indeks := map[string]int{}
+
+	rows, err := r.conn(ctx).QueryContext(ctx, fmt.Sprintf(`
+		SELECT %[1]s AS grup,
+		       COUNT(*),
+		       MIN(panjang_biji_mm),
+		       percentile_cont(0.25) WITHIN GROUP (ORDER BY panjang_biji_mm),
+		       percentile_cont(0.5)  WITHIN GROUP (ORDER BY panjang_biji_mm),
+		       percentile_cont(0.75) WITHIN GROUP (ORDER BY panjang_biji_mm),
+		       MAX(panjang_biji_mm),
+		       AVG(panjang_biji_mm),
+		       COALESCE(stddev_samp(panjang_biji_mm), 0)
+		FROM DataPengamatanPadi
+		%[2]s
+		%[3]s
+		ORDER BY grup
+	`, grup, where, groupClause), args...)
+	if err != nil {
+		return domain.StatistikVarietas{}, translateError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var g domain.StatistikGrup
+		var s domain.StatistikNumerik
+		// Tanpa GROUP BY, data kosong tetap menghasilkan satu baris COUNT 0 dengan nilai NULL
+		var min, q1, median, q3, max, mean *float64
+		if err := rows.Scan(&g.Grup, &s.Count, &min, &q1, &median, &q3, &max, &mean, &s.StdDev); err != nil {
+			return domain.StatistikVarietas{}, translateError(err)
+		}
+		if s.Count == 0 {
+			continue
+		}
+		s.Min, s.Q1, s.Median, s.Q3, s.Max, s.Mean = *min, *q1, *median, *q3, *max, *mean
+		g.PanjangBijiMM = s
+		g.Frekuensi = map[string][]domain.Frekuensi{}
+
+		indeks[g.Grup] = len(hasil.Grup)
+		hasil.Grup = append(hasil.Grup, g)
+		hasil.Total += s.Count
+	}
+	if err := rows.Err(); err != nil {
+		return domain.StatistikVarietas{}, translateError(err)
+	}
+	if len(hasil.Grup) == 0 {
+		return hasil, nil
+	}
+
+	var bagian []string
+	for _, field := range domain.FieldKategorikal {
+		if field == groupBy {
+			continue
+		}
+		col, _ := ekspresiKolom(field)
+		bagian = append(bagian, fmt.Sprintf(`
+		SELECT %[1]s AS grup, '%[2]s' AS field, %[3]s AS nilai, COUNT(*) AS jumlah
+		FROM DataPengamatanPadi
+		%[4]s
+		GROUP BY 1, 3`, grup, field, col, where))
+	}
+
+	rows, err = r.conn(ctx).QueryContext(ctx,
+		strings.Join(bagian, "\n\t\tUNION ALL")+"\n\t\tORDER BY grup, field, jumlah DESC, nilai", args...)
+	if err != nil {
+		return domain.StatistikVarietas{}, translateError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key, field string
+		var f domain.Frekuensi
+		if err := rows.Scan(&key, &field, &f.Nilai, &f.Jumlah); err != nil {
+			return domain.StatistikVarietas{}, translateError(err)
+		}
+		i, ok := indeks[key]
+		if !ok {
+			continue
+		}
+		g := &hasil.Grup[i]
+		f.Proporsi = float64(f.Jumlah) / float64(g.PanjangBijiMM.Count)
+		g.Frekuensi[field] = append(g.Frekuensi[field], f)
+	}
+	return hasil, translateError(rows.Err())
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
+)
+
+// Aturan adalah izin satu method service
+type Aturan struct {
+	// Peran boleh memanggil method untuk data mana pun
+	Peran []domain.Peran
+	// PeranPemilik hanya boleh memanggil method untuk data yang ia buat sendiri
+	// (VarietasPadi.DibuatOleh sama dengan subjek token)
+	PeranPemilik []domain.Peran
+}
+
+// Kelompok peran yang dipakai tabel Kebijakan
+var (
+	peranBaca    = []domain.Peran{domain.PeranViewer, domain.PeranTechnician, domain.PeranCurator, domain.PeranAdmin}
+	peranPenulis = []domain.Peran{domain.PeranTechnician, domain.PeranCurator, domain.PeranAdmin}
+	peranKurator = []domain.Peran{domain.PeranCurator, domain.PeranAdmin}
+	peranAdmin   = []domain.Peran{domain.PeranAdmin}
+	peranTeknisi = []domain.Peran{domain.PeranTechnician}
+)
+
+// Kebijakan adalah izin setiap method domain.VarietasService dan domain.KosakataService,
+// dengan kunci nama method. Method yang tidak terdaftar selalu ditolak.
+var Kebijakan = map[string]Aturan{
+	// Membaca data, riwayat, dan hasil analisis
+	"DapatkanDataByID":         {Peran: peranBaca},
+	"DapatkanSemuaData":        {Peran: peranBaca},
+	"EksporData":               {Peran: peranBaca},
+	"RiwayatData":              {Peran: peranBaca},
+	"PrediksiKelas":            {Peran: peranBaca},
+	"EvaluasiKlasifikasi":      {Peran: peranBaca},
+	"StatistikData":            {Peran: peranBaca},
+	"CariMirip":                {Peran: peranBaca},
+	"CariMiripDariID":          {Peran: peranBaca},
+	"PeriksaKualitas":          {Peran: peranBaca},
+	"DapatkanKandidatDuplikat": {Peran: peranBaca},
+
+	// Menambah data; batch juga diperiksa per operasi (create, update, delete)
+	"TambahkanData": {Peran: peranPenulis},
+	"ImporData":     {Peran: peranPenulis},
+	"JalankanBatch": {Peran: peranPenulis},
+
+	// Mengubah data: teknisi hanya data miliknya sendiri
+	"UbahData":         {Peran: peranKurator, PeranPemilik: peranTeknisi},
+	"UbahSebagian":     {Peran: peranKurator, PeranPemilik: peranTeknisi},
+	"KembalikanRevisi": {Peran: peranKurator, PeranPemilik: peranTeknisi},
+
+	// Kurasi: menghapus, memulihkan, menggabung, dan menindaklanjuti temuan kualitas
+	"HapusData":         {Peran: peranKurator},
+	"DaftarSampah":      {Peran: peranKurator},
+	"PulihkanData":      {Peran: peranKurator},
+	"GabungkanData":     {Peran: peranKurator},
+	"AkuiTemuan":        {Peran: peranKurator},
+	"BatalkanPengakuan": {Peran: peranKurator},
+	"PerbaikiTemuan":    {Peran: peranKurator},
+	"LatihUlangModel":   {Peran: peranKurator},
+
+	// Administrasi: hapus permanen
+	"BersihkanSampah": {Peran: peranAdmin},
+
+	// Kosakata terkontrol: semua boleh membaca, hanya admin yang mengelola
+	"DaftarKosakata":   {Peran: peranBaca},
+	"DapatkanKosakata": {Peran: peranBaca},
+	"TambahKosakata":   {Peran: peranAdmin},
+	"UbahKosakata":     {Peran: peranAdmin},
+	"HapusKosakata":    {Peran: peranAdmin},
+	"TambahAlias":      {Peran: peranAdmin},
+	"HapusAlias":       {Peran: peranAdmin},
+}
+
+// metodeBatch memetakan operasi batch ke method yang aturannya dipakai
+var metodeBatch = map[string]string{
+	domain.OperasiCreate: "TambahkanData",
+	domain.OperasiUpdate: "UbahData",
+	domain.OperasiDelete: "HapusData",
+}
+
+// operasiMetode adalah operasi riwayat yang dilakukan method per data. Untuk method ini
+// izin data sendiri (IzinPemilik) diteruskan ke service lewat domain.WithPemilikWajib.
+var operasiMetode = map[string]string{
+	"UbahData":         domain.OperasiUpdate,
+	"UbahSebagian":     domain.OperasiUpdate,
+	"KembalikanRevisi": domain.OperasiUpdate,
+	"HapusData":        domain.OperasiDelete,
+	"PulihkanData":     domain.OperasiRestore,
+}
+
+// Izin adalah hasil pemeriksaan Kebijakan untuk satu method
+type Izin int
+
+const (
+	IzinDitolak Izin = iota
+	IzinPemilik      // hanya untuk data milik pemanggil
+	IzinSemua
+)
+
+// PeriksaIzin mengembalikan izin pemanggil dengan peran tertentu atas method
+func PeriksaIzin(metode string, peran []domain.Peran) Izin {
+	aturan, ok := Kebijakan[metode]
+	if !ok {
+		return IzinDitolak
+	}
+	punya := func(p domain.Peran) bool { return slices.Contains(peran, p) }
+	switch {
+	case slices.ContainsFunc(aturan.Peran, punya):
+		return IzinSemua
+	case slices.ContainsFunc(aturan.PeranPemilik, punya):
+		return IzinPemilik
+	}
+	return IzinDitolak
+}
+
+// peranPemanggil mengembalikan peran pemanggil. Request anonim hanya sampai ke service
+// jika baca anonim diizinkan (lihat middleware bearerAuth), jadi dianggap viewer.
+func peranPemanggil(ctx context.Context) []domain.Peran {
+	if p, ok := domain.PrincipalFromContext(ctx); ok {
+		return p.Peran
+	}
+	return []domain.Peran{domain.PeranViewer}
+}
+
+// errDitolak menyusun ErrForbidden beserta peran yang dibutuhkan
+func errDitolak(metode string) error {
+	aturan := Kebijakan[metode]
+	butuh := make([]string, 0, len(aturan.Peran)+len(aturan.PeranPemilik))
+	for _, p := range aturan.Peran {
+		butuh = append(butuh, string(p))
+	}
+	for _, p := range aturan.PeranPemilik {
+		butuh = append(butuh, string(p)+" (data sendiri)")
+	}
+	if len(butuh) == 0 {
+		return fmt.Errorf("%w: %s tidak diizinkan", domain.ErrForbidden, metode)
+	}
+	return fmt.Errorf("%w: %s membutuhkan peran %s", domain.ErrForbidden, metode, strings.Join(butuh, ", "))
+}
+
+// izinkan memastikan pemanggil boleh memanggil method yang tidak terkait satu data
+func izinkan(ctx context.Context, metode string) error {
+	if PeriksaIzin(metode, peranPemanggil(ctx)) != IzinSemua {
+		return errDitolak(metode)
+	}
+	return nil
+}
+
+// izinkanData memastikan pemanggil boleh memanggil method per data (lihat operasiMetode).
+// Jika izinnya hanya untuk data sendiri, ctx yang dikembalikan membawa syarat pemilik
+// yang diperiksa service setelah data dikunci, di transaksi yang sama dengan perubahannya.
+func izinkanData(ctx context.Context, metode string) (context.Context, error) {
+	switch PeriksaIzin(metode, peranPemanggil(ctx)) {
+	case IzinSemua:
+		return ctx, nil
+	case IzinPemilik:
+		operasi, perData := operasiMetode[metode]
+		p, ok := domain.PrincipalFromContext(ctx)
+		if !perData || !ok {
+			return ctx, errDitolak(metode)
+		}
+		return domain.WithPemilikWajib(ctx, operasi, p.Subjek), nil
+	}
+	return ctx, errDitolak(metode)
+}
@@ -0,0 +1,320 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
+)
+
+// KebijakanVarietasService membungkus domain.VarietasService dan menolak panggilan yang
+// tidak diizinkan tabel Kebijakan dengan domain.ErrForbidden sebelum diteruskan.
+type KebijakanVarietasService struct {
+	next domain.VarietasService
+}
+
+// NewKebijakanVarietasService adalah constructor lapisan kebijakan akses data varietas
+func NewKebijakanVarietasService(next domain.VarietasService) domain.VarietasService {
+	return &KebijakanVarietasService{next: next}
+}
+
+func (s *KebijakanVarietasService) TambahkanData(ctx context.Context, data domain.VarietasPadi) (domain.VarietasPadi, error) {
+	if err := izinkan(ctx, "TambahkanData"); err != nil {
+		return domain.VarietasPadi{}, err
+	}
+	return s.next.TambahkanData(ctx, data)
+}
+
+func (s *KebijakanVarietasService) ImporData(ctx context.Context, baris []domain.BarisImpor, dryRun bool) (domain.HasilImpor, error) {
+	if err := izinkan(ctx, "ImporData"); err != nil {
+		return domain.HasilImpor{}, err
+	}
+	return s.next.ImporData(ctx, baris, dryRun)
+}
+
+func (s *KebijakanVarietasService) DapatkanDataByID(ctx context.Context, id int) (domain.VarietasPadi, error) {
+	if err := izinkan(ctx, "DapatkanDataByID"); err != nil {
+		return domain.VarietasPadi{}, err
+	}
+	return s.next.DapatkanDataByID(ctx, id)
+}
+
+func (s *KebijakanVarietasService) DapatkanSemuaData(ctx context.Context, q domain.VarietasQuery) (domain.VarietasPage, error) {
+	if err := izinkan(ctx, "DapatkanSemuaData"); err != nil {
+		return domain.VarietasPage{}, err
+	}
+	return s.next.DapatkanSemuaData(ctx, q)
+}
+
+func (s *KebijakanVarietasService) EksporData(ctx context.Context, q domain.VarietasQuery, fn func(domain.VarietasPadi) error) error {
+	if err := izinkan(ctx, "EksporData"); err != nil {
+		return err
+	}
+	return s.next.EksporData(ctx, q, fn)
+}
+
+func (s *KebijakanVarietasService) UbahData(ctx context.Context, data domain.VarietasPadi) (domain.VarietasPadi, error) {
+	ctx, err := izinkanData(ctx, "UbahData")
+	if err != nil {
+		return domain.VarietasPadi{}, err
+	}
+	return s.next.UbahData(ctx, data)
+}
+
+func (s *KebijakanVarietasService) HapusData(ctx context.Context, id int, versi int) error {
+	ctx, err := izinkanData(ctx, "HapusData")
+	if err != nil {
+		return err
+	}
+	return s.next.HapusData(ctx, id, versi)
+}
+
+func (s *KebijakanVarietasService) UbahSebagian(ctx context.Context, id int, patch domain.VarietasPatch) (domain.VarietasPadi, error) {
+	ctx, err := izinkanData(ctx, "UbahSebagian")
+	if err != nil {
+		return domain.VarietasPadi{}, err
+	}
+	return s.next.UbahSebagian(ctx, id, patch)
+}
+
+func (s *KebijakanVarietasService) DaftarSampah(ctx context.Context, q domain.VarietasQuery) (domain.VarietasPage, error) {
+	if err := izinkan(ctx, "DaftarSampah"); err != nil {
+		return domain.VarietasPage{}, err
+	}
+	return s.next.DaftarSampah(ctx, q)
+}
+
+func (s *KebijakanVarietasService) PulihkanData(ctx context.Context, id int) (domain.VarietasPadi, error) {
+	ctx, err := izinkanData(ctx, "PulihkanData")
+	if err != nil {
+		return domain.VarietasPadi{}, err
+	}
+	return s.next.PulihkanData(ctx, id)
+}
+
+func (s *KebijakanVarietasService) BersihkanSampah(ctx context.Context, retensi time.Duration) (int, error) {
+	if err := izinkan(ctx, "BersihkanSampah"); err != nil {
+		return 0, err
+	}
+	return s.next.BersihkanSampah(ctx, retensi)
+}
+
+func (s *KebijakanVarietasService) RiwayatData(ctx context.Context, id int) ([]domain.Revisi, error) {
+	if err := izinkan(ctx, "RiwayatData"); err != nil {
+		return nil, err
+	}
+	return s.next.RiwayatData(ctx, id)
+}
+
+func (s *KebijakanVarietasService) KembalikanRevisi(ctx context.Context, id int, revisiID int64, versi int) (domain.VarietasPadi, error) {
+	ctx, err := izinkanData(ctx, "KembalikanRevisi")
+	if err != nil {
+		return domain.VarietasPadi{}, err
+	}
+	return s.next.KembalikanRevisi(ctx, id, revisiID, versi)
+}
+
+// JalankanBatch memeriksa izin per operasi. Batch atomic ditolak seluruhnya jika satu
+// operasinya tidak diizinkan, sama seperti operasi yang gagal me-rollback batch atomic.
+// Pada batch non-atomic hanya operasi yang tidak diizinkan yang ditandai gagal dengan
+// ErrForbidden; operasi lainnya tetap dijalankan.
+func (s *KebijakanVarietasService) JalankanBatch(ctx context.Context, ops []domain.OperasiBatch, atomic bool) (domain.HasilBatch, error) {
+	if err := izinkan(ctx, "JalankanBatch"); err != nil {
+		return domain.HasilBatch{}, err
+	}
+	// Bentuk operasi diperiksa lebih dulu agar batch yang salah bentuk tetap ditolak seluruhnya
+	if err := validasiBatch(ops); err != nil {
+		return domain.HasilBatch{}, err
+	}
+
+	ditolak := make([]error, len(ops))
+	var diizinkan []domain.OperasiBatch
+	var posisi []int
+	for i, op := range ops {
+		metode := metodeBatch[op.Op]
+		var err error
+		if op.Op == domain.OperasiCreate {
+			err = izinkan(ctx, metode)
+		} else {
+			ctx, err = izinkanData(ctx, metode)
+		}
+		if err != nil {
+			if atomic {
+				return domain.HasilBatch{}, fmt.Errorf("operasi ke-%d: %w", i, err)
+			}
+			ditolak[i] = err
+			continue
+		}
+		diizinkan = append(diizinkan, op)
+		posisi = append(posisi, i)
+	}
+	if len(diizinkan) == len(ops) {
+		return s.next.JalankanBatch(ctx, ops, atomic)
+	}
+
+	// Hasil operasi yang diizinkan dikembalikan ke posisinya di request
+	hasil := domain.HasilBatch{Committed: true, Hasil: make([]domain.HasilOperasi, len(ops))}
+	if len(diizinkan) > 0 {
+		sebagian, err := s.next.JalankanBatch(ctx, diizinkan, false)
+		if err != nil {
+			return domain.HasilBatch{}, err
+		}
+		hasil = sebagian
+		hasil.Hasil = make([]domain.HasilOperasi, len(ops))
+		for j, h := range sebagian.Hasil {
+			h.Index = posisi[j]
+			hasil.Hasil[posisi[j]] = h
+		}
+	}
+	for i, op := range ops {
+		if ditolak[i] != nil {
+			hasil.Hasil[i] = domain.HasilOperasi{Index: i, Ref: op.Ref, Op: op.Op, Status: domain.StatusGagal, Err: ditolak[i]}
+			hasil.Gagal++
+		}
+	}
+	return hasil, nil
+}
+
+func (s *KebijakanVarietasService) PrediksiKelas(ctx context.Context, sampel domain.VarietasPadi, k int) (domain.HasilPrediksi, error) {
+	if err := izinkan(ctx, "PrediksiKelas"); err != nil {
+		return domain.HasilPrediksi{}, err
+	}
+	return s.next.PrediksiKelas(ctx, sampel, k)
+}
+
+func (s *KebijakanVarietasService) LatihUlangModel(ctx context.Context) (domain.InfoModel, error) {
+	if err := izinkan(ctx, "LatihUlangModel"); err != nil {
+		return domain.InfoModel{}, err
+	}
+	return s.next.LatihUlangModel(ctx)
+}
+
+func (s *KebijakanVarietasService) EvaluasiKlasifikasi(ctx context.Context, lipatan int) (domain.EvaluasiModel, error) {
+	if err := izinkan(ctx, "EvaluasiKlasifikasi"); err != nil {
+		return domain.EvaluasiModel{}, err
+	}
+	return s.next.EvaluasiKlasifikasi(ctx, lipatan)
+}
+
+func (s *KebijakanVarietasService) StatistikData(ctx context.Context, q domain.VarietasQuery, groupBy string) (domain.StatistikVarietas, error) {
+	if err := izinkan(ctx, "StatistikData"); err != nil {
+		return domain.StatistikVarietas{}, err
+	}
+	return s.next.StatistikData(ctx, q, groupBy)
+}
+
+func (s *KebijakanVarietasService) CariMirip(ctx context.Context, sampel domain.VarietasPadi, k int, bobot domain.BobotJarak) (domain.HasilKemiripan, error) {
+	if err := izinkan(ctx, "CariMirip"); err != nil {
+		return domain.HasilKemiripan{}, err
+	}
+	return s.next.CariMirip(ctx, sampel, k, bobot)
+}
+
+func (s *KebijakanVarietasService) CariMiripDariID(ctx context.Context, id int, k int, bobot domain.BobotJarak) (domain.HasilKemiripan, error) {
+	if err := izinkan(ctx, "CariMiripDariID"); err != nil {
+		return domain.HasilKemiripan{}, err
+	}
+	return s.next.CariMiripDariID(ctx, id, k, bobot)
+}
+
+func (s *KebijakanVarietasService) PeriksaKualitas(ctx context.Context, f domain.FilterKualitas) (domain.LaporanKualitas, error) {
+	if err := izinkan(ctx, "PeriksaKualitas"); err != nil {
+		return domain.LaporanKualitas{}, err
+	}
+	return s.next.PeriksaKualitas(ctx, f)
+}
+
+func (s *KebijakanVarietasService) AkuiTemuan(ctx context.Context, kode, catatan string) (domain.TemuanKualitas, error) {
+	if err := izinkan(ctx, "AkuiTemuan"); err != nil {
+		return domain.TemuanKualitas{}, err
+	}
+	return s.next.AkuiTemuan(ctx, kode, catatan)
+}
+
+func (s *KebijakanVarietasService) BatalkanPengakuan(ctx context.Context, kode string) error {
+	if err := izinkan(ctx, "BatalkanPengakuan"); err != nil {
+		return err
+	}
+	return s.next.BatalkanPengakuan(ctx, kode)
+}
+
+func (s *KebijakanVarietasService) PerbaikiTemuan(ctx context.Context, kode string, nilai any) (*domain.VarietasPadi, error) {
+	if err := izinkan(ctx, "PerbaikiTemuan"); err != nil {
+		return nil, err
+	}
+	return s.next.PerbaikiTemuan(ctx, kode, nilai)
+}
+
+func (s *KebijakanVarietasService) DapatkanKandidatDuplikat(ctx context.Context, data domain.VarietasPadi) ([]domain.VarietasPadi, error) {
+	if err := izinkan(ctx, "DapatkanKandidatDuplikat"); err != nil {
+		return nil, err
+	}
+	return s.next.DapatkanKandidatDuplikat(ctx, data)
+}
+
+func (s *KebijakanVarietasService) GabungkanData(ctx context.Context, req domain.PermintaanGabung) (domain.HasilGabung, error) {
+	if err := izinkan(ctx, "GabungkanData"); err != nil {
+		return domain.HasilGabung{}, err
+	}
+	return s.next.GabungkanData(ctx, req)
+}
+
+// KebijakanKosakataService membungkus domain.KosakataService dengan tabel Kebijakan
+type KebijakanKosakataService struct {
+	next domain.KosakataService
+}
+
+// NewKebijakanKosakataService adalah constructor lapisan kebijakan akses kosakata
+func NewKebijakanKosakataService(next domain.KosakataService) domain.KosakataService {
+	return &KebijakanKosakataService{next: next}
+}
+
+func (s *KebijakanKosakataService) DaftarKosakata(ctx context.Context, kategori string) ([]domain.Kosakata, error) {
+	if err := izinkan(ctx, "DaftarKosakata"); err != nil {
+		return nil, err
+	}
+	return s.next.DaftarKosakata(ctx, kategori)
+}
+
+func (s *KebijakanKosakataService) DapatkanKosakata(ctx context.Context, kategori string, id int) (domain.Kosakata, error) {
+	if err := izinkan(ctx, "DapatkanKosakata"); err != nil {
+		return domain.Kosakata{}, err
+	}
+	return s.next.DapatkanKosakata(ctx, kategori, id)
+}
+
+func (s *KebijakanKosakataService) TambahKosakata(ctx context.Context, data domain.Kosakata) (domain.Kosakata, error) {
+	if err := izinkan(ctx, "TambahKosakata"); err != nil {
+		return domain.Kosakata{}, err
+	}
+	return s.next.TambahKosakata(ctx, data)
+}
+
+func (s *KebijakanKosakataService) UbahKosakata(ctx context.Context, data domain.Kosakata) (domain.Kosakata, error) {
+	if err := izinkan(ctx, "UbahKosakata"); err != nil {
+		return domain.Kosakata{}, err
+	}
+	return s.next.UbahKosakata(ctx, data)
+}
+
+func (s *KebijakanKosakataService) HapusKosakata(ctx context.Context, kategori string, id int) error {
+	if err := izinkan(ctx, "HapusKosakata"); err != nil {
+		return err
+	}
+	return s.next.HapusKosakata(ctx, kategori, id)
+}
+
+func (s *KebijakanKosakataService) TambahAlias(ctx context.Context, kategori string, id int, alias string) (domain.Kosakata, error) {
+	if err := izinkan(ctx, "TambahAlias"); err != nil {
+		return domain.Kosakata{}, err
+	}
+	return s.next.TambahAlias(ctx, kategori, id, alias)
+}
+
+func (s *KebijakanKosakataService) HapusAlias(ctx context.Context, kategori string, id int, alias string) error {
+	if err := izinkan(ctx, "HapusAlias"); err != nil {
+		return err
+	}
+	return s.next.HapusAlias(ctx, kategori, id, alias)
+}
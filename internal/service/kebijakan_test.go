@@ -0,0 +1,275 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
+)
+
+// izinPerPeran adalah izin yang diharapkan untuk viewer, technician, curator, dan admin
+type izinPerPeran [4]Izin
+
+var (
+	harapBaca      = izinPerPeran{IzinSemua, IzinSemua, IzinSemua, IzinSemua}
+	harapPenulis   = izinPerPeran{IzinDitolak, IzinSemua, IzinSemua, IzinSemua}
+	harapMilik     = izinPerPeran{IzinDitolak, IzinPemilik, IzinSemua, IzinSemua}
+	harapKurator   = izinPerPeran{IzinDitolak, IzinDitolak, IzinSemua, IzinSemua}
+	harapAdmin     = izinPerPeran{IzinDitolak, IzinDitolak, IzinDitolak, IzinSemua}
+	peranDiperiksa = [4]domain.Peran{domain.PeranViewer, domain.PeranTechnician, domain.PeranCurator, domain.PeranAdmin}
+)
+
+// harapanKebijakan ditulis terpisah dari Kebijakan agar perubahan izin yang tidak disengaja
+// membuat test gagal
+var harapanKebijakan = map[string]izinPerPeran{
+	"DapatkanDataByID":         harapBaca,
+	"DapatkanSemuaData":        harapBaca,
+	"EksporData":               harapBaca,
+	"RiwayatData":              harapBaca,
+	"PrediksiKelas":            harapBaca,
+	"EvaluasiKlasifikasi":      harapBaca,
+	"StatistikData":            harapBaca,
+	"CariMirip":                harapBaca,
+	"CariMiripDariID":          harapBaca,
+	"PeriksaKualitas":          harapBaca,
+	"DapatkanKandidatDuplikat": harapBaca,
+
+	"TambahkanData": harapPenulis,
+	"ImporData":     harapPenulis,
+	"JalankanBatch": harapPenulis,
+
+	"UbahData":         harapMilik,
+	"UbahSebagian":     harapMilik,
+	"KembalikanRevisi": harapMilik,
+
+	"HapusData":         harapKurator,
+	"DaftarSampah":      harapKurator,
+	"PulihkanData":      harapKurator,
+	"GabungkanData":     harapKurator,
+	"AkuiTemuan":        harapKurator,
+	"BatalkanPengakuan": harapKurator,
+	"PerbaikiTemuan":    harapKurator,
+	"LatihUlangModel":   harapKurator,
+
+	"BersihkanSampah": harapAdmin,
+
+	"DaftarKosakata":   harapBaca,
+	"DapatkanKosakata": harapBaca,
+	"TambahKosakata":   harapAdmin,
+	"UbahKosakata":     harapAdmin,
+	"HapusKosakata":    harapAdmin,
+	"TambahAlias":      harapAdmin,
+	"HapusAlias":       harapAdmin,
+}
+
+func TestPeriksaIzin(t *testing.T) {
+	for metode := range Kebijakan {
+		if _, ok := harapanKebijakan[metode]; !ok {
+			t.Errorf("Kebijakan[%q] tidak punya harapan di test", metode)
+		}
+	}
+
+	for metode, harap := range harapanKebijakan {
+		for i, peran := range peranDiperiksa {
+			t.Run(metode+"/"+string(peran), func(t *testing.T) {
+				if got := PeriksaIzin(metode, []domain.Peran{peran}); got != harap[i] {
+					t.Errorf("PeriksaIzin(%q, %s) = %v, ingin %v", metode, peran, got, harap[i])
+				}
+			})
+		}
+	}
+}
+
+func TestPeriksaIzinKasusKhusus(t *testing.T) {
+	tests := []struct {
+		nama   string
+		metode string
+		peran  []domain.Peran
+		want   Izin
+	}{
+		{"tanpa peran", "DapatkanDataByID", nil, IzinDitolak},
+		{"peran tidak dikenal", "DapatkanDataByID", []domain.Peran{"tamu"}, IzinDitolak},
+		{"method tidak terdaftar", "MethodBaru", domain.SemuaPeran, IzinDitolak},
+		{"izin penuh mengalahkan izin pemilik", "UbahData", []domain.Peran{domain.PeranTechnician, domain.PeranCurator}, IzinSemua},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nama, func(t *testing.T) {
+			if got := PeriksaIzin(tt.metode, tt.peran); got != tt.want {
+				t.Errorf("PeriksaIzin(%q, %v) = %v, ingin %v", tt.metode, tt.peran, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestKebijakanMencakupSemuaMethod memastikan method baru di interface service tidak
+// terlewat dari Kebijakan (method yang tidak terdaftar selalu ditolak)
+func TestKebijakanMencakupSemuaMethod(t *testing.T) {
+	for _, iface := range []reflect.Type{
+		reflect.TypeOf((*domain.VarietasService)(nil)).Elem(),
+		reflect.TypeOf((*domain.KosakataService)(nil)).Elem(),
+	} {
+		for i := range iface.NumMethod() {
+			metode := iface.Method(i).Name
+			if _, ok := Kebijakan[metode]; !ok {
+				t.Errorf("%s.%s tidak punya aturan di Kebijakan", iface.Name(), metode)
+			}
+		}
+	}
+}
+
+// --- Data sendiri (IzinPemilik) ---
+
+func ctxPeran(subjek string, peran ...domain.Peran) context.Context {
+	return domain.WithPrincipal(context.Background(), domain.Principal{Subjek: subjek, Peran: peran})
+}
+
+func newServiceKebijakan() domain.VarietasService {
+	return NewKebijakanVarietasService(newServiceUji(domain.ModeDuplikatNonaktif))
+}
+
+func contohVarietas() domain.VarietasPadi {
+	return domain.VarietasPadi{
+		VarietasKelas:    "IR64",
+		Warna:            "Putih",
+		PanjangBijiMM:    6.5,
+		TeksturPermukaan: "Halus",
+		BentukUjungDaun:  "Runcing",
+	}
+}
+
+func TestTeknisiHanyaMengubahDataSendiri(t *testing.T) {
+	pemilik := ctxPeran("tek1", domain.PeranTechnician)
+	orangLain := ctxPeran("tek2", domain.PeranTechnician)
+	kurator := ctxPeran("kur1", domain.PeranCurator)
+
+	panjang := 7.0
+	ubah := []struct {
+		nama string
+		fn   func(ctx context.Context, svc domain.VarietasService, id int) error
+	}{
+		{"UbahData", func(ctx context.Context, svc domain.VarietasService, id int) error {
+			data := contohVarietas()
+			data.ID = id
+			_, err := svc.UbahData(ctx, data)
+			return err
+		}},
+		{"UbahSebagian", func(ctx context.Context, svc domain.VarietasService, id int) error {
+			_, err := svc.UbahSebagian(ctx, id, domain.VarietasPatch{PanjangBijiMM: &panjang})
+			return err
+		}},
+		{"KembalikanRevisi", func(ctx context.Context, svc domain.VarietasService, id int) error {
+			revisi, err := svc.RiwayatData(ctx, id)
+			if err != nil {
+				return err
+			}
+			_, err = svc.KembalikanRevisi(ctx, id, revisi[len(revisi)-1].ID, 0)
+			return err
+		}},
+	}
+
+	for _, u := range ubah {
+		tests := []struct {
+			nama    string
+			ctx     context.Context
+			ditolak bool
+		}{
+			{"pemilik", pemilik, false},
+			{"teknisi lain", orangLain, true},
+			{"kurator", kurator, false},
+		}
+		for _, tt := range tests {
+			t.Run(u.nama+"/"+tt.nama, func(t *testing.T) {
+				svc := newServiceKebijakan()
+				created, err := svc.TambahkanData(pemilik, contohVarietas())
+				if err != nil {
+					t.Fatalf("TambahkanData: %v", err)
+				}
+				if created.DibuatOleh != "tek1" {
+					t.Fatalf("DibuatOleh = %q, ingin %q", created.DibuatOleh, "tek1")
+				}
+
+				err = u.fn(tt.ctx, svc, created.ID)
+				if tt.ditolak {
+					if !errors.Is(err, domain.ErrForbidden) {
+						t.Fatalf("error = %v, ingin ErrForbidden", err)
+					}
+					got, err := svc.DapatkanDataByID(kurator, created.ID)
+					if err != nil {
+						t.Fatalf("DapatkanDataByID: %v", err)
+					}
+					if got.Versi != created.Versi {
+						t.Errorf("data berubah walau ditolak: versi %d, ingin %d", got.Versi, created.Versi)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("error = %v, ingin nil", err)
+				}
+			})
+		}
+	}
+}
+
+func TestJalankanBatchOperasiDitolak(t *testing.T) {
+	teknisi := ctxPeran("tek1", domain.PeranTechnician)
+	kurator := ctxPeran("kur1", domain.PeranCurator)
+
+	siapkan := func(t *testing.T) (domain.VarietasService, []domain.OperasiBatch) {
+		t.Helper()
+		svc := newServiceKebijakan()
+		milikKurator, err := svc.TambahkanData(kurator, contohVarietas())
+		if err != nil {
+			t.Fatalf("TambahkanData: %v", err)
+		}
+		milikTeknisi, err := svc.TambahkanData(teknisi, contohVarietas())
+		if err != nil {
+			t.Fatalf("TambahkanData: %v", err)
+		}
+		data := contohVarietas()
+		return svc, []domain.OperasiBatch{
+			{Op: domain.OperasiCreate, Ref: "baru", Data: &data},
+			{Op: domain.OperasiDelete, Ref: "hapus", ID: milikTeknisi.ID},             // teknisi tidak boleh menghapus
+			{Op: domain.OperasiUpdate, Ref: "lain", ID: milikKurator.ID, Data: &data}, // bukan miliknya
+			{Op: domain.OperasiUpdate, Ref: "sendiri", ID: milikTeknisi.ID, Data: &data},
+		}
+	}
+
+	t.Run("non-atomic", func(t *testing.T) {
+		svc, ops := siapkan(t)
+		hasil, err := svc.JalankanBatch(teknisi, ops, false)
+		if err != nil {
+			t.Fatalf("JalankanBatch: %v", err)
+		}
+		if !hasil.Committed || hasil.Berhasil != 2 || hasil.Gagal != 2 {
+			t.Errorf("committed=%v berhasil=%d gagal=%d, ingin true/2/2", hasil.Committed, hasil.Berhasil, hasil.Gagal)
+		}
+		want := []string{domain.StatusBerhasil, domain.StatusGagal, domain.StatusGagal, domain.StatusBerhasil}
+		for i, h := range hasil.Hasil {
+			if h.Index != i || h.Ref != ops[i].Ref {
+				t.Errorf("hasil[%d]: index=%d ref=%q, ingin %d/%q", i, h.Index, h.Ref, i, ops[i].Ref)
+			}
+			if h.Status != want[i] {
+				t.Errorf("hasil[%d].Status = %q, ingin %q", i, h.Status, want[i])
+			}
+			if h.Status == domain.StatusGagal && !errors.Is(h.Err, domain.ErrForbidden) {
+				t.Errorf("hasil[%d].Err = %v, ingin ErrForbidden", i, h.Err)
+			}
+		}
+	})
+
+	t.Run("atomic", func(t *testing.T) {
+		svc, ops := siapkan(t)
+		if _, err := svc.JalankanBatch(teknisi, ops, true); !errors.Is(err, domain.ErrForbidden) {
+			t.Fatalf("error = %v, ingin ErrForbidden", err)
+		}
+		semua, err := svc.DapatkanSemuaData(kurator, domain.VarietasQuery{})
+		if err != nil {
+			t.Fatalf("DapatkanSemuaData: %v", err)
+		}
+		if len(semua.Data) != 2 {
+			t.Errorf("jumlah data = %d, ingin 2 (batch tidak dijalankan)", len(semua.Data))
+		}
+	})
+}
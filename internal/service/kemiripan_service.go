@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/klasifikasi"
+)
+
+// MaksTetangga membatasi k pada pencarian data termirip
+const MaksTetangga = 100
+
+// validasiKemiripan memeriksa k dan bobot jarak
+func validasiKemiripan(k int, bobot domain.BobotJarak) error {
+	if k < 1 || k > MaksTetangga {
+		return domain.NewValidationError("jumlah tetangga tidak valid",
+			domain.FieldError{Field: "k", Code: "range", Message: fmt.Sprintf("harus antara 1 dan %d", MaksTetangga)})
+	}
+	return bobot.Validate()
+}
+
+// CariMirip memvalidasi ciri morfologi sampel dengan aturan yang sama seperti prediksi,
+// lalu membandingkannya dengan semua data aktif
+func (s *VarietasService) CariMirip(ctx context.Context, sampel domain.VarietasPadi, k int, bobot domain.BobotJarak) (domain.HasilKemiripan, error) {
+	if err := validasiKemiripan(k, bobot); err != nil {
+		return domain.HasilKemiripan{}, err
+	}
+	sampel.ID, sampel.VarietasKelas = 0, ""
+	sampel, err := s.validasiDengan(ctx, sampel, s.validatorFitur, s.kosakata.Resolve)
+	if err != nil {
+		return domain.HasilKemiripan{}, err
+	}
+	return s.tetanggaTerdekat(ctx, sampel, k, bobot)
+}
+
+// CariMiripDariID membandingkan data id dengan semua data aktif lainnya
+func (s *VarietasService) CariMiripDariID(ctx context.Context, id int, k int, bobot domain.BobotJarak) (domain.HasilKemiripan, error) {
+	if err := validasiKemiripan(k, bobot); err != nil {
+		return domain.HasilKemiripan{}, err
+	}
+	acuan, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return domain.HasilKemiripan{}, wrapRepoError(err, fmt.Sprintf("gagal mengambil varietas id %d", id))
+	}
+	return s.tetanggaTerdekat(ctx, acuan, k, bobot)
+}
+
+func (s *VarietasService) tetanggaTerdekat(ctx context.Context, acuan domain.VarietasPadi, k int, bobot domain.BobotJarak) (domain.HasilKemiripan, error) {
+	data, err := s.repo.FindAll(ctx)
+	if err != nil {
+		return domain.HasilKemiripan{}, wrapRepoError(err, "gagal mengambil data untuk pencarian kemiripan")
+	}
+	return domain.HasilKemiripan{Bobot: bobot, Tetangga: klasifikasi.Terdekat(acuan, data, bobot, k)}, nil
+}
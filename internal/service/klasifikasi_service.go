@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/klasifikasi"
+)
+
+// MaksKandidatPrediksi membatasi k pada PrediksiKelas
+const MaksKandidatPrediksi = 20
+
+// modelKlasifikasi menyimpan model terlatih bersama generasi data saat model dilatih.
+// Setiap perubahan data yang sudah di-commit menaikkan generasi, sehingga prediksi
+// berikutnya melatih ulang model dari data terbaru.
+type modelKlasifikasi struct {
+	generasi atomic.Uint64
+
+	mu           sync.Mutex // satu pelatihan dalam satu waktu
+	model        *klasifikasi.Model
+	info         domain.InfoModel
+	dariGenerasi uint64
+}
+
+// usang menandai model perlu dilatih ulang. Dipanggil setelah transaksi commit agar
+// pelatihan tidak membaca data sebelum perubahan lalu menganggap dirinya terbaru.
+func (m *modelKlasifikasi) usang() {
+	m.generasi.Add(1)
+}
+
+// modelTerbaru mengembalikan model yang sesuai dengan data saat ini, melatih ulang jika
+// data sudah berubah sejak pelatihan terakhir atau jika paksa
+func (s *VarietasService) modelTerbaru(ctx context.Context, paksa bool) (*klasifikasi.Model, domain.InfoModel, error) {
+	m := s.model
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Generasi dibaca sebelum FindAll: perubahan yang commit di tengah pelatihan
+	// menaikkan generasi lagi sehingga model ini tetap dianggap usang
+	generasi := m.generasi.Load()
+	if !paksa && m.model != nil && m.dariGenerasi == generasi {
+		return m.model, m.info, nil
+	}
+
+	data, err := s.repo.FindAll(ctx)
+	if err != nil {
+		return nil, domain.InfoModel{}, wrapRepoError(err, "gagal mengambil data untuk melatih model")
+	}
+	model, err := klasifikasi.Latih(data)
+	if errors.Is(err, klasifikasi.ErrDataKosong) {
+		return nil, domain.InfoModel{}, fmt.Errorf("%w: %v", domain.ErrConflict, err)
+	}
+	if err != nil {
+		return nil, domain.InfoModel{}, fmt.Errorf("gagal melatih model klasifikasi: %w", err)
+	}
+
+	m.model, m.dariGenerasi = model, generasi
+	m.info = domain.InfoModel{
+		Algoritma:   klasifikasi.Algoritma,
+		JumlahData:  model.JumlahData(),
+		JumlahKelas: len(model.Kelas()),
+		DilatihPada: time.Now(),
+	}
+	return m.model, m.info, nil
+}
+
+// PrediksiKelas memvalidasi ciri morfologi sampel dengan aturan yang sama seperti create
+// (termasuk kosakata terkontrol), lalu mengembalikan k kelas dengan confidence tertinggi
+func (s *VarietasService) PrediksiKelas(ctx context.Context, sampel domain.VarietasPadi, k int) (domain.HasilPrediksi, error) {
+	if k < 1 || k > MaksKandidatPrediksi {
+		return domain.HasilPrediksi{}, domain.NewValidationError("jumlah kandidat tidak valid",
+			domain.FieldError{Field: "k", Code: "range", Message: fmt.Sprintf("harus antara 1 dan %d", MaksKandidatPrediksi)})
+	}
+	sampel.VarietasKelas = ""
+	sampel, err := s.validasiDengan(ctx, sampel, s.validatorFitur, s.kosakata.Resolve)
+	if err != nil {
+		return domain.HasilPrediksi{}, err
+	}
+
+	model, info, err := s.modelTerbaru(ctx, false)
+	if err != nil {
+		return domain.HasilPrediksi{}, err
+	}
+	prediksi := model.Prediksi(sampel)
+	if len(prediksi) > k {
+		prediksi = prediksi[:k]
+	}
+	return domain.HasilPrediksi{Prediksi: prediksi, Model: info}, nil
+}
+
+// LatihUlangModel melatih ulang model saat itu juga, misalnya setelah ejaan kosakata
+// diganti (perubahan lewat ON UPDATE CASCADE tidak menaikkan generasi data)
+func (s *VarietasService) LatihUlangModel(ctx context.Context) (domain.InfoModel, error) {
+	_, info, err := s.modelTerbaru(ctx, true)
+	return info, err
+}
+
+// Batas jumlah lipatan cross-validation
+const (
+	MinLipatanEvaluasi  = 2
+	MaksLipatanEvaluasi = 20
+)
+
+// EvaluasiKlasifikasi menjalankan stratified k-fold cross-validation atas semua data aktif.
+// Model yang dipakai prediksi tidak ikut berubah.
+func (s *VarietasService) EvaluasiKlasifikasi(ctx context.Context, lipatan int) (domain.EvaluasiModel, error) {
+	if lipatan < MinLipatanEvaluasi || lipatan > MaksLipatanEvaluasi {
+		return domain.EvaluasiModel{}, domain.NewValidationError("jumlah lipatan tidak valid",
+			domain.FieldError{Field: "folds", Code: "range",
+				Message: fmt.Sprintf("harus antara %d dan %d", MinLipatanEvaluasi, MaksLipatanEvaluasi)})
+	}
+
+	data, err := s.repo.FindAll(ctx)
+	if err != nil {
+		return domain.EvaluasiModel{}, wrapRepoError(err, "gagal mengambil data untuk evaluasi model")
+	}
+	if len(data) < lipatan {
+		return domain.EvaluasiModel{}, fmt.Errorf("%w: butuh minimal %d data untuk %d lipatan, baru ada %d",
+			domain.ErrConflict, lipatan, lipatan, len(data))
+	}
+
+	hasil, err := klasifikasi.Evaluasi(data, lipatan)
+	if errors.Is(err, klasifikasi.ErrDataKosong) {
+		return domain.EvaluasiModel{}, fmt.Errorf("%w: %v", domain.ErrConflict, err)
+	}
+	if err != nil {
+		return domain.EvaluasiModel{}, fmt.Errorf("gagal mengevaluasi model klasifikasi: %w", err)
+	}
+	hasil.DievaluasiPada = time.Now()
+	return hasil, nil
+}
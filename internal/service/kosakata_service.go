@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
+)
+
+// KosakataService adalah implementasi domain.KosakataService
+type KosakataService struct {
+	repo domain.KosakataRepository
+}
+
+// NewKosakataService adalah constructor Service kosakata terkontrol
+func NewKosakataService(repo domain.KosakataRepository) domain.KosakataService {
+	return &KosakataService{repo: repo}
+}
+
+// validasiTeks merapikan spasi lalu memastikan teks tidak kosong dan maksimal 50 karakter
+func validasiTeks(field, value string) (string, error) {
+	value = domain.CollapseSpaces(value)
+	switch {
+	case value == "":
+		return "", domain.NewValidationError("data kosakata tidak valid",
+			domain.FieldError{Field: field, Code: "required", Message: "wajib diisi"})
+	case utf8.RuneCountInString(value) > 50:
+		return "", domain.NewValidationError("data kosakata tidak valid",
+			domain.FieldError{Field: field, Code: "max_length", Message: "maksimal 50 karakter"})
+	}
+	return value, nil
+}
+
+// pastikanBelumDikenal menolak teks yang sudah dikenal sebagai nilai baku atau alias di
+// kategori, agar Resolve tidak ambigu. Teks yang dikenal sebagai nilai baku milik (milik
+// kosakata yang sedang diubah) diperbolehkan; isi milik dengan "" untuk kosakata baru.
+func (s *KosakataService) pastikanBelumDikenal(ctx context.Context, kategori, teks, milik string) error {
+	existing, err := s.repo.Resolve(ctx, kategori, teks)
+	switch {
+	case errors.Is(err, sql.ErrNoRows), errors.Is(err, domain.ErrNotFound):
+		return nil
+	case err != nil:
+		return wrapRepoError(err, "gagal memeriksa kosakata "+kategori)
+	case milik != "" && existing == milik:
+		return nil
+	}
+	return fmt.Errorf("%w: '%s' sudah dikenal sebagai '%s'", domain.ErrConflict, teks, existing)
+}
+
+func (s *KosakataService) DaftarKosakata(ctx context.Context, kategori string) ([]domain.Kosakata, error) {
+	data, err := s.repo.FindAll(ctx, kategori)
+	if err != nil {
+		return nil, wrapRepoError(err, "gagal mengambil kosakata "+kategori)
+	}
+	return data, nil
+}
+
+func (s *KosakataService) DapatkanKosakata(ctx context.Context, kategori string, id int) (domain.Kosakata, error) {
+	data, err := s.repo.FindByID(ctx, kategori, id)
+	if err != nil {
+		return domain.Kosakata{}, wrapRepoError(err, fmt.Sprintf("gagal mengambil kosakata %s id %d", kategori, id))
+	}
+	return data, nil
+}
+
+func (s *KosakataService) TambahKosakata(ctx context.Context, data domain.Kosakata) (domain.Kosakata, error) {
+	nilai, err := validasiTeks("nilai", data.Nilai)
+	if err != nil {
+		return domain.Kosakata{}, err
+	}
+	data.Nilai = nilai
+
+	// Nilai baru tidak boleh sama dengan nilai baku atau alias yang sudah ada
+	if err := s.pastikanBelumDikenal(ctx, data.Kategori, nilai, ""); err != nil {
+		return domain.Kosakata{}, err
+	}
+
+	created, err := s.repo.Create(ctx, data)
+	if err != nil {
+		return domain.Kosakata{}, wrapRepoError(err, "gagal menyimpan kosakata "+data.Kategori)
+	}
+	return created, nil
+}
+
+func (s *KosakataService) UbahKosakata(ctx context.Context, data domain.Kosakata) (domain.Kosakata, error) {
+	nilai, err := validasiTeks("nilai", data.Nilai)
+	if err != nil {
+		return domain.Kosakata{}, err
+	}
+	data.Nilai = nilai
+
+	// Nilai boleh tetap (atau hanya berubah huruf besar/kecil) atau sama dengan alias miliknya
+	// sendiri, tetapi tidak boleh sama dengan nilai baku atau alias kosakata lain
+	lama, err := s.repo.FindByID(ctx, data.Kategori, data.ID)
+	if err != nil {
+		return domain.Kosakata{}, wrapRepoError(err, fmt.Sprintf("gagal mengambil kosakata %s id %d", data.Kategori, data.ID))
+	}
+	if err := s.pastikanBelumDikenal(ctx, data.Kategori, nilai, lama.Nilai); err != nil {
+		return domain.Kosakata{}, err
+	}
+
+	updated, err := s.repo.Update(ctx, data)
+	if err != nil {
+		return domain.Kosakata{}, wrapRepoError(err, fmt.Sprintf("gagal mengubah kosakata %s id %d", data.Kategori, data.ID))
+	}
+	return updated, nil
+}
+
+func (s *KosakataService) HapusKosakata(ctx context.Context, kategori string, id int) error {
+	err := s.repo.Delete(ctx, kategori, id)
+	return wrapRepoError(err, fmt.Sprintf("gagal menghapus kosakata %s id %d", kategori, id))
+}
+
+func (s *KosakataService) TambahAlias(ctx context.Context, kategori string, id int, alias string) (domain.Kosakata, error) {
+	alias, err := validasiTeks("alias", alias)
+	if err != nil {
+		return domain.Kosakata{}, err
+	}
+	alias = strings.ToLower(alias)
+
+	// Alias yang sudah dikenal (sebagai nilai baku atau alias lain) akan membuat Resolve ambigu
+	if err := s.pastikanBelumDikenal(ctx, kategori, alias, ""); err != nil {
+		return domain.Kosakata{}, err
+	}
+
+	updated, err := s.repo.AddAlias(ctx, kategori, id, alias)
+	if err != nil {
+		return domain.Kosakata{}, wrapRepoError(err, fmt.Sprintf("gagal menambah alias kosakata %s id %d", kategori, id))
+	}
+	return updated, nil
+}
+
+func (s *KosakataService) HapusAlias(ctx context.Context, kategori string, id int, alias string) error {
+	err := s.repo.RemoveAlias(ctx, kategori, id, domain.CollapseSpaces(alias))
+	return wrapRepoError(err, fmt.Sprintf("gagal menghapus alias '%s' dari kosakata %s id %d", alias, kategori, id))
+}
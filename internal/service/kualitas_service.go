@@ -0,0 +1,427 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
+)
+
+// Parameter pemeriksaan kualitas data
+const (
+	// BatasSkorZRobust: |0.6745 * (x - median) / MAD| di atas batas ini dianggap outlier
+	// (Iglewicz & Hoaglin)
+	BatasSkorZRobust = 3.5
+	// FaktorIQR: nilai di luar [Q1 - 1.5 IQR, Q3 + 1.5 IQR] dianggap outlier (pagar Tukey)
+	FaktorIQR = 1.5
+	// MinDataOutlier adalah jumlah data minimal satu varietas_kelas agar outlier-nya dicari
+	MinDataOutlier = 5
+	// MinDataLangka adalah jumlah data minimal agar nilai langka dicari
+	MinDataLangka = 20
+	// ProporsiLangka: nilai yang dipakai tidak lebih dari 1% data (minimal 1 data) dianggap langka
+	ProporsiLangka = 0.01
+	// MaksCatatanPengakuan membatasi panjang catatan reviewer
+	MaksCatatanPengakuan = 500
+)
+
+// fieldLangka adalah field kategorikal yang diperiksa nilai langkanya
+var fieldLangka = []string{"varietas_kelas", "warna", "tekstur_permukaan", "bentuk_ujung_daun"}
+
+// PeriksaKualitas menghitung semua temuan kualitas dari data aktif saat ini, lalu
+// menandai temuan yang sudah diakui reviewer pada versi data yang sama
+func (s *VarietasService) PeriksaKualitas(ctx context.Context, f domain.FilterKualitas) (domain.LaporanKualitas, error) {
+	var fields []domain.FieldError
+	if f.Jenis != "" && !slices.Contains(domain.JenisTemuan, f.Jenis) {
+		fields = append(fields, domain.FieldError{Field: "jenis", Code: "one_of",
+			Message: "harus salah satu dari: " + strings.Join(domain.JenisTemuan, ", ")})
+	}
+	if f.Status != "" && f.Status != domain.StatusTemuanTerbuka && f.Status != domain.StatusTemuanDiakui {
+		fields = append(fields, domain.FieldError{Field: "status", Code: "one_of",
+			Message: fmt.Sprintf("harus %s atau %s", domain.StatusTemuanTerbuka, domain.StatusTemuanDiakui)})
+	}
+	if len(fields) > 0 {
+		return domain.LaporanKualitas{}, domain.NewValidationError("parameter pemeriksaan kualitas tidak valid", fields...)
+	}
+
+	temuan, jumlahData, err := s.hitungTemuan(ctx)
+	if err != nil {
+		return domain.LaporanKualitas{}, err
+	}
+
+	laporan := domain.LaporanKualitas{JumlahData: jumlahData, Temuan: []domain.TemuanKualitas{}, DiperiksaPada: time.Now()}
+	for _, t := range temuan {
+		if t.Status == domain.StatusTemuanDiakui {
+			laporan.Diakui++
+		} else {
+			laporan.Terbuka++
+		}
+		if (f.Jenis == "" || t.Jenis == f.Jenis) && (f.Status == "" || t.Status == f.Status) {
+			laporan.Temuan = append(laporan.Temuan, t)
+		}
+	}
+	return laporan, nil
+}
+
+// AkuiTemuan mencatat bahwa reviewer sudah memeriksa temuan dan datanya memang benar.
+// Pengakuan berlaku sampai data berubah.
+func (s *VarietasService) AkuiTemuan(ctx context.Context, kode, catatan string) (domain.TemuanKualitas, error) {
+	if utf8.RuneCountInString(catatan) > MaksCatatanPengakuan {
+		return domain.TemuanKualitas{}, domain.NewValidationError("catatan pengakuan tidak valid",
+			domain.FieldError{Field: "catatan", Code: "max_length", Message: fmt.Sprintf("maksimal %d karakter", MaksCatatanPengakuan)})
+	}
+	t, err := s.cariTemuan(ctx, kode)
+	if err != nil {
+		return domain.TemuanKualitas{}, err
+	}
+
+	p, err := s.kualitas.Save(ctx, domain.PengakuanKualitas{
+		Kode:      t.Kode,
+		IDPadi:    t.IDPadi,
+		VersiData: t.Versi,
+		Catatan:   strings.TrimSpace(catatan),
+		Aktor:     domain.ActorFromContext(ctx),
+	})
+	if err != nil {
+		return domain.TemuanKualitas{}, wrapRepoError(err, fmt.Sprintf("gagal menyimpan pengakuan temuan %s", kode))
+	}
+	t.Status, t.Pengakuan = domain.StatusTemuanDiakui, &p
+	return t, nil
+}
+
+// BatalkanPengakuan membuka kembali temuan yang sudah diakui
+func (s *VarietasService) BatalkanPengakuan(ctx context.Context, kode string) error {
+	return wrapRepoError(s.kualitas.Delete(ctx, kode), fmt.Sprintf("gagal membatalkan pengakuan temuan %s", kode))
+}
+
+// PerbaikiTemuan memperbaiki data penyebab temuan lewat jalur perubahan biasa (validasi,
+// riwayat, dan cek versi): outlier dan nilai langka diganti nilai (atau saran jika nilai
+// kosong), duplikat dipindahkan ke tempat sampah. Mengembalikan data setelah diperbaiki,
+// atau nil untuk duplikat.
+func (s *VarietasService) PerbaikiTemuan(ctx context.Context, kode string, nilai any) (*domain.VarietasPadi, error) {
+	t, err := s.cariTemuan(ctx, kode)
+	if err != nil {
+		return nil, err
+	}
+	ctx = domain.WithKeterangan(ctx, "perbaikan temuan kualitas "+t.Kode)
+
+	if t.Jenis == domain.TemuanDuplikat {
+		return nil, s.HapusData(ctx, t.IDPadi, t.Versi)
+	}
+
+	if nilai == nil {
+		nilai = t.Saran
+	}
+	if nilai == nil {
+		return nil, domain.NewValidationError("nilai perbaikan tidak valid",
+			domain.FieldError{Field: "nilai", Code: "required", Message: "wajib diisi karena temuan ini tidak punya saran"})
+	}
+
+	patch := domain.VarietasPatch{Versi: t.Versi}
+	if t.Field == "panjang_biji_mm" {
+		n, ok := nilai.(float64)
+		if !ok {
+			return nil, domain.NewValidationError("nilai perbaikan tidak valid",
+				domain.FieldError{Field: "nilai", Code: "type", Message: "harus bertipe angka"})
+		}
+		patch.PanjangBijiMM = &n
+	} else {
+		teks, ok := nilai.(string)
+		if !ok {
+			return nil, domain.NewValidationError("nilai perbaikan tidak valid",
+				domain.FieldError{Field: "nilai", Code: "type", Message: "harus bertipe string"})
+		}
+		switch t.Field {
+		case "varietas_kelas":
+			patch.VarietasKelas = &teks
+		case "warna":
+			patch.Warna = &teks
+		case "tekstur_permukaan":
+			patch.TeksturPermukaan = &teks
+		case "bentuk_ujung_daun":
+			patch.BentukUjungDaun = &teks
+		}
+	}
+
+	updated, err := s.UbahSebagian(ctx, t.IDPadi, patch)
+	if err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// cariTemuan menghitung ulang temuan lalu mengambil satu temuan berdasarkan kodenya
+func (s *VarietasService) cariTemuan(ctx context.Context, kode string) (domain.TemuanKualitas, error) {
+	temuan, _, err := s.hitungTemuan(ctx)
+	if err != nil {
+		return domain.TemuanKualitas{}, err
+	}
+	for _, t := range temuan {
+		if t.Kode == kode {
+			return t, nil
+		}
+	}
+	return domain.TemuanKualitas{}, fmt.Errorf("temuan kualitas '%s' tidak ada (mungkin sudah diperbaiki): %w", kode, domain.ErrNotFound)
+}
+
+// hitungTemuan menjalankan semua pemeriksaan atas data aktif. Temuan terurut menurut
+// ID data lalu jenisnya.
+func (s *VarietasService) hitungTemuan(ctx context.Context) ([]domain.TemuanKualitas, int, error) {
+	data, err := s.repo.FindAll(ctx)
+	if err != nil {
+		return nil, 0, wrapRepoError(err, "gagal mengambil data untuk pemeriksaan kualitas")
+	}
+	daftarPengakuan, err := s.kualitas.FindAll(ctx)
+	if err != nil {
+		return nil, 0, wrapRepoError(err, "gagal mengambil pengakuan temuan kualitas")
+	}
+	pengakuan := make(map[string]domain.PengakuanKualitas, len(daftarPengakuan))
+	for _, p := range daftarPengakuan {
+		pengakuan[p.Kode] = p
+	}
+
+	sort.Slice(data, func(i, j int) bool { return data[i].ID < data[j].ID })
+	temuan := append(append(outlierPanjang(data), duplikat(data)...), nilaiLangka(data)...)
+
+	for i := range temuan {
+		t := &temuan[i]
+		t.Status = domain.StatusTemuanTerbuka
+		// Pengakuan untuk versi lama tidak berlaku: datanya sudah berubah sejak ditinjau
+		if p, ok := pengakuan[t.Kode]; ok && p.VersiData == t.Versi {
+			t.Status, t.Pengakuan = domain.StatusTemuanDiakui, &p
+		}
+	}
+	sort.SliceStable(temuan, func(i, j int) bool {
+		if temuan[i].IDPadi != temuan[j].IDPadi {
+			return temuan[i].IDPadi < temuan[j].IDPadi
+		}
+		return slices.Index(domain.JenisTemuan, temuan[i].Jenis) < slices.Index(domain.JenisTemuan, temuan[j].Jenis)
+	})
+	return temuan, len(data), nil
+}
+
+// outlierPanjang mencari panjang biji yang menyimpang di dalam varietas_kelas-nya dengan
+// skor z robust (median dan MAD) dan pagar IQR
+func outlierPanjang(data []domain.VarietasPadi) []domain.TemuanKualitas {
+	grup := map[string][]domain.VarietasPadi{}
+	var kelas []string
+	for _, v := range data {
+		if _, ok := grup[v.VarietasKelas]; !ok {
+			kelas = append(kelas, v.VarietasKelas)
+		}
+		grup[v.VarietasKelas] = append(grup[v.VarietasKelas], v)
+	}
+
+	var temuan []domain.TemuanKualitas
+	for _, k := range kelas {
+		anggota := grup[k]
+		if len(anggota) < MinDataOutlier {
+			continue
+		}
+
+		urut := make([]float64, len(anggota))
+		for i, v := range anggota {
+			urut[i] = v.PanjangBijiMM
+		}
+		sort.Float64s(urut)
+		median := kuantil(urut, 0.5)
+		q1, q3 := kuantil(urut, 0.25), kuantil(urut, 0.75)
+		bawah, atas := q1-FaktorIQR*(q3-q1), q3+FaktorIQR*(q3-q1)
+
+		simpangan := make([]float64, len(urut))
+		for i, x := range urut {
+			simpangan[i] = math.Abs(x - median)
+		}
+		sort.Float64s(simpangan)
+		mad := kuantil(simpangan, 0.5)
+
+		for _, v := range anggota {
+			x := v.PanjangBijiMM
+			var metode []string
+			// MAD 0 berarti lebih dari separuh data bernilai sama; skor z tidak terdefinisi
+			if mad > 0 && math.Abs(0.6745*(x-median)/mad) > BatasSkorZRobust {
+				metode = append(metode, "robust_z")
+			}
+			if x < bawah || x > atas {
+				metode = append(metode, "iqr")
+			}
+			if len(metode) == 0 {
+				continue
+			}
+
+			t := domain.TemuanKualitas{
+				Kode:   fmt.Sprintf("%s:%d", domain.TemuanOutlierPanjang, v.ID),
+				Jenis:  domain.TemuanOutlierPanjang,
+				IDPadi: v.ID,
+				Versi:  v.Versi,
+				Field:  "panjang_biji_mm",
+				Nilai:  x,
+				Pesan: fmt.Sprintf("panjang biji %g mm menyimpang dari varietas %s lainnya (median %.2f mm, rentang wajar %.2f–%.2f mm)",
+					x, k, median, bawah, atas),
+				Metode: metode,
+			}
+			if saran, ok := saranPanjang(x, median, bawah, atas); ok {
+				t.Saran = saran
+			}
+			temuan = append(temuan, t)
+		}
+	}
+	return temuan
+}
+
+// saranPanjang menebak nilai yang dimaksud dari salah ketik yang umum: koma desimal
+// bergeser (75 untuk 7.5) atau dua digit bersebelahan tertukar (5.7 untuk 7.5). Kandidat
+// harus berada di rentang wajar grupnya dan lolos validasi; dipilih yang terdekat ke median.
+func saranPanjang(x, median, bawah, atas float64) (float64, bool) {
+	kandidat := []float64{x / 10, x * 10, x / 100, x * 100}
+
+	digit := []byte(strconv.FormatFloat(x, 'f', -1, 64))
+	for i := 0; i+1 < len(digit); i++ {
+		j := i + 1
+		if digit[j] == '.' {
+			j++ // tukar digit di kedua sisi titik desimal
+		}
+		if j >= len(digit) || digit[i] == '.' || digit[i] == digit[j] {
+			continue
+		}
+		tukar := slices.Clone(digit)
+		tukar[i], tukar[j] = tukar[j], tukar[i]
+		if n, err := strconv.ParseFloat(string(tukar), 64); err == nil {
+			kandidat = append(kandidat, n)
+		}
+	}
+
+	terbaik, ada := 0.0, false
+	for _, c := range kandidat {
+		c = math.Round(c*100) / 100
+		if c == x || c < bawah || c > atas || c < domain.MinPanjangBijiMM || c > domain.MaxPanjangBijiMM {
+			continue
+		}
+		if !ada || math.Abs(c-median) < math.Abs(terbaik-median) {
+			terbaik, ada = c, true
+		}
+	}
+	return terbaik, ada
+}
+
+// kunciDuplikat mengelompokkan data menurut nilai kategorikal sehingga domain.KandidatDuplikat
+// hanya dibandingkan antar data yang mungkin cocok
+func kunciDuplikat(v domain.VarietasPadi) string {
+	return strings.ToLower(strings.Join([]string{v.Warna, v.TeksturPermukaan, v.BentukUjungDaun}, "\x00"))
+}
+
+// duplikat menandai data yang kemungkinan sampel fisik yang sama dengan data lain yang
+// lebih lama (domain.KandidatDuplikat). data harus terurut menurut ID sehingga data tertua
+// tidak ikut ditandai; data yang sudah ditandai tidak dipakai sebagai pembanding.
+func duplikat(data []domain.VarietasPadi) []domain.TemuanKualitas {
+	asli := map[string][]domain.VarietasPadi{}
+	var temuan []domain.TemuanKualitas
+	for _, v := range data {
+		key := kunciDuplikat(v)
+		i := slices.IndexFunc(asli[key], func(a domain.VarietasPadi) bool { return domain.KandidatDuplikat(a, v) })
+		if i < 0 {
+			asli[key] = append(asli[key], v)
+			continue
+		}
+		id := asli[key][i].ID
+		temuan = append(temuan, domain.TemuanKualitas{
+			Kode:         fmt.Sprintf("%s:%d", domain.TemuanDuplikat, v.ID),
+			Jenis:        domain.TemuanDuplikat,
+			IDPadi:       v.ID,
+			Versi:        v.Versi,
+			Pesan:        fmt.Sprintf("nilai pengamatan hampir sama dengan data id %d", id),
+			DuplikatDari: &id,
+		})
+	}
+	return temuan
+}
+
+// nilaiLangka menandai nilai kategorikal yang dipakai sangat sedikit data, beserta saran
+// nilai umum dengan ejaan paling mirip (kemungkinan yang dimaksud)
+func nilaiLangka(data []domain.VarietasPadi) []domain.TemuanKualitas {
+	if len(data) < MinDataLangka {
+		return nil
+	}
+	batas := max(1, int(ProporsiLangka*float64(len(data))))
+
+	var temuan []domain.TemuanKualitas
+	for _, field := range fieldLangka {
+		jumlah := map[string]int{}
+		for _, v := range data {
+			jumlah[v.KategoriValue(field)]++
+		}
+		var umum []string
+		for nilai, n := range jumlah {
+			if n > batas {
+				umum = append(umum, nilai)
+			}
+		}
+		sort.Strings(umum)
+
+		for _, v := range data {
+			nilai := v.KategoriValue(field)
+			if jumlah[nilai] > batas {
+				continue
+			}
+			t := domain.TemuanKualitas{
+				Kode:   fmt.Sprintf("%s:%d:%s", domain.TemuanNilaiLangka, v.ID, field),
+				Jenis:  domain.TemuanNilaiLangka,
+				IDPadi: v.ID,
+				Versi:  v.Versi,
+				Field:  field,
+				Nilai:  nilai,
+				Pesan:  fmt.Sprintf("%s '%s' hanya dipakai %d dari %d data", field, nilai, jumlah[nilai], len(data)),
+			}
+			if saran, ok := ejaanTerdekat(nilai, umum, jumlah); ok {
+				t.Saran = saran
+			}
+			temuan = append(temuan, t)
+		}
+	}
+	return temuan
+}
+
+// MaksJarakEjaan adalah jarak Levenshtein maksimal agar nilai umum disarankan sebagai
+// pengganti nilai langka
+const MaksJarakEjaan = 2
+
+// ejaanTerdekat mencari nilai umum dengan jarak edit terkecil (tidak peka huruf besar/kecil);
+// jika sama dekat dipilih yang paling sering dipakai
+func ejaanTerdekat(nilai string, umum []string, jumlah map[string]int) (string, bool) {
+	terbaik, jarakTerbaik := "", MaksJarakEjaan+1
+	for _, u := range umum {
+		d := jarakEdit(strings.ToLower(nilai), strings.ToLower(u))
+		if d < jarakTerbaik || (d == jarakTerbaik && jumlah[u] > jumlah[terbaik]) {
+			terbaik, jarakTerbaik = u, d
+		}
+	}
+	return terbaik, jarakTerbaik <= MaksJarakEjaan
+}
+
+// jarakEdit menghitung jarak Levenshtein antar dua teks (per rune)
+func jarakEdit(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			biaya := 1
+			if ra[i-1] == rb[j-1] {
+				biaya = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+biaya)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
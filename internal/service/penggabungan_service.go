@@ -0,0 +1,289 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
+)
+
+// MaksDigabung membatasi jumlah data yang digabung dalam satu permintaan
+const MaksDigabung = 100
+
+// cariKandidatDuplikat mencari data aktif yang kemungkinan sampel fisik yang sama dengan
+// data (lihat domain.KandidatDuplikat). Nilai kategorikal data harus sudah baku (hasil
+// validasi) agar bisa disaring di repository; sisanya dibandingkan di sini. Semua data yang
+// lolos filter dibaca (bukan hanya satu halaman) karena jendela waktu baru diperiksa di sini.
+func (s *VarietasService) cariKandidatDuplikat(ctx context.Context, data domain.VarietasPadi) ([]domain.VarietasPadi, error) {
+	q := domain.VarietasQuery{
+		Sort:    []domain.SortField{{Field: "id_padi", Desc: true}},
+		Filters: filterDuplikat(data, data.PanjangBijiMM, data.PanjangBijiMM),
+	}
+
+	// Data yang belum disimpan dibandingkan seolah dicatat sekarang
+	if data.WaktuPembuatan.IsZero() {
+		data.WaktuPembuatan = time.Now()
+	}
+	kandidat := []domain.VarietasPadi{}
+	err := s.repo.Iterate(ctx, q, func(v domain.VarietasPadi) error {
+		if v.ID != data.ID && domain.KandidatDuplikat(data, v) {
+			kandidat = append(kandidat, v)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return kandidat, nil
+}
+
+// filterDuplikat menyaring data tersimpan yang nilai kategorikalnya sama dengan data dan
+// panjang bijinya di antara minPanjang dan maksPanjang (ditambah toleransi)
+func filterDuplikat(data domain.VarietasPadi, minPanjang, maksPanjang float64) []domain.Filter {
+	return []domain.Filter{
+		{Field: "warna", Op: domain.OpEq, Value: data.Warna},
+		{Field: "tekstur_permukaan", Op: domain.OpEq, Value: data.TeksturPermukaan},
+		{Field: "bentuk_ujung_daun", Op: domain.OpEq, Value: data.BentukUjungDaun},
+		{Field: "panjang_biji_mm", Op: domain.OpGte, Value: minPanjang - domain.ToleransiUkuranDuplikat},
+		{Field: "panjang_biji_mm", Op: domain.OpLte, Value: maksPanjang + domain.ToleransiUkuranDuplikat},
+	}
+}
+
+// kunciDuplikat mengunci kunci duplikat setiap data sampai transaksi di ctx selesai, sehingga
+// dua transaksi tidak bisa sama-sama lolos pemeriksaan duplikat lalu menyimpan data yang sama.
+// Kunci diambil berurutan agar dua transaksi yang beririsan tidak deadlock.
+func (s *VarietasService) kunciDuplikat(ctx context.Context, data ...domain.VarietasPadi) error {
+	kunci := make([]string, len(data))
+	for i, d := range data {
+		kunci[i] = domain.KunciDuplikat(d)
+	}
+	slices.Sort(kunci)
+	for _, k := range slices.Compact(kunci) {
+		if err := s.repo.LockDuplikat(ctx, k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// saringDuplikatImpor mencatat kandidat duplikat setiap data impor di laporannya, baik data
+// tersimpan maupun baris impor sebelumnya. Pada ModeDuplikatTolak baris duplikat ditolak.
+// posisi adalah indeks laporan setiap data; hasilnya data yang disimpan beserta posisinya.
+// Laporan baris ditulis ulang seluruhnya sehingga aman dipanggil lagi saat transaksi diulang.
+func (s *VarietasService) saringDuplikatImpor(ctx context.Context, hasil *domain.HasilImpor, data []domain.VarietasPadi, posisi []int) ([]domain.VarietasPadi, []int, error) {
+	for _, p := range posisi {
+		b := &hasil.Baris[p]
+		b.Status, b.Errors, b.KandidatDuplikat, b.DuplikatBaris = domain.StatusDiterima, nil, nil, nil
+	}
+	if s.modeDuplikat == domain.ModeDuplikatNonaktif {
+		return data, posisi, nil
+	}
+	tolak := s.modeDuplikat == domain.ModeDuplikatTolak
+	if tolak {
+		if err := s.kunciDuplikat(ctx, data...); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// Data tersimpan dibaca sekali per kunci duplikat, untuk rentang panjang semua baris kunci itu
+	kelompok := map[string][]int{}
+	for i, d := range data {
+		k := domain.KunciDuplikat(d)
+		kelompok[k] = append(kelompok[k], i)
+	}
+	tersimpan := map[string][]domain.VarietasPadi{}
+	for k, anggota := range kelompok {
+		minPanjang, maksPanjang := data[anggota[0]].PanjangBijiMM, data[anggota[0]].PanjangBijiMM
+		for _, i := range anggota {
+			minPanjang, maksPanjang = min(minPanjang, data[i].PanjangBijiMM), max(maksPanjang, data[i].PanjangBijiMM)
+		}
+		q := domain.VarietasQuery{Filters: filterDuplikat(data[anggota[0]], minPanjang, maksPanjang)}
+		err := s.repo.Iterate(ctx, q, func(v domain.VarietasPadi) error {
+			tersimpan[k] = append(tersimpan[k], v)
+			return nil
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var simpan []domain.VarietasPadi
+	var simpanPosisi []int
+	diterima := map[string][]int{} // indeks data yang diterima per kunci
+	sekarang := time.Now()
+	for i, d := range data {
+		k, b := domain.KunciDuplikat(d), &hasil.Baris[posisi[i]]
+		baru := d
+		baru.WaktuPembuatan = sekarang
+		for _, v := range tersimpan[k] {
+			if domain.KandidatDuplikat(baru, v) {
+				b.KandidatDuplikat = append(b.KandidatDuplikat, v.ID)
+			}
+		}
+		for _, j := range diterima[k] {
+			if domain.KandidatDuplikat(d, data[j]) {
+				b.DuplikatBaris = append(b.DuplikatBaris, hasil.Baris[posisi[j]].Baris)
+			}
+		}
+		if tolak && (len(b.KandidatDuplikat) > 0 || len(b.DuplikatBaris) > 0) {
+			b.Status = domain.StatusDitolak
+			b.Errors = []domain.FieldError{{Field: "data", Code: "duplicate", Message: pesanDuplikatImpor(*b)}}
+			continue
+		}
+		diterima[k] = append(diterima[k], i)
+		simpan = append(simpan, d)
+		simpanPosisi = append(simpanPosisi, posisi[i])
+	}
+	return simpan, simpanPosisi, nil
+}
+
+// pesanDuplikatImpor menjelaskan data dan baris yang membuat baris impor ditolak
+func pesanDuplikatImpor(b domain.LaporanBaris) string {
+	var asal []string
+	if len(b.KandidatDuplikat) > 0 {
+		asal = append(asal, "id "+gabungAngka(b.KandidatDuplikat))
+	}
+	if len(b.DuplikatBaris) > 0 {
+		asal = append(asal, "baris "+gabungAngka(b.DuplikatBaris))
+	}
+	return "kemungkinan duplikat dari " + strings.Join(asal, " dan ")
+}
+
+// gabungAngka menulis daftar angka dipisah koma
+func gabungAngka(angka []int) string {
+	teks := make([]string, len(angka))
+	for i, n := range angka {
+		teks[i] = strconv.Itoa(n)
+	}
+	return strings.Join(teks, ", ")
+}
+
+// DapatkanKandidatDuplikat mengembalikan data lain yang kemungkinan duplikat dari data,
+// atau kosong jika pemeriksaan duplikat dinonaktifkan
+func (s *VarietasService) DapatkanKandidatDuplikat(ctx context.Context, data domain.VarietasPadi) ([]domain.VarietasPadi, error) {
+	if s.modeDuplikat == domain.ModeDuplikatNonaktif {
+		return []domain.VarietasPadi{}, nil
+	}
+	kandidat, err := s.cariKandidatDuplikat(ctx, data)
+	if err != nil {
+		return nil, wrapRepoError(err, "gagal mencari kandidat duplikat")
+	}
+	return kandidat, nil
+}
+
+// validasiGabung memeriksa permintaan penggabungan sebelum data dikunci
+func validasiGabung(req domain.PermintaanGabung) error {
+	var fields []domain.FieldError
+	if req.IDUtama < 1 {
+		fields = append(fields, domain.FieldError{Field: "id_utama", Code: "required", Message: "wajib diisi dengan ID data yang dipertahankan"})
+	}
+	switch {
+	case len(req.IDDigabung) == 0:
+		fields = append(fields, domain.FieldError{Field: "id_digabung", Code: "required", Message: "minimal satu ID data yang digabung"})
+	case len(req.IDDigabung) > MaksDigabung:
+		fields = append(fields, domain.FieldError{Field: "id_digabung", Code: "max_items", Message: fmt.Sprintf("maksimal %d ID", MaksDigabung)})
+	}
+	for i, id := range req.IDDigabung {
+		field := fmt.Sprintf("id_digabung[%d]", i)
+		switch {
+		case id < 1:
+			fields = append(fields, domain.FieldError{Field: field, Code: "invalid", Message: "harus bilangan bulat positif"})
+		case id == req.IDUtama:
+			fields = append(fields, domain.FieldError{Field: field, Code: "invalid", Message: "tidak boleh sama dengan id_utama"})
+		case slices.Index(req.IDDigabung, id) != i:
+			fields = append(fields, domain.FieldError{Field: field, Code: "duplicate", Message: fmt.Sprintf("ID %d disebut lebih dari sekali", id)})
+		}
+	}
+	if len(fields) > 0 {
+		return domain.NewValidationError("permintaan penggabungan tidak valid", fields...)
+	}
+	return nil
+}
+
+// GabungkanData menggabungkan data IDDigabung ke data IDUtama dalam satu transaksi. Nilai
+// data utama dipertahankan; lebar biji yang belum diukur diisi dari data yang digabung.
+// Data yang digabung dipindahkan ke tempat sampah, setiap perubahan dicatat di riwayat
+// sebagai operasi "merge", dan ID lamanya diarahkan ke data utama.
+func (s *VarietasService) GabungkanData(ctx context.Context, req domain.PermintaanGabung) (domain.HasilGabung, error) {
+	if err := validasiGabung(req); err != nil {
+		return domain.HasilGabung{}, err
+	}
+
+	var hasil domain.HasilGabung
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		// Dikunci berurutan menurut ID agar dua penggabungan yang beririsan tidak deadlock
+		ids := append([]int{req.IDUtama}, req.IDDigabung...)
+		slices.Sort(ids)
+		terkunci := make(map[int]domain.VarietasPadi, len(ids))
+		for _, id := range ids {
+			v, err := s.repo.Lock(ctx, id, false)
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("data varietas id %d tidak ada atau sudah dihapus: %w", id, domain.ErrNotFound)
+			}
+			if err != nil {
+				return err
+			}
+			terkunci[id] = v
+		}
+
+		utama := terkunci[req.IDUtama]
+		hasil.Data = utama
+		if utama.LebarBijiMM == nil {
+			for _, id := range req.IDDigabung {
+				if lebar := terkunci[id].LebarBijiMM; lebar != nil {
+					var err error
+					if hasil.Data, err = s.repo.Patch(ctx, utama.ID, domain.VarietasPatch{LebarBijiMM: &lebar}); err != nil {
+						return err
+					}
+					break
+				}
+			}
+		}
+
+		ketUtama := make([]string, len(req.IDDigabung))
+		penunjuk := make([]domain.Penggabungan, len(req.IDDigabung))
+		for i, id := range req.IDDigabung {
+			sebelum := terkunci[id]
+			sesudah, err := s.repo.Delete(ctx, id)
+			if err != nil {
+				return err
+			}
+			ctxGabung := domain.WithKeterangan(ctx, fmt.Sprintf("digabung ke id %d", utama.ID))
+			if err := s.catat(ctxGabung, domain.OperasiMerge, &sebelum, &sesudah); err != nil {
+				return err
+			}
+			ketUtama[i] = fmt.Sprint(id)
+			penunjuk[i] = domain.Penggabungan{IDLama: id, IDBaru: utama.ID, Aktor: domain.ActorFromContext(ctx)}
+		}
+
+		ctxUtama := domain.WithKeterangan(ctx, "gabungan dari id "+strings.Join(ketUtama, ", "))
+		if err := s.catat(ctxUtama, domain.OperasiMerge, &utama, &hasil.Data); err != nil {
+			return err
+		}
+
+		var err error
+		hasil.Digabung, err = s.gabung.Save(ctx, penunjuk...)
+		return err
+	})
+	if err != nil {
+		return domain.HasilGabung{}, wrapRepoError(err, fmt.Sprintf("gagal menggabungkan data ke varietas id %d", req.IDUtama))
+	}
+	s.model.usang()
+	return hasil, nil
+}
+
+// tujuanGabung mengembalikan DigabungError jika data id sudah digabung ke data lain,
+// atau errAsli jika tidak (termasuk jika penunjuknya tidak bisa dibaca)
+func (s *VarietasService) tujuanGabung(ctx context.Context, id int, errAsli error) error {
+	g, err := s.gabung.FindByID(ctx, id)
+	if err != nil {
+		return errAsli
+	}
+	return &domain.DigabungError{IDLama: g.IDLama, IDBaru: g.IDBaru}
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
+)
+
+func TestKandidatDuplikatLebihDariSatuHalaman(t *testing.T) {
+	ctx := context.Background()
+	svc := newServiceUji(domain.ModeDuplikatPeringatan)
+
+	// Lebih banyak dari domain.MaxPerPage data yang semuanya mirip sampel
+	jumlah := domain.MaxPerPage + 5
+	for i := range jumlah {
+		v := contohVarietas()
+		v.PanjangBijiMM += float64(i%3) * 0.01 // masih dalam toleransi pembulatan
+		if _, err := svc.TambahkanData(ctx, v); err != nil {
+			t.Fatalf("TambahkanData ke-%d: %v", i, err)
+		}
+	}
+	// Data yang bukan kandidat tidak boleh ikut
+	bukan := contohVarietas()
+	bukan.PanjangBijiMM += 1
+	if _, err := svc.TambahkanData(ctx, bukan); err != nil {
+		t.Fatalf("TambahkanData: %v", err)
+	}
+
+	kandidat, err := svc.DapatkanKandidatDuplikat(ctx, contohVarietas())
+	if err != nil {
+		t.Fatalf("DapatkanKandidatDuplikat: %v", err)
+	}
+	if len(kandidat) != jumlah {
+		t.Fatalf("jumlah kandidat = %d, ingin %d", len(kandidat), jumlah)
+	}
+	ids := make([]int, len(kandidat))
+	for i, v := range kandidat {
+		ids[i] = v.ID
+	}
+	if !slices.Contains(ids, 1) {
+		t.Error("data pertama (di luar 100 data terbaru) tidak ditemukan")
+	}
+	if !slices.IsSortedFunc(ids, func(a, b int) int { return b - a }) {
+		t.Errorf("kandidat tidak terurut dari id terbaru: %v", ids)
+	}
+
+	// Mode tolak memakai pencarian yang sama: semua kandidat dilaporkan
+	svc.modeDuplikat = domain.ModeDuplikatTolak
+	_, err = svc.TambahkanData(ctx, contohVarietas())
+	var dupErr *domain.DuplikatError
+	if !errors.As(err, &dupErr) || len(dupErr.Kandidat) != jumlah {
+		t.Fatalf("error = %v, ingin DuplikatError dengan %d kandidat", err, jumlah)
+	}
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
+)
+
+// StatistikData memakai agregasi repository jika tersedia (SQL), atau menghitung sendiri
+// dari semua data yang cocok untuk repository lain (misalnya in-memory)
+func (s *VarietasService) StatistikData(ctx context.Context, q domain.VarietasQuery, groupBy string) (domain.StatistikVarietas, error) {
+	if groupBy != "" && !slices.Contains(domain.FieldKategorikal, groupBy) {
+		return domain.StatistikVarietas{}, domain.NewValidationError("parameter group_by tidak valid",
+			domain.FieldError{Field: "group_by", Code: "one_of",
+				Message: "harus salah satu dari: " + strings.Join(domain.FieldKategorikal, ", ")})
+	}
+	q.Page, q.PerPage, q.Sort = 0, 0, nil
+
+	if repo, ok := s.repo.(domain.StatistikRepository); ok {
+		hasil, err := repo.Statistik(ctx, q, groupBy)
+		if err != nil {
+			return domain.StatistikVarietas{}, wrapRepoError(err, "gagal menghitung statistik varietas")
+		}
+		return hasil, nil
+	}
+
+	var data []domain.VarietasPadi
+	err := s.repo.Iterate(ctx, q, func(v domain.VarietasPadi) error {
+		data = append(data, v)
+		return nil
+	})
+	if err != nil {
+		return domain.StatistikVarietas{}, wrapRepoError(err, "gagal menghitung statistik varietas")
+	}
+	return hitungStatistik(data, groupBy), nil
+}
+
+// hitungStatistik adalah versi Go dari agregasi SQL di repository PostgreSQL
+func hitungStatistik(data []domain.VarietasPadi, groupBy string) domain.StatistikVarietas {
+	grup := map[string][]domain.VarietasPadi{}
+	for _, v := range data {
+		key := ""
+		if groupBy != "" {
+			key = v.KategoriValue(groupBy)
+		}
+		grup[key] = append(grup[key], v)
+	}
+
+	hasil := domain.StatistikVarietas{GroupBy: groupBy, Total: len(data), Grup: []domain.StatistikGrup{}}
+	for key, anggota := range grup {
+		g := domain.StatistikGrup{Grup: key, Frekuensi: map[string][]domain.Frekuensi{}}
+
+		panjang := make([]float64, len(anggota))
+		for i, v := range anggota {
+			panjang[i] = v.PanjangBijiMM
+		}
+		g.PanjangBijiMM = statistikNumerik(panjang)
+
+		for _, field := range domain.FieldKategorikal {
+			if field == groupBy {
+				continue
+			}
+			g.Frekuensi[field] = tabelFrekuensi(anggota, field)
+		}
+		hasil.Grup = append(hasil.Grup, g)
+	}
+	sort.Slice(hasil.Grup, func(i, j int) bool { return hasil.Grup[i].Grup < hasil.Grup[j].Grup })
+	return hasil
+}
+
+// statistikNumerik menghitung ringkasan lima angka, rerata, dan simpangan baku sampel
+func statistikNumerik(nilai []float64) domain.StatistikNumerik {
+	n := len(nilai)
+	if n == 0 {
+		return domain.StatistikNumerik{}
+	}
+	urut := slices.Clone(nilai)
+	sort.Float64s(urut)
+
+	var jumlah float64
+	for _, x := range urut {
+		jumlah += x
+	}
+	mean := jumlah / float64(n)
+
+	var stdDev float64
+	if n > 1 {
+		var kuadrat float64
+		for _, x := range urut {
+			kuadrat += (x - mean) * (x - mean)
+		}
+		stdDev = math.Sqrt(kuadrat / float64(n-1))
+	}
+
+	return domain.StatistikNumerik{
+		Count:  n,
+		Min:    urut[0],
+		Q1:     kuantil(urut, 0.25),
+		Median: kuantil(urut, 0.5),
+		Q3:     kuantil(urut, 0.75),
+		Max:    urut[n-1],
+		Mean:   mean,
+		StdDev: stdDev,
+	}
+}
+
+// kuantil menghitung kuantil p dari data terurut dengan interpolasi linear antar dua data
+// terdekat, sama seperti percentile_cont di PostgreSQL
+func kuantil(urut []float64, p float64) float64 {
+	posisi := p * float64(len(urut)-1)
+	bawah := int(math.Floor(posisi))
+	if bawah+1 >= len(urut) {
+		return urut[bawah]
+	}
+	return urut[bawah] + (posisi-float64(bawah))*(urut[bawah+1]-urut[bawah])
+}
+
+// tabelFrekuensi menghitung kemunculan setiap nilai field, dari yang paling sering
+func tabelFrekuensi(data []domain.VarietasPadi, field string) []domain.Frekuensi {
+	jumlah := map[string]int{}
+	for _, v := range data {
+		jumlah[v.KategoriValue(field)]++
+	}
+	tabel := make([]domain.Frekuensi, 0, len(jumlah))
+	for nilai, n := range jumlah {
+		tabel = append(tabel, domain.Frekuensi{Nilai: nilai, Jumlah: n, Proporsi: float64(n) / float64(len(data))})
+	}
+	sort.Slice(tabel, func(i, j int) bool {
+		if tabel[i].Jumlah != tabel[j].Jumlah {
+			return tabel[i].Jumlah > tabel[j].Jumlah
+		}
+		return tabel[i].Nilai < tabel[j].Nilai
+	})
+	return tabel
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"database/sql" // DITAMBAH: Untuk penanganan error sql.ErrNoRows
 	"errors"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
 )
@@ -13,40 +16,176 @@ import (
 type VarietasService struct {
 	// Variabel repo harus berupa interface, BUKAN struct konkret
 	repo domain.VarietasRepository
+	// riwayat mencatat setiap perubahan data di transaksi yang sama dengan perubahannya
+	riwayat domain.RiwayatRepository
+	// tx menyatukan beberapa operasi repository dalam satu transaksi
+	tx domain.TxManager
+	// validator menjalankan aturan yang sama untuk create, update, dan import
+	validator *domain.Validator
+	// validatorFitur sama seperti validator tetapi tanpa varietas_kelas (input prediksi)
+	validatorFitur *domain.Validator
+	// kosakata menyeragamkan ejaan warna, tekstur, dan bentuk ujung daun
+	kosakata domain.KosakataRepository
+	// model adalah cache model klasifikasi; ditandai usang setiap data berubah
+	model *modelKlasifikasi
+	// kualitas menyimpan pengakuan reviewer atas temuan pemeriksaan kualitas data
+	kualitas domain.KualitasRepository
+	// gabung menyimpan penunjuk dari ID data yang sudah digabung ke data utamanya
+	gabung domain.PenggabunganRepository
+	// modeDuplikat menentukan tindakan saat data baru mirip data yang sudah ada
+	modeDuplikat domain.ModeDuplikat
 }
 
 // NewVarietasService adalah constructor untuk Service Layer.
-func NewVarietasService(repo domain.VarietasRepository) domain.VarietasService {
-	return &VarietasService{repo: repo}
+func NewVarietasService(repo domain.VarietasRepository, riwayat domain.RiwayatRepository,
+	kosakata domain.KosakataRepository, tx domain.TxManager, kualitas domain.KualitasRepository,
+	gabung domain.PenggabunganRepository, modeDuplikat domain.ModeDuplikat) domain.VarietasService {
+	return &VarietasService{
+		repo:           repo,
+		riwayat:        riwayat,
+		tx:             tx,
+		validator:      domain.NewValidator(),
+		validatorFitur: domain.NewValidator(domain.FiturRules...),
+		kosakata:       kosakata,
+		model:          &modelKlasifikasi{},
+		kualitas:       kualitas,
+		gabung:         gabung,
+		modeDuplikat:   modeDuplikat,
+	}
 }
 
-// --- IMPLEMENTASI FUNGSI CRUD LENGKAP ---
+// catat menyimpan revisi satu perubahan. Dipanggil di dalam WithinTx yang sama dengan
+// perubahannya agar data dan riwayatnya tersimpan atau batal bersama.
+func (s *VarietasService) catat(ctx context.Context, operasi string, sebelum, sesudah *domain.VarietasPadi) error {
+	return s.riwayat.Catat(ctx, domain.NewRevisi(ctx, operasi, sebelum, sesudah))
+}
 
-// DapatkanSemuaData mengimplementasikan kontrak service untuk Read All.
-func (s *VarietasService) DapatkanSemuaData(ctx context.Context) ([]domain.VarietasPadi, error) {
-	data, err := s.repo.FindAll(ctx) // DITAMBAH ctx
+// kunci mengunci data aktif id sampai transaksi selesai, memastikan pemanggil boleh
+// melakukan operasi pada data itu (periksaPemilik), dan memastikan versinya sama dengan
+// versi yang diharapkan (0 berarti tanpa syarat). Harus dipanggil di dalam WithinTx.
+func (s *VarietasService) kunci(ctx context.Context, operasi string, id int, versi int) (domain.VarietasPadi, error) {
+	current, err := s.repo.Lock(ctx, id, false)
 	if err != nil {
-		return nil, errors.New("gagal mengambil data varietas dari penyimpanan")
+		return domain.VarietasPadi{}, err
+	}
+	if err := periksaPemilik(ctx, operasi, current); err != nil {
+		return domain.VarietasPadi{}, err
+	}
+	if versi != 0 && current.Versi != versi {
+		return domain.VarietasPadi{}, domain.ErrPreconditionFailed
 	}
+	return current, nil
+}
 
-	// Contoh Logika Bisnis: Memberi notifikasi jika data kosong
-	if len(data) == 0 {
-		// Log.Println("Tidak ada data varietas ditemukan")
+// periksaPemilik menolak operasi pada data milik orang lain jika pemanggil hanya boleh
+// melakukannya pada data sendiri (lihat domain.WithPemilikWajib). Dipanggil setelah data
+// dikunci agar pemeriksaan dan perubahannya atomik.
+func periksaPemilik(ctx context.Context, operasi string, data domain.VarietasPadi) error {
+	if subjek, ok := domain.PemilikWajib(ctx, operasi); ok && data.DibuatOleh != subjek {
+		return fmt.Errorf("%w: data varietas id %d bukan milik %s", domain.ErrForbidden, data.ID, subjek)
 	}
+	return nil
+}
+
+// validasi menjalankan validator lalu mengganti nilai kategorikal dengan ejaan baku
+// dari kosakata terkontrol (termasuk alias). Nilai yang tidak dikenal ditolak.
+// Pelanggaran dari kedua tahap digabung dalam satu ValidationError.
+func (s *VarietasService) validasi(ctx context.Context, data domain.VarietasPadi) (domain.VarietasPadi, error) {
+	return s.validasiDengan(ctx, data, s.validator, s.kosakata.Resolve)
+}
+
+// resolver mencari ejaan baku sebuah nilai kategorikal (lihat KosakataRepository.Resolve)
+type resolver func(ctx context.Context, kategori, value string) (string, error)
 
+// validasiDengan sama seperti validasi, tetapi memakai validator dan resolver kosakata tertentu
+// (misalnya resolver ber-cache saat impor ribuan baris)
+func (s *VarietasService) validasiDengan(ctx context.Context, data domain.VarietasPadi,
+	validator *domain.Validator, resolve resolver) (domain.VarietasPadi, error) {
+	data, err := validator.Validate(data)
+
+	var fields []domain.FieldError
+	var vErr *domain.ValidationError
+	if errors.As(err, &vErr) {
+		fields = vErr.Fields
+	} else if err != nil {
+		return domain.VarietasPadi{}, err
+	}
+
+	for _, kategori := range domain.KategoriKosakata {
+		value := data.KategoriValue(kategori)
+		if value == "" {
+			continue // sudah dilaporkan oleh aturan Required
+		}
+		nilai, err := resolve(ctx, kategori, value)
+		if errors.Is(err, sql.ErrNoRows) {
+			fields = append(fields, domain.FieldError{Field: kategori, Code: "unknown_value",
+				Message: fmt.Sprintf("nilai '%s' belum terdaftar di kosakata %s", value, kategori)})
+			continue
+		}
+		if err != nil {
+			return domain.VarietasPadi{}, wrapRepoError(err, "gagal memeriksa kosakata "+kategori)
+		}
+		data = data.WithKategoriValue(kategori, nilai)
+	}
+
+	if len(fields) > 0 {
+		return domain.VarietasPadi{}, domain.NewValidationError("data varietas tidak valid", fields...)
+	}
 	return data, nil
 }
 
+// wrapRepoError menerjemahkan error dari repository menjadi error domain.
+// sql.ErrNoRows menjadi ErrNotFound, error domain diteruskan apa adanya,
+// dan error lain dibungkus dengan konteks operasi yang gagal.
+func wrapRepoError(err error, operasi string) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, sql.ErrNoRows):
+		return fmt.Errorf("%s: %w", operasi, domain.ErrNotFound)
+	case errors.Is(err, domain.ErrNotFound), errors.Is(err, domain.ErrValidation), errors.Is(err, domain.ErrConflict),
+		errors.Is(err, domain.ErrForbidden),
+		errors.Is(err, domain.ErrUnavailable), errors.Is(err, domain.ErrQueryTidakValid),
+		errors.Is(err, domain.ErrPreconditionFailed):
+		return fmt.Errorf("%s: %w", operasi, err)
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+		return fmt.Errorf("%s: %w: %v", operasi, domain.ErrUnavailable, err)
+	}
+	return fmt.Errorf("%s: %v", operasi, err)
+}
+
+// --- IMPLEMENTASI FUNGSI CRUD LENGKAP ---
+
+// DapatkanSemuaData mengimplementasikan kontrak service untuk Read All.
+// Filter, sort, dan pagination diteruskan ke repository agar dikerjakan di database.
+func (s *VarietasService) DapatkanSemuaData(ctx context.Context, q domain.VarietasQuery) (domain.VarietasPage, error) {
+	page, err := s.repo.FindPage(ctx, q.Normalize())
+	if err != nil {
+		return domain.VarietasPage{}, wrapRepoError(err, "gagal mengambil data varietas")
+	}
+
+	return page, nil
+}
+
+// EksporData mengalirkan data yang cocok dengan filter dan sort q ke fn.
+// Pagination diabaikan: semua data yang cocok diekspor.
+func (s *VarietasService) EksporData(ctx context.Context, q domain.VarietasQuery, fn func(domain.VarietasPadi) error) error {
+	q.Page, q.PerPage = 0, 0
+	err := s.repo.Iterate(ctx, q, fn)
+	return wrapRepoError(err, "gagal mengekspor data varietas")
+}
+
 // DapatkanDataByID mengimplementasikan kontrak service untuk Read By ID.
 // Didefinisikan di luar struct, sebagai method.
 func (s *VarietasService) DapatkanDataByID(ctx context.Context, id int) (domain.VarietasPadi, error) {
 	data, err := s.repo.FindByID(ctx, id) // DITAMBAH ctx
+	if errors.Is(err, sql.ErrNoRows) {
+		// Data yang sudah digabung diarahkan ke data utamanya
+		err = s.tujuanGabung(ctx, id, err)
+	}
 	if err != nil {
-		// Logika penanganan error database spesifik (contoh)
-		if errors.Is(err, sql.ErrNoRows) {
-			return domain.VarietasPadi{}, errors.New("data varietas tidak ditemukan")
-		}
-		return domain.VarietasPadi{}, errors.New("gagal mengambil data dari penyimpanan")
+		// sql.ErrNoRows diterjemahkan menjadi domain.ErrNotFound
+		return domain.VarietasPadi{}, wrapRepoError(err, fmt.Sprintf("gagal mengambil varietas id %d", id))
 	}
 	return data, nil
 }
@@ -54,33 +193,481 @@ func (s *VarietasService) DapatkanDataByID(ctx context.Context, id int) (domain.
 // TambahkanData mengimplementasikan kontrak service untuk Create.
 // Tanda tangan fungsi diubah untuk menerima context.Context
 func (s *VarietasService) TambahkanData(ctx context.Context, data domain.VarietasPadi) (domain.VarietasPadi, error) { // DITAMBAH ctx
-	// Logika Bisnis: Validasi
-	if data.VarietasKelas == "" || data.PanjangBijiMM <= 0 {
-		return domain.VarietasPadi{}, errors.New("varietas kelas atau panjang biji tidak valid")
+	// Logika Bisnis: Validasi (semua pelanggaran dikumpulkan sekaligus)
+	data, err := s.validasi(ctx, data)
+	if err != nil {
+		return domain.VarietasPadi{}, err
 	}
+	data.DibuatOleh = domain.ActorFromContext(ctx)
 
-	// Panggil Repository (DITAMBAH ctx)
-	return s.repo.Create(ctx, data)
+	// Panggil Repository (DITAMBAH ctx); data dan riwayatnya disimpan dalam satu transaksi
+	var created domain.VarietasPadi
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if s.modeDuplikat == domain.ModeDuplikatTolak {
+			if err := s.kunciDuplikat(ctx, data); err != nil {
+				return err
+			}
+			kandidat, err := s.cariKandidatDuplikat(ctx, data)
+			if err != nil {
+				return err
+			}
+			if len(kandidat) > 0 {
+				return &domain.DuplikatError{Kandidat: kandidat}
+			}
+		}
+		var err error
+		if created, err = s.repo.Create(ctx, data); err != nil {
+			return err
+		}
+		return s.catat(ctx, domain.OperasiCreate, nil, &created)
+	})
+	if err != nil {
+		return domain.VarietasPadi{}, wrapRepoError(err, "gagal menyimpan data varietas")
+	}
+	s.model.usang()
+	return created, nil
+}
+
+// ImporData memvalidasi setiap baris impor dengan aturan yang sama seperti TambahkanData,
+// termasuk pemeriksaan duplikat, lalu menyimpan semua baris valid beserta riwayatnya dalam
+// satu transaksi.
+// Pada dryRun tidak ada yang disimpan; laporannya tetap menunjukkan baris yang akan diterima.
+func (s *VarietasService) ImporData(ctx context.Context, baris []domain.BarisImpor, dryRun bool) (domain.HasilImpor, error) {
+	hasil := domain.HasilImpor{DryRun: dryRun, Total: len(baris), Baris: make([]domain.LaporanBaris, len(baris))}
+
+	// Nilai kategorikal di file impor biasanya berulang, jadi hasil Resolve di-cache
+	resolve := cachedResolver(s.kosakata.Resolve)
+
+	var valid []domain.VarietasPadi
+	var posisi []int // indeks laporan untuk setiap data valid
+	for i, b := range baris {
+		hasil.Baris[i] = domain.LaporanBaris{Baris: b.Baris, Status: domain.StatusDitolak, Errors: b.Errors}
+		if len(b.Errors) > 0 {
+			continue
+		}
+
+		data, err := s.validasiDengan(ctx, b.Data, s.validator, resolve)
+		var vErr *domain.ValidationError
+		if errors.As(err, &vErr) {
+			hasil.Baris[i].Errors = vErr.Fields
+			continue
+		}
+		if err != nil {
+			return domain.HasilImpor{}, err
+		}
+
+		data.DibuatOleh = domain.ActorFromContext(ctx)
+		valid = append(valid, data)
+		posisi = append(posisi, i)
+	}
+	if len(valid) == 0 {
+		hasil.Ditolak = hasil.Total
+		return hasil, nil
+	}
+
+	ctx = domain.WithKeterangan(ctx, "impor CSV")
+	var created []domain.VarietasPadi
+	var posisiDibuat []int // indeks laporan untuk setiap data yang disimpan
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		simpan, posisiSimpan, err := s.saringDuplikatImpor(ctx, &hasil, valid, posisi)
+		if err != nil || dryRun || len(simpan) == 0 {
+			return err
+		}
+		posisiDibuat = posisiSimpan
+		if created, err = s.repo.CreateBulk(ctx, simpan); err != nil {
+			return err
+		}
+		revisi := make([]domain.Revisi, len(created))
+		for i := range created {
+			revisi[i] = domain.NewRevisi(ctx, domain.OperasiCreate, nil, &created[i])
+		}
+		return s.riwayat.Catat(ctx, revisi...)
+	})
+	if err != nil {
+		return domain.HasilImpor{}, wrapRepoError(err, "gagal menyimpan data impor")
+	}
+	for _, b := range hasil.Baris {
+		if b.Status == domain.StatusDiterima {
+			hasil.Diterima++
+		}
+	}
+	hasil.Ditolak = hasil.Total - hasil.Diterima
+	if len(created) == 0 {
+		return hasil, nil
+	}
+	s.model.usang()
+	for j, c := range created {
+		hasil.Baris[posisiDibuat[j]].IDPadi = c.ID
+	}
+	return hasil, nil
+}
+
+// cachedResolver membungkus resolver agar setiap pasangan (kategori, nilai) hanya dicari sekali.
+// Nilai yang tidak dikenal (sql.ErrNoRows) juga di-cache; error lain tidak.
+func cachedResolver(resolve resolver) resolver {
+	type hasilResolve struct {
+		nilai string
+		err   error
+	}
+	cache := map[string]hasilResolve{}
+	return func(ctx context.Context, kategori, value string) (string, error) {
+		key := kategori + "\x00" + strings.ToLower(value)
+		if h, ok := cache[key]; ok {
+			return h.nilai, h.err
+		}
+		nilai, err := resolve(ctx, kategori, value)
+		if err == nil || errors.Is(err, sql.ErrNoRows) {
+			cache[key] = hasilResolve{nilai, err}
+		}
+		return nilai, err
+	}
 }
 
 // UbahData mengimplementasikan kontrak service untuk Update. (Perlu implementasi di sini)
 func (s *VarietasService) UbahData(ctx context.Context, data domain.VarietasPadi) (domain.VarietasPadi, error) {
 	// Logika Bisnis: Validasi ID dan data
 	if data.ID <= 0 {
-		return domain.VarietasPadi{}, errors.New("ID varietas tidak valid untuk diubah")
+		return domain.VarietasPadi{}, domain.NewValidationError("ID varietas tidak valid untuk diubah",
+			domain.FieldError{Field: "id_padi", Code: "invalid", Message: "harus bilangan bulat positif"})
+	}
+	data, err := s.validasi(ctx, data)
+	if err != nil {
+		return domain.VarietasPadi{}, err
+	}
+
+	// Panggil Repository (Update) setelah baris dikunci dan versinya dicek
+	var updated domain.VarietasPadi
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.kunci(ctx, domain.OperasiUpdate, data.ID, data.Versi)
+		if err != nil {
+			return err
+		}
+		if updated, err = s.repo.Update(ctx, data); err != nil {
+			return err
+		}
+		return s.catat(ctx, domain.OperasiUpdate, &before, &updated)
+	})
+	if err != nil {
+		return domain.VarietasPadi{}, wrapRepoError(err, fmt.Sprintf("gagal mengubah varietas id %d", data.ID))
 	}
-	// Panggil Repository (Update)
-	return s.repo.Update(ctx, data)
+	s.model.usang()
+	return updated, nil
 }
 
-// HapusData mengimplementasikan kontrak service untuk Delete. (Perlu implementasi di sini)
-func (s *VarietasService) HapusData(ctx context.Context, id int) error {
-	// Panggil Repository (Delete)
-	err := s.repo.Delete(ctx, id)
-	if errors.Is(err, sql.ErrNoRows) {
-		return errors.New("data varietas yang akan dihapus tidak ditemukan")
+// UbahSebagian menerapkan perubahan sebagian (PATCH). Data lama dibaca dan dikunci, patch
+// digabung, lalu hasil gabungan divalidasi dengan aturan yang sama seperti create/update.
+// Hanya kolom yang ada di patch yang ditulis ke repository.
+func (s *VarietasService) UbahSebagian(ctx context.Context, id int, patch domain.VarietasPatch) (domain.VarietasPadi, error) {
+	var updated domain.VarietasPadi
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		// Versi dicek lebih awal agar klien dengan ETag usang tidak menerima error validasi/test
+		existing, err := s.kunci(ctx, domain.OperasiUpdate, id, patch.Versi)
+		if err != nil {
+			return err
+		}
+
+		// Operasi "test" JSON Patch: semua syarat harus cocok dengan data saat ini
+		if failed := patch.FailedTests(existing); len(failed) > 0 {
+			return fmt.Errorf("%w: nilai saat ini tidak cocok dengan operasi test pada %v", domain.ErrConflict, failed)
+		}
+		if patch.IsEmpty() {
+			updated = existing
+			return nil
+		}
+
+		merged, err := s.validasi(ctx, patch.Apply(existing))
+		if err != nil {
+			return err
+		}
+		if updated, err = s.repo.Patch(ctx, id, patch.Restrict(merged)); err != nil {
+			return err
+		}
+		return s.catat(ctx, domain.OperasiUpdate, &existing, &updated)
+	})
+	if err != nil {
+		return domain.VarietasPadi{}, wrapRepoError(err, fmt.Sprintf("gagal mengubah sebagian varietas id %d", id))
+	}
+	s.model.usang()
+	return updated, nil
+}
+
+// HapusData mengimplementasikan kontrak service untuk Delete.
+// versi adalah versi yang diharapkan dari If-Match (0 berarti tanpa syarat).
+func (s *VarietasService) HapusData(ctx context.Context, id int, versi int) error {
+	// Panggil Repository (Delete) setelah baris dikunci dan versinya dicek
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.kunci(ctx, domain.OperasiDelete, id, versi)
+		if err != nil {
+			return err
+		}
+		deleted, err := s.repo.Delete(ctx, id)
+		if err != nil {
+			return err
+		}
+		return s.catat(ctx, domain.OperasiDelete, &before, &deleted)
+	})
+	if err != nil {
+		return wrapRepoError(err, fmt.Sprintf("gagal menghapus varietas id %d", id))
+	}
+	s.model.usang()
+	return nil
+}
+
+// --- TEMPAT SAMPAH (SOFT DELETE) ---
+
+// DaftarSampah mengambil data yang sudah dihapus, dengan filter/sort/pagination yang sama
+// seperti DapatkanSemuaData
+func (s *VarietasService) DaftarSampah(ctx context.Context, q domain.VarietasQuery) (domain.VarietasPage, error) {
+	q.Terhapus = true
+	page, err := s.repo.FindPage(ctx, q.Normalize())
+	if err != nil {
+		return domain.VarietasPage{}, wrapRepoError(err, "gagal mengambil isi tempat sampah")
+	}
+	return page, nil
+}
+
+// PulihkanData mengembalikan data dari tempat sampah ke daftar aktif
+func (s *VarietasService) PulihkanData(ctx context.Context, id int) (domain.VarietasPadi, error) {
+	var restored domain.VarietasPadi
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.repo.Lock(ctx, id, true)
+		if err != nil {
+			return err // sql.ErrNoRows jika id tidak ada di tempat sampah
+		}
+		if err := periksaPemilik(ctx, domain.OperasiRestore, before); err != nil {
+			return err
+		}
+		// Data yang sudah digabung tidak dipulihkan agar tidak muncul dua kali
+		if g, err := s.gabung.FindByID(ctx, id); err == nil {
+			return fmt.Errorf("%w: data sudah digabung ke id %d", domain.ErrConflict, g.IDBaru)
+		} else if !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		if restored, err = s.repo.Restore(ctx, id); err != nil {
+			return err
+		}
+		return s.catat(ctx, domain.OperasiRestore, &before, &restored)
+	})
+	if err != nil {
+		return domain.VarietasPadi{}, wrapRepoError(err, fmt.Sprintf("gagal memulihkan varietas id %d dari tempat sampah", id))
+	}
+	s.model.usang()
+	return restored, nil
+}
+
+// BersihkanSampah menghapus permanen data yang sudah berada di tempat sampah lebih lama dari retensi.
+// Riwayat data tetap disimpan dan ditambah satu revisi "purge" per data.
+func (s *VarietasService) BersihkanSampah(ctx context.Context, retensi time.Duration) (int, error) {
+	if retensi < 0 {
+		return 0, domain.NewValidationError("masa retensi tidak boleh negatif",
+			domain.FieldError{Field: "older_than", Code: "range", Message: "harus durasi positif, misalnya 720h"})
+	}
+	batas := time.Now().Add(-retensi)
+	var n int
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		purged, err := s.repo.Purge(ctx, batas)
+		if err != nil {
+			return err
+		}
+		n = len(purged)
+		revisi := make([]domain.Revisi, len(purged))
+		for i := range purged {
+			revisi[i] = domain.NewRevisi(ctx, domain.OperasiPurge, &purged[i], nil)
+		}
+		return s.riwayat.Catat(ctx, revisi...)
+	})
+	if err != nil {
+		return 0, wrapRepoError(err, "gagal membersihkan tempat sampah")
+	}
+	if n > 0 {
+		s.model.usang()
+	}
+	return n, nil
+}
+
+// --- RIWAYAT PERUBAHAN (AUDIT TRAIL) ---
+
+// RiwayatData mengembalikan timeline perubahan satu data, termasuk data yang sudah dihapus
+func (s *VarietasService) RiwayatData(ctx context.Context, id int) ([]domain.Revisi, error) {
+	riwayat, err := s.riwayat.History(ctx, id)
+	if err != nil {
+		return nil, wrapRepoError(err, fmt.Sprintf("gagal mengambil riwayat varietas id %d", id))
+	}
+	if len(riwayat) > 0 {
+		return riwayat, nil
+	}
+
+	// Data yang dibuat sebelum audit trail ada memang belum punya riwayat
+	if _, err := s.repo.FindByID(ctx, id); err != nil {
+		return nil, wrapRepoError(err, fmt.Sprintf("gagal mengambil riwayat varietas id %d", id))
+	}
+	return riwayat, nil
+}
+
+// KembalikanRevisi mengubah data kembali ke keadaan yang tercatat pada revisi tertentu.
+// Perubahan ini sendiri dicatat sebagai revisi baru (tidak menghapus riwayat setelahnya),
+// dan tetap divalidasi karena kosakata atau aturan bisa berubah sejak revisi itu dibuat.
+func (s *VarietasService) KembalikanRevisi(ctx context.Context, id int, revisiID int64, versi int) (domain.VarietasPadi, error) {
+	rev, err := s.riwayat.FindRevision(ctx, id, revisiID)
+	if err != nil {
+		return domain.VarietasPadi{}, wrapRepoError(err, fmt.Sprintf("gagal mengambil revisi %d varietas id %d", revisiID, id))
+	}
+	snapshot, ok := rev.Snapshot()
+	if !ok {
+		return domain.VarietasPadi{}, fmt.Errorf("%w: revisi %d tidak menyimpan keadaan data", domain.ErrConflict, revisiID)
+	}
+
+	data := domain.VarietasPadi{
+		ID:               id,
+		VarietasKelas:    snapshot.VarietasKelas,
+		Warna:            snapshot.Warna,
+		PanjangBijiMM:    snapshot.PanjangBijiMM,
+		LebarBijiMM:      snapshot.LebarBijiMM,
+		TeksturPermukaan: snapshot.TeksturPermukaan,
+		BentukUjungDaun:  snapshot.BentukUjungDaun,
+		Versi:            versi,
+	}
+	ctx = domain.WithKeterangan(ctx, fmt.Sprintf("revert ke revisi %d", revisiID))
+	return s.UbahData(ctx, data)
+}
+
+// --- BATCH (SINKRONISASI CLIENT MOBILE) ---
+
+// MaksOperasiBatch membatasi jumlah operasi per batch agar satu transaksi tidak terlalu lama
+const MaksOperasiBatch = 1000
+
+// errBatchDibatalkan dipakai untuk me-rollback transaksi batch atomic yang punya operasi gagal
+var errBatchDibatalkan = errors.New("batch dibatalkan")
+
+// JalankanBatch menjalankan semua operasi secara berurutan dalam satu transaksi.
+// Setiap operasi memakai aturan yang sama dengan endpoint tunggalnya (validasi, kosakata, versi).
+// Atomic: operasi pertama yang gagal me-rollback seluruh batch dan operasi sisanya tidak dijalankan.
+// Non-atomic: setiap operasi dibungkus savepoint sehingga hanya operasi yang gagal yang dibatalkan.
+func (s *VarietasService) JalankanBatch(ctx context.Context, ops []domain.OperasiBatch, atomic bool) (domain.HasilBatch, error) {
+	if err := validasiBatch(ops); err != nil {
+		return domain.HasilBatch{}, err
+	}
+
+	var hasil domain.HasilBatch
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		// Disusun ulang setiap percobaan karena transaksi bisa diulang oleh TxManager
+		hasil = domain.HasilBatch{Atomic: atomic, Hasil: make([]domain.HasilOperasi, len(ops))}
+		for i, op := range ops {
+			hasil.Hasil[i] = domain.HasilOperasi{Index: i, Ref: op.Ref, Op: op.Op, Status: domain.StatusDibatalkan}
+		}
+
+		for i, op := range ops {
+			var data *domain.VarietasPadi
+			jalankan := func(ctx context.Context) error {
+				var err error
+				data, err = s.jalankanOperasi(ctx, op)
+				return err
+			}
+
+			var err error
+			if atomic {
+				err = jalankan(ctx)
+			} else {
+				err = s.tx.Savepoint(ctx, jalankan)
+			}
+			if errors.Is(err, domain.ErrKonflikTransaksi) {
+				return err // seluruh transaksi diulang oleh TxManager
+			}
+			if err != nil {
+				hasil.Hasil[i].Status, hasil.Hasil[i].Err = domain.StatusGagal, err
+				hasil.Gagal++
+				if atomic {
+					return errBatchDibatalkan
+				}
+				continue
+			}
+			hasil.Hasil[i].Status, hasil.Hasil[i].Data = domain.StatusBerhasil, data
+			hasil.Berhasil++
+		}
+		return nil
+	})
+
+	if errors.Is(err, errBatchDibatalkan) {
+		// Operasi yang sempat berhasil ikut di-rollback
+		for i := range hasil.Hasil {
+			if hasil.Hasil[i].Status == domain.StatusBerhasil {
+				hasil.Hasil[i].Status, hasil.Hasil[i].Data = domain.StatusDibatalkan, nil
+			}
+		}
+		hasil.Berhasil = 0
+		return hasil, nil
+	}
+	if err != nil {
+		return domain.HasilBatch{}, wrapRepoError(err, "gagal menjalankan batch")
+	}
+	hasil.Committed = true
+	if hasil.Berhasil > 0 {
+		s.model.usang()
+	}
+	return hasil, nil
+}
+
+// jalankanOperasi menjalankan satu operasi batch lewat method service biasa.
+// Data yang dikembalikan kosong untuk delete.
+func (s *VarietasService) jalankanOperasi(ctx context.Context, op domain.OperasiBatch) (*domain.VarietasPadi, error) {
+	keterangan := "batch"
+	if op.Ref != "" {
+		keterangan += " ref " + op.Ref
+	}
+	ctx = domain.WithKeterangan(ctx, keterangan)
+
+	switch op.Op {
+	case domain.OperasiCreate:
+		created, err := s.TambahkanData(ctx, *op.Data)
+		if err != nil {
+			return nil, err
+		}
+		return &created, nil
+	case domain.OperasiUpdate:
+		data := *op.Data
+		data.ID, data.Versi = op.ID, op.Versi
+		updated, err := s.UbahData(ctx, data)
+		if err != nil {
+			return nil, err
+		}
+		return &updated, nil
+	default: // domain.OperasiDelete, sudah dicek di validasiBatch
+		return nil, s.HapusData(ctx, op.ID, op.Versi)
+	}
+}
+
+// validasiBatch memeriksa bentuk setiap operasi sebelum transaksi dimulai.
+// Operasi yang bentuknya salah menandakan bug di client, jadi seluruh batch ditolak.
+func validasiBatch(ops []domain.OperasiBatch) error {
+	if len(ops) == 0 {
+		return domain.NewValidationError("batch tidak berisi operasi",
+			domain.FieldError{Field: "operations", Code: "required", Message: "minimal satu operasi"})
+	}
+	if len(ops) > MaksOperasiBatch {
+		return domain.NewValidationError("batch terlalu besar",
+			domain.FieldError{Field: "operations", Code: "max", Message: fmt.Sprintf("maksimal %d operasi", MaksOperasiBatch)})
+	}
+
+	var fields []domain.FieldError
+	for i, op := range ops {
+		prefix := fmt.Sprintf("operations[%d].", i)
+		switch op.Op {
+		case domain.OperasiCreate, domain.OperasiUpdate, domain.OperasiDelete:
+		default:
+			fields = append(fields, domain.FieldError{Field: prefix + "op", Code: "one_of", Message: "harus create, update, atau delete"})
+			continue
+		}
+		if op.Op != domain.OperasiCreate && op.ID <= 0 {
+			fields = append(fields, domain.FieldError{Field: prefix + "id", Code: "required", Message: "wajib diisi bilangan bulat positif untuk " + op.Op})
+		}
+		if op.Op != domain.OperasiDelete && op.Data == nil {
+			fields = append(fields, domain.FieldError{Field: prefix + "data", Code: "required", Message: "wajib diisi untuk " + op.Op})
+		}
+	}
+	if len(fields) > 0 {
+		return domain.NewValidationError("operasi batch tidak valid", fields...)
 	}
-	return err
+	return nil
 }
 
 // --- IMPLEMENTASI FUNCTIONAL PROGRAMMING (FP) ---
@@ -103,11 +690,13 @@ func FilterData(varietas []domain.VarietasPadi, p VarietasPredicate) []domain.Va
 func (s *VarietasService) DapatkanVarietasBijiPanjang(ctx context.Context) ([]domain.VarietasPadi, error) {
 	semuaVarietas, err := s.repo.FindAll(ctx)
 	if err != nil {
-		return nil, err
+		return nil, wrapRepoError(err, "gagal mengambil data varietas")
 	}
 
+	// Biji panjang = kategori "panjang" atau "sangat_panjang" menurut tabel ambang yang aktif
 	isBijiPanjang := func(v domain.VarietasPadi) bool {
-		return v.PanjangBijiMM > 7.0
+		k := v.KategoriPanjang()
+		return k == "panjang" || k == "sangat_panjang"
 	}
 
 	hasilFilter := FilterData(semuaVarietas, isBijiPanjang)
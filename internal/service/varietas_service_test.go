@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/domain"
+	"github.com/Farewellez/REST-API_VarietasPadi/internal/repository"
+)
+
+// newServiceUji membuat VarietasService (tanpa kebijakan akses) di atas repository in-memory
+func newServiceUji(mode domain.ModeDuplikat) *VarietasService {
+	repo := repository.NewMemoryVarietasRepository()
+	return NewVarietasService(repo, repository.NewMemoryRiwayatRepository(),
+		repository.NewMemoryKosakataRepository(repo), repository.NewMemoryTxManager(),
+		repository.NewMemoryKualitasRepository(), repository.NewMemoryPenggabunganRepository(),
+		mode).(*VarietasService)
+}
+
+// siapkanBatch membuat service dengan dua data awal (id 1 dan 2, versi 1) beserta
+// batch berisi operasi yang berhasil dan gagal berselang-seling
+func siapkanBatch(t *testing.T) (*VarietasService, []domain.OperasiBatch) {
+	t.Helper()
+	svc := newServiceUji(domain.ModeDuplikatNonaktif)
+	ctx := context.Background()
+	for range 2 {
+		if _, err := svc.TambahkanData(ctx, contohVarietas()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	baru, ubah := contohVarietas(), contohVarietas()
+	baru.VarietasKelas = "Ciherang"
+	ubah.Warna = "Coklat"
+	return svc, []domain.OperasiBatch{
+		{Op: domain.OperasiCreate, Ref: "baru", Data: &baru},
+		{Op: domain.OperasiUpdate, Ref: "ubah-1", ID: 1, Versi: 1, Data: &ubah},
+		{Op: domain.OperasiUpdate, Ref: "ubah-2", ID: 2, Versi: 99, Data: &ubah}, // versi usang
+		{Op: domain.OperasiDelete, Ref: "hapus-2", ID: 2},
+		{Op: domain.OperasiDelete, Ref: "hapus-999", ID: 999}, // tidak ada
+	}
+}
+
+// periksaHasilBatch memastikan Index, Ref, Op, dan Status setiap hasil sesuai urutan ops
+func periksaHasilBatch(t *testing.T, hasil domain.HasilBatch, ops []domain.OperasiBatch, status []string) {
+	t.Helper()
+	if len(hasil.Hasil) != len(ops) {
+		t.Fatalf("jumlah hasil = %d, ingin %d", len(hasil.Hasil), len(ops))
+	}
+	for i, h := range hasil.Hasil {
+		if h.Index != i || h.Ref != ops[i].Ref || h.Op != ops[i].Op {
+			t.Errorf("hasil[%d] = {Index %d, Ref %q, Op %q}, ingin {%d, %q, %q}", i, h.Index, h.Ref, h.Op, i, ops[i].Ref, ops[i].Op)
+		}
+		if h.Status != status[i] {
+			t.Errorf("hasil[%d].Status = %q, ingin %q (err %v)", i, h.Status, status[i], h.Err)
+		}
+		if (h.Err != nil) != (status[i] == domain.StatusGagal) {
+			t.Errorf("hasil[%d].Err = %v dengan status %q", i, h.Err, h.Status)
+		}
+	}
+}
+
+func TestJalankanBatchNonAtomic(t *testing.T) {
+	svc, ops := siapkanBatch(t)
+	ctx := context.Background()
+
+	hasil, err := svc.JalankanBatch(ctx, ops, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !hasil.Committed || hasil.Atomic || hasil.Berhasil != 3 || hasil.Gagal != 2 {
+		t.Errorf("hasil = {Committed %v, Atomic %v, Berhasil %d, Gagal %d}, ingin {true, false, 3, 2}",
+			hasil.Committed, hasil.Atomic, hasil.Berhasil, hasil.Gagal)
+	}
+	periksaHasilBatch(t, hasil, ops, []string{
+		domain.StatusBerhasil, domain.StatusBerhasil, domain.StatusGagal, domain.StatusBerhasil, domain.StatusGagal,
+	})
+	if err := hasil.Hasil[2].Err; !errors.Is(err, domain.ErrPreconditionFailed) {
+		t.Errorf("hasil[2].Err = %v, ingin ErrPreconditionFailed", err)
+	}
+	if err := hasil.Hasil[4].Err; !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("hasil[4].Err = %v, ingin ErrNotFound", err)
+	}
+	if d := hasil.Hasil[0].Data; d == nil || d.ID != 3 || d.VarietasKelas != "Ciherang" {
+		t.Errorf("hasil[0].Data = %+v, ingin data baru id 3", d)
+	}
+	if d := hasil.Hasil[1].Data; d == nil || d.Versi != 2 || d.Warna != "Coklat" {
+		t.Errorf("hasil[1].Data = %+v, ingin data id 1 versi 2", d)
+	}
+
+	// Operasi yang berhasil tetap tersimpan walaupun operasi lain gagal
+	if v, err := svc.DapatkanDataByID(ctx, 3); err != nil || v.VarietasKelas != "Ciherang" {
+		t.Errorf("data baru = %+v, %v", v, err)
+	}
+	if v, err := svc.DapatkanDataByID(ctx, 1); err != nil || v.Versi != 2 || v.Warna != "Coklat" {
+		t.Errorf("data id 1 = %+v, %v, ingin versi 2 berwarna Coklat", v, err)
+	}
+	if _, err := svc.DapatkanDataByID(ctx, 2); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("data id 2 setelah dihapus: err = %v, ingin ErrNotFound", err)
+	}
+	if r, err := svc.RiwayatData(ctx, 1); err != nil || len(r) != 2 {
+		t.Errorf("riwayat id 1 berisi %d revisi (err %v), ingin 2", len(r), err)
+	}
+}
+
+func TestJalankanBatchAtomic(t *testing.T) {
+	svc, ops := siapkanBatch(t)
+	ctx := context.Background()
+
+	hasil, err := svc.JalankanBatch(ctx, ops, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hasil.Committed || !hasil.Atomic || hasil.Berhasil != 0 || hasil.Gagal != 1 {
+		t.Errorf("hasil = {Committed %v, Atomic %v, Berhasil %d, Gagal %d}, ingin {false, true, 0, 1}",
+			hasil.Committed, hasil.Atomic, hasil.Berhasil, hasil.Gagal)
+	}
+	// Operasi sebelum kegagalan di-rollback, operasi sesudahnya tidak dijalankan
+	periksaHasilBatch(t, hasil, ops, []string{
+		domain.StatusDibatalkan, domain.StatusDibatalkan, domain.StatusGagal, domain.StatusDibatalkan, domain.StatusDibatalkan,
+	})
+	for i, h := range hasil.Hasil {
+		if h.Data != nil {
+			t.Errorf("hasil[%d].Data = %+v, ingin nil karena di-rollback", i, h.Data)
+		}
+	}
+
+	if _, err := svc.DapatkanDataByID(ctx, 3); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("data baru masih ada setelah rollback: err = %v", err)
+	}
+	for _, id := range []int{1, 2} {
+		v, err := svc.DapatkanDataByID(ctx, id)
+		if err != nil || v.Versi != 1 || v.Warna != "Putih" {
+			t.Errorf("data id %d = %+v, %v, ingin tidak berubah", id, v, err)
+		}
+		if r, err := svc.RiwayatData(ctx, id); err != nil || len(r) != 1 {
+			t.Errorf("riwayat id %d berisi %d revisi (err %v), ingin 1", id, len(r), err)
+		}
+	}
+	if page, err := svc.DapatkanSemuaData(ctx, domain.VarietasQuery{}); err != nil || page.Total != 2 {
+		t.Errorf("total data = %d (err %v), ingin 2", page.Total, err)
+	}
+}